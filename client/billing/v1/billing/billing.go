@@ -0,0 +1,219 @@
+package billing
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	flavorclient "github.com/G-Core/gcorelabscloud-go/client/flavors/v1/client"
+	floatingipclient "github.com/G-Core/gcorelabscloud-go/client/floatingips/v1/client"
+	gpuclient "github.com/G-Core/gcorelabscloud-go/client/gpu/v3/client"
+	instanceclient "github.com/G-Core/gcorelabscloud-go/client/instances/v1/client"
+	lbclient "github.com/G-Core/gcorelabscloud-go/client/loadbalancers/v1/client"
+	"github.com/G-Core/gcorelabscloud-go/client/utils"
+	volumeclient "github.com/G-Core/gcorelabscloud-go/client/volumes/v1/client"
+	"github.com/G-Core/gcorelabscloud-go/gcore/billing/v1/billing"
+	"github.com/shopspring/decimal"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	breakdowns    = []string{"type", "tag", "metadata", "items"}
+	resourceTypes = []string{
+		string(billing.ResourceInstance),
+		string(billing.ResourceBaremetal),
+		string(billing.ResourceGPUServer),
+		string(billing.ResourceVolume),
+		string(billing.ResourceFloatingIP),
+		string(billing.ResourceLoadBalancer),
+	}
+)
+
+func buildClients(c *cli.Context, resources []string) (billing.Clients, error) {
+	var (
+		clients billing.Clients
+		err     error
+	)
+	build := func(builder func(*cli.Context) (*gcorecloud.ServiceClient, error), client **gcorecloud.ServiceClient) {
+		if err == nil {
+			*client, err = builder(c)
+		}
+	}
+	for _, resource := range resources {
+		switch billing.ResourceType(resource) {
+		case billing.ResourceInstance:
+			build(instanceclient.NewInstanceClientV1, &clients.Instances)
+			build(flavorclient.NewFlavorClientV1, &clients.Flavors)
+		case billing.ResourceBaremetal:
+			build(instanceclient.NewBmInstanceClientV1, &clients.BaremetalInstances)
+			build(flavorclient.NewBmFlavorClientV1, &clients.BaremetalFlavors)
+		case billing.ResourceGPUServer:
+			build(gpuclient.NewGPUVirtualClientV3, &clients.GPUVirtual)
+			build(gpuclient.NewGPUBaremetalClientV3, &clients.GPUBaremetal)
+		case billing.ResourceVolume:
+			build(volumeclient.NewVolumeClientV1, &clients.Volumes)
+		case billing.ResourceFloatingIP:
+			build(floatingipclient.NewFloatingIPClientV1, &clients.FloatingIPs)
+		case billing.ResourceLoadBalancer:
+			build(lbclient.NewLoadbalancerClientV1, &clients.LoadBalancers)
+			build(lbclient.NewLBFlavorClientV1, &clients.LBFlavors)
+		default:
+			return clients, fmt.Errorf("unknown resource type %s, use %s", resource, strings.Join(resourceTypes, ", "))
+		}
+	}
+	return clients, err
+}
+
+func formatPrice(price *decimal.Decimal) string {
+	if price == nil {
+		return ""
+	}
+	return price.StringFixed(2)
+}
+
+func writeCostsCSV(out io.Writer, costs []billing.Cost) error {
+	w := csv.NewWriter(out)
+	_ = w.Write([]string{"key", "count", "unpriced", "price_per_month"})
+	for _, cost := range costs {
+		price := cost.PricePerMonth
+		_ = w.Write([]string{cost.Key, strconv.Itoa(cost.Count), strconv.Itoa(cost.Unpriced), formatPrice(&price)})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func writeItemsCSV(out io.Writer, items []billing.Item) error {
+	w := csv.NewWriter(out)
+	_ = w.Write([]string{"type", "id", "name", "flavor", "size", "price_per_month", "tags"})
+	for _, item := range items {
+		size := ""
+		if item.Size > 0 {
+			size = strconv.Itoa(item.Size)
+		}
+		_ = w.Write([]string{
+			string(item.Type), item.ID, item.Name, item.Flavor, size, formatPrice(item.PricePerMonth), strings.Join(item.Tags, ";"),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+var estimateSubCommand = cli.Command{
+	Name:     "estimate",
+	Usage:    "Estimate monthly cost of project resources",
+	Category: "cost",
+	Flags: []cli.Flag{
+		&cli.GenericFlag{
+			Name: "by",
+			Value: &utils.EnumValue{
+				Enum:    breakdowns,
+				Default: breakdowns[0],
+			},
+			Usage:    fmt.Sprintf("cost breakdown. output in %s", strings.Join(breakdowns, ", ")),
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "metadata-key",
+			Usage:    "metadata key to break the cost down by with --by metadata",
+			Required: false,
+		},
+		&cli.StringSliceFlag{
+			Name:     "resource",
+			Usage:    fmt.Sprintf("resource types to estimate. Defaults to all of %s", strings.Join(resourceTypes, ", ")),
+			Required: false,
+		},
+		&cli.StringSliceFlag{
+			Name:     "volume-price",
+			Usage:    "volume price per GiB and month as <volume_type>=<price> or <price> for all types. Volumes are unpriced without it",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "floating-ip-price",
+			Usage:    "floating IP price per month. Floating IPs are unpriced without it",
+			Required: false,
+		},
+		&cli.BoolFlag{
+			Name:     "csv",
+			Usage:    "output CSV instead of --format",
+			Required: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		by := c.String("by")
+		if by == "metadata" && c.String("metadata-key") == "" {
+			_ = cli.ShowCommandHelp(c, "estimate")
+			return cli.NewExitError(fmt.Errorf("--metadata-key is required with --by metadata"), 1)
+		}
+		var (
+			rates billing.Rates
+			err   error
+		)
+		if rates.VolumePerGiBMonth, err = billing.ParseVolumeRates(c.StringSlice("volume-price")); err != nil {
+			_ = cli.ShowCommandHelp(c, "estimate")
+			return cli.NewExitError(err, 1)
+		}
+		if c.String("floating-ip-price") != "" {
+			price, err := decimal.NewFromString(c.String("floating-ip-price"))
+			if err != nil {
+				_ = cli.ShowCommandHelp(c, "estimate")
+				return cli.NewExitError(fmt.Errorf("invalid floating IP price %s", c.String("floating-ip-price")), 1)
+			}
+			rates.FloatingIPPerMonth = &price
+		}
+		resources := c.StringSlice("resource")
+		if len(resources) == 0 {
+			resources = resourceTypes
+		}
+		clients, err := buildClients(c, resources)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+
+		items, err := billing.Collect(clients, rates)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if by == "items" {
+			if c.Bool("csv") {
+				if err := writeItemsCSV(os.Stdout, items); err != nil {
+					return cli.NewExitError(err, 1)
+				}
+				return nil
+			}
+			utils.ShowResults(items, c.String("format"))
+			return nil
+		}
+
+		var costs []billing.Cost
+		switch by {
+		case "tag":
+			costs = billing.ByTag(items)
+		case "metadata":
+			costs = billing.ByMetadata(items, c.String("metadata-key"))
+		default:
+			costs = billing.ByType(items)
+		}
+		costs = append(costs, billing.Total(items))
+		if c.Bool("csv") {
+			if err := writeCostsCSV(os.Stdout, costs); err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			return nil
+		}
+		utils.ShowResults(costs, c.String("format"))
+		return nil
+	},
+}
+
+var Commands = cli.Command{
+	Name:  "cost",
+	Usage: "GCloud project cost",
+	Subcommands: []*cli.Command{
+		&estimateSubCommand,
+	},
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
-func buildTokenClient(c *cli.Context, endpointName, endpointType string, version string) (*gcorecloud.ServiceClient, error) {
+func buildTokenClient(c *cli.Context, endpointName, endpointType string, version string, region int) (*gcorecloud.ServiceClient, error) {
 	settings, err := gcore.NewGCloudTokenAPISettingsFromEnv()
 	if err != nil {
 		return nil, err
@@ -36,7 +36,6 @@ func buildTokenClient(c *cli.Context, endpointName, endpointType string, version
 		settings.APIURL = url
 	}
 
-	region := c.Int("region")
 	if region != 0 {
 		settings.Region = region
 	}
@@ -64,7 +63,7 @@ func buildTokenClient(c *cli.Context, endpointName, endpointType string, version
 	return gcore.TokenClientServiceWithDebug(options, eo, settings.Debug)
 }
 
-func buildAPITokenClient(c *cli.Context, endpointName, endpointType string, version string) (*gcorecloud.ServiceClient, error) {
+func buildAPITokenClient(c *cli.Context, endpointName, endpointType string, version string, region int) (*gcorecloud.ServiceClient, error) {
 	settings, err := gcore.NewGCloudAPITokenAPISettingsFromEnv()
 	if err != nil {
 		return nil, err
@@ -87,7 +86,6 @@ func buildAPITokenClient(c *cli.Context, endpointName, endpointType string, vers
 		settings.APIURL = url
 	}
 
-	region := c.Int("region")
 	if region != 0 {
 		settings.Region = region
 	}
@@ -115,7 +113,7 @@ func buildAPITokenClient(c *cli.Context, endpointName, endpointType string, vers
 	return gcore.APITokenClientServiceWithDebug(options, eo, settings.Debug)
 }
 
-func buildPlatformClient(c *cli.Context, endpointName, endpointType string, version string) (*gcorecloud.ServiceClient, error) {
+func buildPlatformClient(c *cli.Context, endpointName, endpointType string, version string, region int) (*gcorecloud.ServiceClient, error) {
 	settings, err := gcore.NewGCloudPlatformAPISettingsFromEnv()
 	if err != nil {
 		return nil, err
@@ -143,7 +141,6 @@ func buildPlatformClient(c *cli.Context, endpointName, endpointType string, vers
 		settings.APIURL = url
 	}
 
-	region := c.Int("region")
 	if region != 0 {
 		settings.Region = region
 	}
@@ -173,6 +170,11 @@ func buildPlatformClient(c *cli.Context, endpointName, endpointType string, vers
 }
 
 func BuildClient(c *cli.Context, endpointName, version string) (*gcorecloud.ServiceClient, error) {
+	return BuildRegionClient(c, endpointName, version, c.Int("region"))
+}
+
+// BuildRegionClient builds a client for the given region, other settings are taken from the context as in BuildClient.
+func BuildRegionClient(c *cli.Context, endpointName, version string, region int) (*gcorecloud.ServiceClient, error) {
 	clientType := flags.ClientType
 	if clientType == "" {
 		clientType = c.String("client-type")
@@ -180,11 +182,11 @@ func BuildClient(c *cli.Context, endpointName, version string) (*gcorecloud.Serv
 
 	switch clientType {
 	case "token":
-		return buildTokenClient(c, endpointName, "", version)
+		return buildTokenClient(c, endpointName, "", version, region)
 	case "api-token":
-		return buildAPITokenClient(c, endpointName, "", version)
+		return buildAPITokenClient(c, endpointName, "", version, region)
 	default:
-		return buildPlatformClient(c, endpointName, "", version)
+		return buildPlatformClient(c, endpointName, "", version, region)
 	}
 }
 
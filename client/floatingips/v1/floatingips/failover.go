@@ -0,0 +1,99 @@
+package floatingips
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/G-Core/gcorelabscloud-go/client/flags"
+	"github.com/G-Core/gcorelabscloud-go/client/floatingips/v1/client"
+	"github.com/G-Core/gcorelabscloud-go/client/utils"
+	"github.com/G-Core/gcorelabscloud-go/gcore/floatingip/v1/floatingips"
+	"github.com/urfave/cli/v2"
+)
+
+var floatingIPFailoverSubCommand = cli.Command{
+	Name:      "failover",
+	Usage:     "Move floating ip from the primary port to the standby port",
+	ArgsUsage: "<floatingip_id>",
+	Category:  "floatingip",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "from-port",
+			Usage: "primary port id. The floating ip is not moved if it is assigned to another port",
+		},
+		&cli.StringFlag{
+			Name:     "to-port",
+			Usage:    "standby port id",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "watch",
+			Usage: "host:port of the primary instance to health check over TCP. Fails over when the checks fail",
+		},
+		&cli.DurationFlag{
+			Name:  "interval",
+			Usage: "time between health checks with --watch",
+			Value: 5 * time.Second,
+		},
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "health check connection timeout with --watch",
+			Value: 2 * time.Second,
+		},
+		&cli.IntFlag{
+			Name:  "failures",
+			Usage: "consecutive failed health checks to fail over after with --watch",
+			Value: 3,
+		},
+		&cli.IntFlag{
+			Name:  "wait-seconds",
+			Usage: "Required amount of time in seconds to wait while the floating ip is being moved",
+			Value: 300,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		floatingIPID, err := flags.GetFirstStringArg(c, floatingIPIDText)
+		if err != nil {
+			_ = cli.ShowCommandHelp(c, "failover")
+			return err
+		}
+		if c.String("from-port") == c.String("to-port") {
+			_ = cli.ShowCommandHelp(c, "failover")
+			return cli.NewExitError(fmt.Errorf("--from-port and --to-port should differ"), 1)
+		}
+		client, err := client.NewFloatingIPClientV1(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+
+		if address := c.String("watch"); address != "" {
+			opts := floatingips.WatchOpts{
+				Interval: c.Duration("interval"),
+				Timeout:  c.Duration("timeout"),
+				Failures: c.Int("failures"),
+			}
+			if err := opts.Validate(); err != nil {
+				_ = cli.ShowCommandHelp(c, "failover")
+				return cli.NewExitError(err, 1)
+			}
+			fmt.Fprintf(os.Stderr, "watching %s\n", address)
+			err := floatingips.WatchTCP(c.Context, address, opts)
+			if c.Context.Err() != nil {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "%s, failing over\n", err)
+		}
+
+		ctx, cancel := context.WithTimeout(c.Context, time.Duration(c.Int("wait-seconds"))*time.Second)
+		defer cancel()
+		floatingIP, err := floatingips.Failover(ctx, client, floatingIPID, c.String("from-port"), c.String("to-port"))
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		utils.ShowResults(floatingIP, c.String("format"))
+		return nil
+	},
+}
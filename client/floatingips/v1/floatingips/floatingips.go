@@ -249,6 +249,7 @@ var Commands = cli.Command{
 		&floatingIPGetSubCommand,
 		&floatingIPAssignSubCommand,
 		&floatingIPUnAssignSubCommand,
+		&floatingIPFailoverSubCommand,
 		&floatingIPDeleteSubCommand,
 		&floatingIPCreateSubCommand,
 		&availablefloatingips.AvailableFloatingIPCommands,
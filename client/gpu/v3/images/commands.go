@@ -30,7 +30,23 @@ var imageUploadFlags = []cli.Flag{
 	&cli.StringFlag{
 		Name:     "url",
 		Usage:    "Image URL",
-		Required: true,
+		Required: false,
+	},
+	&cli.StringFlag{
+		Name:     "file",
+		Usage:    "Local image file. It is served on --listen while the API downloads it from --public-url",
+		Required: false,
+	},
+	&cli.StringFlag{
+		Name:     "listen",
+		Usage:    "Address to serve the local image file on",
+		Value:    ":8080",
+		Required: false,
+	},
+	&cli.StringFlag{
+		Name:     "public-url",
+		Usage:    "URL of the --listen address reachable by the API, i.e. http://203.0.113.10:8080",
+		Required: false,
 	},
 	&cli.StringFlag{
 		Name:     "name",
@@ -92,7 +108,7 @@ var baremetalCommand = cli.Command{
 				{
 					Name:        "upload",
 					Usage:       "Upload baremetal GPU image",
-					Description: "Upload a new baremetal GPU image with the specified URL and name",
+					Description: "Upload a new baremetal GPU image with the specified name from a URL or a local file",
 					Category:    "images",
 					ArgsUsage:   " ",
 					Flags:       append(imageUploadFlags, flags.WaitCommandFlags...),
@@ -141,7 +157,7 @@ var virtualCommand = cli.Command{
 				{
 					Name:        "upload",
 					Usage:       "Upload virtual GPU image",
-					Description: "Upload a new virtual GPU image with the specified URL and name",
+					Description: "Upload a new virtual GPU image with the specified name from a URL or a local file",
 					Category:    "images",
 					ArgsUsage:   " ",
 					Flags:       append(imageUploadFlags, flags.WaitCommandFlags...),
@@ -191,9 +207,13 @@ func uploadImageAction(c *cli.Context, newClient func(*cli.Context) (*gcorecloud
 	}
 
 	// Only validate if not showing help
-	if !c.Bool("help") && (c.String("url") == "" || c.String("name") == "") {
+	if !c.Bool("help") && c.String("name") == "" {
+		_ = cli.ShowCommandHelp(c, "")
+		return cli.Exit("Required flag 'name' must be set", 1)
+	}
+	if !c.Bool("help") && (c.String("url") == "") == (c.String("file") == "") {
 		_ = cli.ShowCommandHelp(c, "")
-		return cli.Exit("Required flags 'url' and 'name' must be set", 1)
+		return cli.Exit("Either 'url' or 'file' flag must be set", 1)
 	}
 
 	gpuClient, err := newClient(c)
@@ -231,6 +251,15 @@ func uploadImageAction(c *cli.Context, newClient func(*cli.Context) (*gcorecloud
 		opts.Metadata = metadataInterface
 	}
 
+	taskClient, err := taskclient.NewTaskClientV1(c)
+	if err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	if c.String("file") != "" {
+		return uploadFile(c, gpuClient, taskClient, opts)
+	}
+
 	results := images.UploadImage(gpuClient, opts)
 	if results.Err != nil {
 		return cli.Exit(results.Err, 1)
@@ -241,11 +270,6 @@ func uploadImageAction(c *cli.Context, newClient func(*cli.Context) (*gcorecloud
 		return cli.Exit(err, 1)
 	}
 
-	taskClient, err := taskclient.NewTaskClientV1(c)
-	if err != nil {
-		return cli.Exit(err, 1)
-	}
-
 	return utils.WaitTaskAndShowResult(c, taskClient, taskID, true, func(task tasks.TaskID) (interface{}, error) {
 		return task, nil
 	})
@@ -371,7 +395,7 @@ func BaremetalCommands() *cli.Command {
 			{
 				Name:        "upload",
 				Usage:       "Upload baremetal GPU image",
-				Description: "Upload a new baremetal GPU image with the specified URL and name",
+				Description: "Upload a new baremetal GPU image with the specified name from a URL or a local file",
 				Category:    "images",
 				ArgsUsage:   " ",
 				Flags:       append(imageUploadFlags, flags.WaitCommandFlags...),
@@ -416,7 +440,7 @@ func VirtualCommands() *cli.Command {
 			{
 				Name:        "upload",
 				Usage:       "Upload virtual GPU image",
-				Description: "Upload a new virtual GPU image with the specified URL and name",
+				Description: "Upload a new virtual GPU image with the specified name from a URL or a local file",
 				Category:    "images",
 				ArgsUsage:   " ",
 				Flags:       append(imageUploadFlags, flags.WaitCommandFlags...),
@@ -0,0 +1,49 @@
+package images
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	v1images "github.com/G-Core/gcorelabscloud-go/client/images/v1/images"
+	"github.com/G-Core/gcorelabscloud-go/client/utils"
+	"github.com/G-Core/gcorelabscloud-go/gcore/gpu/v3/images"
+)
+
+// uploadFile serves the --file image on --listen for the API and shows the uploaded GPU image.
+func uploadFile(c *cli.Context, gpuClient, taskClient *gcorecloud.ServiceClient, opts images.ImageOpts) error {
+	if c.String("public-url") == "" {
+		_ = cli.ShowCommandHelp(c, "upload")
+		return cli.Exit(fmt.Errorf("--public-url is required to upload a local file"), 1)
+	}
+	listener, err := net.Listen("tcp", c.String("listen"))
+	if err != nil {
+		return cli.Exit(fmt.Errorf("cannot listen on %s: %w", c.String("listen"), err), 1)
+	}
+	result, err := images.UploadFile(gpuClient, taskClient, images.UploadFileOpts{
+		ImageOpts:   opts,
+		Path:        c.String("file"),
+		Listener:    listener,
+		PublicURL:   c.String("public-url"),
+		WaitSeconds: c.Int("wait-seconds"),
+		Hashing:     v1images.ProgressPrinter("checksum"),
+		Sending:     v1images.ProgressPrinter("upload"),
+		Warn: func(warning string) {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		},
+	})
+	if err != nil {
+		return cli.Exit(err, 1)
+	}
+	fmt.Fprintf(os.Stderr, "%s md5 %s sha256 %s\n", result.Local.Path, result.Local.MD5, result.Local.SHA256)
+
+	image, err := images.Get(gpuClient, result.ImageID).Extract()
+	if err != nil {
+		return cli.Exit(fmt.Errorf("cannot get image with ID: %s. Error: %w", result.ImageID, err), 1)
+	}
+	utils.ShowResults(image, c.String("format"))
+	return nil
+}
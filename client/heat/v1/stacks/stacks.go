@@ -1,8 +1,11 @@
 package stacks
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/G-Core/gcorelabscloud-go/client/flags"
 	"github.com/G-Core/gcorelabscloud-go/client/heat/v1/client"
@@ -173,6 +176,16 @@ var stackUpdateSubCommand = cli.Command{
 			Usage:    "use path method. template is not mandatory",
 			Required: false,
 		},
+		&cli.BoolFlag{
+			Name:     "dry-run",
+			Usage:    "show resources to be added, replaced, updated and deleted without updating the stack",
+			Required: false,
+		},
+		&cli.BoolFlag{
+			Name:     "show-unchanged",
+			Usage:    "show unchanged resources in dry run",
+			Required: false,
+		},
 	},
 	Action: func(c *cli.Context) error {
 		stackID, err := flags.GetFirstStringArg(c, stackIDText)
@@ -221,6 +234,21 @@ var stackUpdateSubCommand = cli.Command{
 
 		opts.Parameters = params
 
+		if c.Bool("dry-run") {
+			var result stacks.PreviewResult
+			if c.Bool("patch") {
+				result = stacks.UpdatePatchPreview(client, stackID, opts)
+			} else {
+				result = stacks.UpdatePreview(client, stackID, opts)
+			}
+			changes, err := result.Extract()
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			utils.ShowResults(changes.Changes(c.Bool("show-unchanged")), c.String("format"))
+			return nil
+		}
+
 		if c.Bool("patch") {
 			err = stacks.UpdatePatch(client, stackID, opts).ExtractErr()
 		} else {
@@ -233,12 +261,197 @@ var stackUpdateSubCommand = cli.Command{
 	},
 }
 
+var stackCreateSubCommand = cli.Command{
+	Name:     "create",
+	Usage:    "Create heat stack",
+	Category: "stack",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "name",
+			Aliases:  []string{"n"},
+			Usage:    "stack name",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:     "template",
+			Usage:    "stack template yaml file",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:     "environment",
+			Usage:    "stack environment yaml file",
+			Required: false,
+		},
+		&cli.StringSliceFlag{
+			Name:     "parameter",
+			Usage:    "stack parameters. Example: --parameter one=two --parameter three=four",
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "timeout",
+			Usage:    "stack creation timeout in minutes",
+			Required: false,
+		},
+		&cli.BoolFlag{
+			Name:     "disable-rollback",
+			Usage:    "keep stack resources when stack creation fails",
+			Required: false,
+		},
+		&cli.BoolFlag{
+			Name:     "wait",
+			Usage:    "tail stack events until stack creation is finished",
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "wait-seconds",
+			Usage:    "time in seconds to tail stack events with --wait",
+			Value:    3600,
+			Required: false,
+		},
+		&cli.DurationFlag{
+			Name:     "interval",
+			Usage:    "stack events polling interval",
+			Value:    5 * time.Second,
+			Required: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		client, err := client.NewHeatClientV1(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+
+		content, err := utils.CheckYamlFile(c.String("template"))
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		template := &stacks.Template{}
+		template.TE = stacks.TE{
+			Bin: content,
+		}
+		opts := stacks.CreateOpts{
+			Name:         c.String("name"),
+			TemplateOpts: template,
+			Timeout:      c.Int("timeout"),
+		}
+		if c.IsSet("disable-rollback") {
+			disableRollback := c.Bool("disable-rollback")
+			opts.DisableRollback = &disableRollback
+		}
+
+		environmentFile := c.String("environment")
+		if environmentFile != "" {
+			content, err := utils.CheckYamlFile(environmentFile)
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			env := &stacks.Environment{}
+			env.TE = stacks.TE{
+				Bin: content,
+			}
+			opts.EnvironmentOpts = env
+		}
+
+		params, err := utils.StringSliceToMapInterface(c.StringSlice("parameter"))
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		opts.Parameters = params
+
+		created, err := stacks.Create(client, opts).Extract()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if !c.Bool("wait") {
+			utils.ShowResults(created, c.String("format"))
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(c.Context, time.Duration(c.Int("wait-seconds"))*time.Second)
+		defer cancel()
+		stack, err := watchStack(ctx, client, created.ID, c.Duration("interval"), os.Stderr)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		utils.ShowResults(stack, c.String("format"))
+		return nil
+	},
+}
+
+var stackValidateSubCommand = cli.Command{
+	Name:     "validate",
+	Usage:    "Validate heat stack template offline",
+	Category: "stack",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "template",
+			Usage:    "stack template yaml file",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:     "environment",
+			Usage:    "stack environment yaml file",
+			Required: false,
+		},
+		&cli.StringSliceFlag{
+			Name:     "parameter",
+			Usage:    "stack parameters. Example: --parameter one=two --parameter three=four",
+			Required: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		content, err := utils.CheckYamlFile(c.String("template"))
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		template := &stacks.Template{}
+		template.TE = stacks.TE{
+			Bin: content,
+		}
+		opts := stacks.CreateOpts{TemplateOpts: template}
+
+		environmentFile := c.String("environment")
+		if environmentFile != "" {
+			content, err := utils.CheckYamlFile(environmentFile)
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			env := &stacks.Environment{}
+			env.TE = stacks.TE{
+				Bin: content,
+			}
+			opts.EnvironmentOpts = env
+		}
+
+		params, err := utils.StringSliceToMapInterface(c.StringSlice("parameter"))
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		opts.Parameters = params
+
+		issues, err := opts.Lint()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if len(issues) > 0 {
+			utils.ShowResults(issues, c.String("format"))
+		}
+		if issues.HasErrors() {
+			return cli.NewExitError(fmt.Errorf("template %s is not valid", c.String("template")), 1)
+		}
+		return nil
+	},
+}
+
 var StackCommands = cli.Command{
 	Name:  "stack",
 	Usage: "Heat stacks commands",
 	Subcommands: []*cli.Command{
+		&stackCreateSubCommand,
 		&stackGetSubCommand,
 		&stackListSubCommand,
 		&stackUpdateSubCommand,
+		&stackValidateSubCommand,
 	},
 }
@@ -0,0 +1,29 @@
+package stacks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/heat/v1/stack/events"
+	"github.com/G-Core/gcorelabscloud-go/gcore/heat/v1/stack/resources"
+	"github.com/G-Core/gcorelabscloud-go/gcore/heat/v1/stack/stacks"
+)
+
+// watchStack prints stack events and resource status transitions to out until the stack settles.
+func watchStack(ctx context.Context, client *gcorecloud.ServiceClient, stackID string, interval time.Duration, out io.Writer) (*stacks.Stack, error) {
+	return events.Watch(ctx, client, stackID, events.WatchOpts{
+		Interval: interval,
+		Event: func(e events.Event) {
+			_, _ = fmt.Fprintf(out, "%s [%s]: %s %s\n", e.EventTime.Format(time.RFC3339), e.ResourceName, e.ResourceStatus, e.ResourceStatusReason)
+		},
+		Transition: func(r resources.ResourceList, previous string) {
+			if previous == "" {
+				previous = "-"
+			}
+			_, _ = fmt.Fprintf(out, "resource %s: %s -> %s\n", r.ResourceName, previous, r.ResourceStatus)
+		},
+	})
+}
@@ -333,7 +333,24 @@ var imageUploadCommand = cli.Command{
 		},
 		&cli.StringFlag{
 			Name:     "url",
-			Required: true,
+			Usage:    "image URL the API downloads the image from",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "file",
+			Usage:    "local image file. It is served on --listen while the API downloads it from --public-url",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "listen",
+			Usage:    "address to serve the local image file on",
+			Value:    ":8080",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "public-url",
+			Usage:    "URL of the --listen address reachable by the API, i.e. http://203.0.113.10:8080",
+			Required: false,
 		},
 		&cli.BoolFlag{
 			Name:  "cow-format",
@@ -388,6 +405,11 @@ var imageUploadCommand = cli.Command{
 			return cli.NewExitError(err, 1)
 		}
 
+		if (c.String("url") == "") == (c.String("file") == "") {
+			_ = cli.ShowCommandHelp(c, "upload")
+			return cli.NewExitError(fmt.Errorf("either --url or --file should be set"), 1)
+		}
+
 		opts := images.UploadOpts{
 			Architecture:   types.ImageArchitectureType(c.String("architecture")),
 			OsVersion:      c.String("os-version"),
@@ -402,6 +424,9 @@ var imageUploadCommand = cli.Command{
 			CowFormat:      c.Bool("cow-format"),
 			Metadata:       metadata,
 		}
+		if c.String("file") != "" {
+			return uploadFile(c, downloadClient, opts)
+		}
 
 		results, err := images.Upload(downloadClient, opts).Extract()
 		if err != nil {
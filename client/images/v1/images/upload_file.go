@@ -0,0 +1,74 @@
+package images
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/client/images/v1/client"
+	"github.com/G-Core/gcorelabscloud-go/client/utils"
+	"github.com/G-Core/gcorelabscloud-go/gcore/image/v1/images"
+	"github.com/urfave/cli/v2"
+)
+
+// ProgressPrinter returns a function printing the progress of the action to stderr.
+func ProgressPrinter(action string) func(done, total int64) {
+	last := int64(-1)
+	return func(done, total int64) {
+		percent := int64(100)
+		if total > 0 {
+			percent = done * 100 / total
+		}
+		if percent == last {
+			return
+		}
+		last = percent
+		fmt.Fprintf(os.Stderr, "\r%s: %d%% (%d/%d bytes)", action, percent, done, total)
+		if done >= total {
+			fmt.Fprintln(os.Stderr)
+		}
+	}
+}
+
+// uploadFile serves the --file image on --listen for the API and shows the uploaded image.
+func uploadFile(c *cli.Context, downloadClient *gcorecloud.ServiceClient, opts images.UploadOpts) error {
+	if c.String("public-url") == "" {
+		_ = cli.ShowCommandHelp(c, "upload")
+		return cli.NewExitError(fmt.Errorf("--public-url is required to upload a local file"), 1)
+	}
+	listener, err := net.Listen("tcp", c.String("listen"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("cannot listen on %s: %w", c.String("listen"), err), 1)
+	}
+	result, err := images.UploadFile(downloadClient, images.UploadFileOpts{
+		UploadOpts:  opts,
+		Path:        c.String("file"),
+		Listener:    listener,
+		PublicURL:   c.String("public-url"),
+		WaitSeconds: c.Int("wait-seconds"),
+		Hashing:     ProgressPrinter("checksum"),
+		Sending:     ProgressPrinter("upload"),
+		Warn: func(warning string) {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		},
+	})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	fmt.Fprintf(os.Stderr, "%s md5 %s sha256 %s\n", result.Local.Path, result.Local.MD5, result.Local.SHA256)
+
+	getClient, err := client.NewImageClientV1(c)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	image, err := images.Get(getClient, result.ImageID).Extract()
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("cannot get image with ID: %s. Error: %w", result.ImageID, err), 1)
+	}
+	if err := images.VerifyUploadedImage(*image, result.Local); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	utils.ShowResults(image, c.String("format"))
+	return nil
+}
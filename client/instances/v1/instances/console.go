@@ -0,0 +1,107 @@
+package instances
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/G-Core/gcorelabscloud-go/client/flags"
+	"github.com/G-Core/gcorelabscloud-go/client/instances/v1/client"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+// parseEscape parses an escape character given as a single character or in caret notation, i.e. ^].
+func parseEscape(s string) (byte, error) {
+	switch {
+	case len(s) == 1:
+		return s[0], nil
+	case len(s) == 2 && s[0] == '^' && s[1] >= '@' && s[1] <= '_':
+		return s[1] & 0x1f, nil
+	}
+	return 0, fmt.Errorf("invalid escape character %s, use a single character or ^X", s)
+}
+
+var instanceConsoleCommand = cli.Command{
+	Name:      "console",
+	Usage:     "Attach the terminal to the instance serial console",
+	ArgsUsage: "<instance_id>",
+	Category:  "instance",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "escape",
+			Aliases:  []string{"e"},
+			Usage:    "escape character which ends the session",
+			Value:    "^]",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "log-file",
+			Usage:    "append the console output to the file",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "origin",
+			Usage:    "websocket origin. Defaults to the console URL host",
+			Required: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		instanceID, err := flags.GetFirstStringArg(c, instanceIDText)
+		if err != nil {
+			_ = cli.ShowCommandHelp(c, "console")
+			return err
+		}
+		escape, err := parseEscape(c.String("escape"))
+		if err != nil {
+			_ = cli.ShowCommandHelp(c, "console")
+			return cli.NewExitError(err, 1)
+		}
+		client, err := client.NewInstanceClientV1(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		console, err := instances.GetInstanceConsole(client, instanceID).Extract()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		conn, err := instances.DialConsole(*console, c.String("origin"))
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+
+		opts := instances.ConsoleSessionOpts{Escape: escape}
+		if c.String("log-file") != "" {
+			log, err := os.OpenFile(c.String("log-file"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+			if err != nil {
+				_ = conn.Close()
+				return cli.NewExitError(err, 1)
+			}
+			defer func() {
+				_, _ = fmt.Fprintf(log, "\n--- instance %s console session ended %s ---\n", instanceID, time.Now().UTC().Format(time.RFC3339))
+				_ = log.Close()
+			}()
+			_, _ = fmt.Fprintf(log, "--- instance %s console session started %s ---\n", instanceID, time.Now().UTC().Format(time.RFC3339))
+			opts.Log = log
+		}
+
+		fd := int(os.Stdin.Fd())
+		if term.IsTerminal(fd) {
+			state, err := term.MakeRaw(fd)
+			if err != nil {
+				_ = conn.Close()
+				return cli.NewExitError(err, 1)
+			}
+			defer func() { _ = term.Restore(fd, state) }()
+		}
+		fmt.Fprintf(os.Stderr, "Connected to instance %s console. Escape character is %s\r\n", instanceID, c.String("escape"))
+		err = instances.AttachConsole(conn, os.Stdin, os.Stdout, opts)
+		fmt.Fprintf(os.Stderr, "\r\nConnection to instance %s console closed.\r\n", instanceID)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return nil
+	},
+}
@@ -48,6 +48,11 @@ var Commands = cli.Command{
 		&instanceSuspendCommand,
 		&instanceResumeCommand,
 		&instanceResizeCommand,
+		&instanceMetricsCommand,
+		&instanceRightsizeCommand,
+		&instanceConsoleCommand,
+		&instanceSSHCommand,
+		&instanceSSHConfigCommand,
 		&instanceCreateBaremetalCommand,
 		{
 			Name:  "interface",
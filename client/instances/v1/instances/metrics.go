@@ -0,0 +1,242 @@
+package instances
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/G-Core/gcorelabscloud-go/client/flags"
+	"github.com/G-Core/gcorelabscloud-go/client/instances/v1/client"
+	"github.com/G-Core/gcorelabscloud-go/client/utils"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/types"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	metricsTimeUnits = []string{string(types.HourMetricsTimeUnit), string(types.DayMetricsTimeUnit)}
+	metricsRenders   = []string{"summary", "csv", "sparkline"}
+	sparkTicks       = []rune("▁▂▃▄▅▆▇█")
+)
+
+// parseSince parses a duration with an additional d (days) unit.
+func parseSince(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil || days < 1 {
+			return 0, fmt.Errorf("invalid duration %s", s)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %s", s)
+	}
+	return d, nil
+}
+
+func metricsTimeInterval(since time.Duration, unit types.MetricsTimeUnit) int {
+	unitDuration := time.Hour
+	if unit == types.DayMetricsTimeUnit {
+		unitDuration = 24 * time.Hour
+	}
+	return int((since + unitDuration - 1) / unitDuration)
+}
+
+func formatMetric(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func writeMetricsCSV(out io.Writer, series []instances.MetricSeries) error {
+	w := csv.NewWriter(out)
+	header := []string{"time"}
+	for _, s := range series {
+		header = append(header, s.Metric)
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	if len(series) > 0 {
+		for i, p := range series[0].Points {
+			row := []string{p.Time.Format(time.RFC3339)}
+			for _, s := range series {
+				value := ""
+				if v := s.Points[i].Value; v != nil {
+					value = formatMetric(*v)
+				}
+				row = append(row, value)
+			}
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// sparkline renders the points scaled between min and max in at most width characters. Gaps are blank.
+func sparkline(points []instances.MetricPoint, stats instances.MetricStats, width int) string {
+	if width <= 0 || len(points) == 0 {
+		return ""
+	}
+	per := int(math.Ceil(float64(len(points)) / float64(width)))
+	var b strings.Builder
+	for start := 0; start < len(points); start += per {
+		end := start + per
+		if end > len(points) {
+			end = len(points)
+		}
+		var sum float64
+		var count int
+		for _, p := range points[start:end] {
+			if p.Value != nil {
+				sum += *p.Value
+				count++
+			}
+		}
+		if count == 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		idx := 0
+		if stats.Max > stats.Min {
+			idx = int((sum/float64(count) - stats.Min) / (stats.Max - stats.Min) * float64(len(sparkTicks)-1))
+		}
+		b.WriteRune(sparkTicks[idx])
+	}
+	return b.String()
+}
+
+func writeMetricsSparklines(out io.Writer, series []instances.MetricSeries, width int) {
+	nameWidth := 0
+	for _, s := range series {
+		if len(s.Metric) > nameWidth {
+			nameWidth = len(s.Metric)
+		}
+	}
+	for _, s := range series {
+		stats := s.Stats()
+		_, _ = fmt.Fprintf(out, "%-*s %s  min %s avg %s max %s p95 %s\n", nameWidth, s.Metric, sparkline(s.Points, stats, width),
+			formatMetric(stats.Min), formatMetric(math.Round(stats.Avg*100)/100), formatMetric(stats.Max), formatMetric(stats.P95))
+	}
+}
+
+var instanceMetricsCommand = cli.Command{
+	Name:      "metrics",
+	Usage:     "Show instance metrics statistics",
+	ArgsUsage: "<instance_id>",
+	Category:  "instance",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "since",
+			Usage:    "time window, i.e. 30m, 6h or 2d",
+			Value:    "1h",
+			Required: false,
+		},
+		&cli.GenericFlag{
+			Name: "unit",
+			Value: &utils.EnumValue{
+				Enum:    metricsTimeUnits,
+				Default: string(types.HourMetricsTimeUnit),
+			},
+			Usage:    fmt.Sprintf("metrics time unit. output in %s", strings.Join(metricsTimeUnits, ", ")),
+			Required: false,
+		},
+		&cli.DurationFlag{
+			Name:     "step",
+			Usage:    "interval samples are aligned to. Defaults to the smallest interval between samples",
+			Required: false,
+		},
+		&cli.StringSliceFlag{
+			Name:     "metric",
+			Aliases:  []string{"m"},
+			Usage:    "show the metric only, i.e. cpu_util or disk_sda_iops_read",
+			Required: false,
+		},
+		&cli.GenericFlag{
+			Name: "render",
+			Value: &utils.EnumValue{
+				Enum:    metricsRenders,
+				Default: metricsRenders[0],
+			},
+			Usage:    fmt.Sprintf("summary uses --format. output in %s", strings.Join(metricsRenders, ", ")),
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "width",
+			Usage:    "sparkline width",
+			Value:    60,
+			Required: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		instanceID, err := flags.GetFirstStringArg(c, instanceIDText)
+		if err != nil {
+			_ = cli.ShowCommandHelp(c, "metrics")
+			return err
+		}
+		since, err := parseSince(c.String("since"))
+		if err != nil {
+			_ = cli.ShowCommandHelp(c, "metrics")
+			return cli.NewExitError(err, 1)
+		}
+		client, err := client.NewInstanceClientV1(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		unit := types.MetricsTimeUnit(c.String("unit"))
+		opts := instances.ListMetricsOpts{TimeUnit: unit, TimeInterval: metricsTimeInterval(since, unit)}
+		if err := opts.Validate(); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		samples, err := instances.ListInstanceMetrics(client, instanceID, opts).Extract()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		from := time.Now().Add(-since)
+		var recent []instances.InstanceMetrics
+		for _, s := range samples {
+			if !s.Time.Before(from) {
+				recent = append(recent, s)
+			}
+		}
+
+		step := c.Duration("step")
+		if step <= 0 {
+			step = instances.MetricsStep(recent)
+		}
+		series := instances.AlignMetrics(recent, step)
+		if c.IsSet("metric") {
+			wanted := make(map[string]bool)
+			for _, m := range c.StringSlice("metric") {
+				wanted[m] = true
+			}
+			var selected []instances.MetricSeries
+			for _, s := range series {
+				if wanted[s.Metric] {
+					selected = append(selected, s)
+				}
+			}
+			series = selected
+		}
+
+		switch c.String("render") {
+		case "csv":
+			if err := writeMetricsCSV(os.Stdout, series); err != nil {
+				return cli.NewExitError(err, 1)
+			}
+		case "sparkline":
+			writeMetricsSparklines(os.Stdout, series, c.Int("width"))
+		default:
+			utils.ShowResults(instances.SummarizeMetrics(series), c.String("format"))
+		}
+		return nil
+	},
+}
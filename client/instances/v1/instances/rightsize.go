@@ -0,0 +1,135 @@
+package instances
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/G-Core/gcorelabscloud-go/client/flags"
+	"github.com/G-Core/gcorelabscloud-go/client/instances/v1/client"
+	"github.com/G-Core/gcorelabscloud-go/client/utils"
+	"github.com/G-Core/gcorelabscloud-go/gcore/flavor/v1/flavors"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/types"
+	"github.com/G-Core/gcorelabscloud-go/gcore/task/v1/tasks"
+	"github.com/urfave/cli/v2"
+)
+
+var instanceRightsizeCommand = cli.Command{
+	Name:      "rightsize",
+	Usage:     "Recommend instance flavors by CPU and memory utilization",
+	ArgsUsage: "[<instance_id>...]",
+	Category:  "instance",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:     "since",
+			Usage:    "utilization time window, i.e. 12h or 14d",
+			Value:    "7d",
+			Required: false,
+		},
+		&cli.Float64Flag{
+			Name:     "low",
+			Usage:    "p95 utilization percent instances are over-provisioned below",
+			Value:    30,
+			Required: false,
+		},
+		&cli.Float64Flag{
+			Name:     "high",
+			Usage:    "p95 utilization percent instances are under-provisioned above",
+			Value:    85,
+			Required: false,
+		},
+		&cli.Float64Flag{
+			Name:     "target",
+			Usage:    "highest p95 utilization percent projected on recommended flavors",
+			Value:    70,
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "min-samples",
+			Usage:    "hourly samples required to give a recommendation",
+			Value:    24,
+			Required: false,
+		},
+		&cli.BoolFlag{
+			Name:     "apply",
+			Usage:    "resize instances to the recommended flavors. Instances are restarted",
+			Required: false,
+		},
+	}, flags.WaitCommandFlags...),
+	Action: func(c *cli.Context) error {
+		since, err := parseSince(c.String("since"))
+		if err != nil {
+			_ = cli.ShowCommandHelp(c, "rightsize")
+			return cli.NewExitError(err, 1)
+		}
+		opts := instances.RightsizeOpts{
+			Low:        c.Float64("low"),
+			High:       c.Float64("high"),
+			Target:     c.Float64("target"),
+			MinSamples: c.Int("min-samples"),
+		}
+		if err := opts.Validate(); err != nil {
+			_ = cli.ShowCommandHelp(c, "rightsize")
+			return cli.NewExitError(err, 1)
+		}
+		client, err := client.NewInstanceClientV1(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+
+		var list []instances.Instance
+		if c.Args().Len() == 0 {
+			if list, err = instances.ListAll(client, nil); err != nil {
+				return cli.NewExitError(err, 1)
+			}
+		}
+		for _, id := range c.Args().Slice() {
+			instance, err := instances.Get(client, id).Extract()
+			if err != nil {
+				return cli.NewExitError(fmt.Errorf("cannot get instance with ID: %s. Error: %w", id, err), 1)
+			}
+			list = append(list, *instance)
+		}
+
+		metricsOpts := instances.ListMetricsOpts{
+			TimeUnit:     types.HourMetricsTimeUnit,
+			TimeInterval: metricsTimeInterval(since, types.HourMetricsTimeUnit),
+		}
+		includePrices := true
+		var recommendations []instances.RightsizeRecommendation
+		for _, instance := range list {
+			metrics, err := instances.ListInstanceMetrics(client, instance.ID, metricsOpts).Extract()
+			if err != nil {
+				return cli.NewExitError(fmt.Errorf("cannot get instance %s metrics: %w", instance.ID, err), 1)
+			}
+			available, err := instances.ListAvailableFlavors(client, instance.ID, flavors.ListOpts{IncludePrices: &includePrices}).Extract()
+			if err != nil {
+				return cli.NewExitError(fmt.Errorf("cannot get instance %s available flavors: %w", instance.ID, err), 1)
+			}
+			recommendations = append(recommendations, instances.Rightsize(instance, metrics, available, opts))
+		}
+
+		if c.Bool("apply") {
+			for _, r := range recommendations {
+				resizeOpts, err := r.ToChangeFlavorOpts()
+				if err != nil {
+					continue
+				}
+				_, _ = fmt.Fprintf(os.Stderr, "resizing instance %s from %s to %s\n", r.InstanceID, r.FlavorID, r.RecommendedFlavorID)
+				results, err := instances.Resize(client, r.InstanceID, resizeOpts).Extract()
+				if err != nil {
+					return cli.NewExitError(fmt.Errorf("cannot resize instance %s: %w", r.InstanceID, err), 1)
+				}
+				if !c.Bool("wait") {
+					continue
+				}
+				if _, err := tasks.WaitForTaskResults(client, results, c.Int("wait-seconds")); err != nil {
+					return cli.NewExitError(fmt.Errorf("cannot resize instance %s: %w", r.InstanceID, err), 1)
+				}
+			}
+		}
+		utils.ShowResults(recommendations, c.String("format"))
+		return nil
+	},
+}
@@ -0,0 +1,225 @@
+package instances
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/client/common"
+	"github.com/G-Core/gcorelabscloud-go/client/flags"
+	"github.com/G-Core/gcorelabscloud-go/client/instances/v1/client"
+	keypairclient "github.com/G-Core/gcorelabscloud-go/client/keypairs/v2/client"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/sshconfig"
+	"github.com/G-Core/gcorelabscloud-go/gcore/keypair/v2/keypairs"
+	"github.com/urfave/cli/v2"
+)
+
+const instanceNameOrIDText = "instance name or ID is mandatory argument"
+
+func defaultKeyDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ssh")
+}
+
+func sshHostFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     "user",
+			Aliases:  []string{"l"},
+			Usage:    "login user. Defaults to the image distribution user",
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "port",
+			Aliases:  []string{"p"},
+			Usage:    "ssh port",
+			Value:    sshconfig.DefaultPort,
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "key-dir",
+			Usage:    "directory searched for private keys matching the instance keypair",
+			Value:    defaultKeyDir(),
+			Required: false,
+		},
+	}
+}
+
+func reachable(port int, timeout time.Duration) func(net.IP) bool {
+	return func(ip net.IP) bool {
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(ip.String(), strconv.Itoa(port)), timeout)
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	}
+}
+
+// getInstance gets an instance by name, the argument is used as instance ID if there is no instance with the name.
+func getInstance(client *gcorecloud.ServiceClient, nameOrID string) (*instances.Instance, error) {
+	id, err := instances.IDFromName(client, nameOrID)
+	if err != nil {
+		if !errors.As(err, &gcorecloud.ErrResourceNotFound{}) {
+			return nil, err
+		}
+		id = nameOrID
+	}
+	return instances.Get(client, id).Extract()
+}
+
+var instanceSSHCommand = cli.Command{
+	Name:      "ssh",
+	Usage:     "Connect to the instance with ssh",
+	ArgsUsage: "<instance_name_or_id> [-- <ssh arguments>]",
+	Category:  "instance",
+	Flags: append(sshHostFlags(),
+		&cli.StringFlag{
+			Name:     "identity",
+			Aliases:  []string{"i"},
+			Usage:    "private key. Defaults to the key in --key-dir matching the instance keypair",
+			Required: false,
+		},
+		&cli.DurationFlag{
+			Name:     "connect-timeout",
+			Usage:    "timeout of fixed address reachability checks",
+			Value:    2 * time.Second,
+			Required: false,
+		},
+		&cli.BoolFlag{
+			Name:     "dry-run",
+			Usage:    "print the ssh command instead of running it",
+			Required: false,
+		},
+	),
+	Action: func(c *cli.Context) error {
+		nameOrID, err := flags.GetFirstStringArg(c, instanceNameOrIDText)
+		if err != nil {
+			_ = cli.ShowCommandHelp(c, "ssh")
+			return err
+		}
+		client, err := client.NewInstanceClientV1(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		instance, err := getInstance(client, nameOrID)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		host, err := sshconfig.NewHost(*instance, sshconfig.Opts{
+			User:      c.String("user"),
+			Port:      c.Int("port"),
+			Reachable: reachable(c.Int("port"), c.Duration("connect-timeout")),
+		})
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+
+		host.IdentityFile = c.String("identity")
+		if host.IdentityFile == "" && instance.KeypairName != "" && c.String("key-dir") != "" {
+			keypairClient, err := keypairclient.NewKeypairClientV2(c)
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			keypair, err := keypairs.Get(keypairClient, instance.KeypairName).Extract()
+			if err == nil {
+				host.IdentityFile, err = sshconfig.MatchPrivateKey(keypair.PublicKey, c.String("key-dir"))
+			}
+			if err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "warning: keypair %s: %s\n", instance.KeypairName, err)
+			}
+		}
+
+		args := append(host.Args(), c.Args().Tail()...)
+		if c.Bool("dry-run") {
+			fmt.Println("ssh " + strings.Join(args, " "))
+			return nil
+		}
+		cmd := exec.Command("ssh", args...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				return cli.NewExitError("", exitErr.ExitCode())
+			}
+			return cli.NewExitError(err, 1)
+		}
+		return nil
+	},
+}
+
+var instanceSSHConfigCommand = cli.Command{
+	Name:     "ssh-config",
+	Usage:    "Generate ssh client configuration of project instances",
+	Category: "instance",
+	Flags: append(sshHostFlags(),
+		&cli.IntSliceFlag{
+			Name:     "regions",
+			Usage:    "region IDs of instances. Defaults to the current region",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "prefix",
+			Usage:    "host alias prefix",
+			Required: false,
+		},
+	),
+	Action: func(c *cli.Context) error {
+		keypairClient, err := keypairclient.NewKeypairClientV2(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		list, err := keypairs.ListAll(keypairClient, nil)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		keys := make(map[string]keypairs.KeyPair, len(list))
+		for _, keypair := range list {
+			keys[keypair.Name] = keypair
+		}
+
+		regions := c.IntSlice("regions")
+		if len(regions) == 0 {
+			regions = []int{c.Int("region")}
+		}
+		var all []instances.Instance
+		for _, region := range regions {
+			client, err := common.BuildRegionClient(c, "instances", "v1", region)
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			results, err := instances.ListAll(client, nil)
+			if err != nil {
+				return cli.NewExitError(fmt.Errorf("cannot list instances of region %d: %w", region, err), 1)
+			}
+			all = append(all, results...)
+		}
+
+		hosts, errs := sshconfig.Hosts(all, keys, sshconfig.Opts{
+			KeyDir:      c.String("key-dir"),
+			User:        c.String("user"),
+			Port:        c.Int("port"),
+			AliasPrefix: c.String("prefix"),
+		})
+		for _, err := range errs {
+			_, _ = fmt.Fprintf(os.Stderr, "warning: %s\n", err)
+		}
+		if err := sshconfig.Write(os.Stdout, hosts); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return nil
+	},
+}
@@ -6,9 +6,14 @@ import (
 
 	gcorecloud "github.com/G-Core/gcorelabscloud-go"
 	"github.com/G-Core/gcorelabscloud-go/client/flags"
+	flavorclient "github.com/G-Core/gcorelabscloud-go/client/flavors/v1/client"
 	"github.com/G-Core/gcorelabscloud-go/client/k8s/v2/client"
+	quotaclient "github.com/G-Core/gcorelabscloud-go/client/quotas/v2/client"
 	"github.com/G-Core/gcorelabscloud-go/client/utils"
+	"github.com/G-Core/gcorelabscloud-go/gcore/flavor/v1/flavors"
+	"github.com/G-Core/gcorelabscloud-go/gcore/k8s/v2/clusters"
 	"github.com/G-Core/gcorelabscloud-go/gcore/k8s/v2/pools"
+	"github.com/G-Core/gcorelabscloud-go/gcore/quota/v2/quotas"
 	"github.com/G-Core/gcorelabscloud-go/gcore/task/v1/tasks"
 	"github.com/G-Core/gcorelabscloud-go/gcore/volume/v1/volumes"
 	"github.com/urfave/cli/v2"
@@ -357,6 +362,172 @@ var poolInstancesSubCommand = cli.Command{
 	},
 }
 
+var poolPlanSubCommand = cli.Command{
+	Name:      "plan",
+	Usage:     "Plan cluster pool size and check quota",
+	ArgsUsage: "<pool_name>",
+	Category:  "pool",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:     "cluster-name",
+			Aliases:  []string{"c"},
+			Usage:    "Cluster name",
+			Required: true,
+		},
+		&cli.IntFlag{
+			Name:     "node-count",
+			Usage:    "Target node count",
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "cpu",
+			Usage:    "Total number of vCPUs required by the pool",
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "ram",
+			Usage:    "Total RAM in MiB required by the pool",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "flavor-id",
+			Usage:    "Use this flavor instead of recommending one",
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "headroom",
+			Usage:    "Percentage of nodes added to node count to get max node count",
+			Required: false,
+		},
+		&cli.BoolFlag{
+			Name:     "apply",
+			Usage:    "Update and resize the pool when the plan fits into the quota",
+			Required: false,
+		},
+	}, flags.WaitCommandFlags...),
+	Action: func(c *cli.Context) error {
+		poolName, err := flags.GetFirstStringArg(c, poolNameText)
+		if err != nil {
+			_ = cli.ShowCommandHelp(c, "plan")
+			return cli.NewExitError(err, 1)
+		}
+		clusterName := c.String("cluster-name")
+		client, err := client.NewK8sClustersClientV2(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		flavorClient, err := flavorclient.NewFlavorClientV1(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		quotaClient, err := quotaclient.NewQuotaClientV2(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+
+		pool, err := pools.Get(client, clusterName, poolName).Extract()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		nodes, err := pools.ListInstancesAll(client, clusterName, poolName)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		includePrices := true
+		flavorList, err := flavors.ListAll(flavorClient, flavors.ListOpts{IncludePrices: &includePrices})
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+
+		opts := pools.PlanOpts{
+			NodeCount: c.Int("node-count"),
+			CPU:       c.Int("cpu"),
+			RAM:       c.Int("ram"),
+			FlavorID:  c.String("flavor-id"),
+			Headroom:  c.Int("headroom"),
+		}
+		plan, err := pools.NewPlan(*pool, flavorList, nodes, opts)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+
+		combined, err := quotas.ListCombined(quotaClient, nil).Extract()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		quota, err := combined.Regional(client.RegionID)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		plan.CheckQuota(quota)
+
+		if plan.Allowed() && plan.RequiredVM > 0 {
+			limitOpts := clusters.CheckLimitsOpts{
+				Pools: []clusters.CheckLimitsPoolOpts{{
+					FlavorID:       plan.FlavorID,
+					NodeCount:      plan.RequiredVM,
+					BootVolumeSize: pool.BootVolumeSize,
+				}},
+			}
+			exceeded, err := clusters.CheckLimits(client, limitOpts).Extract()
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			for name, value := range *exceeded {
+				plan.QuotaExceeded = append(plan.QuotaExceeded, pools.QuotaExceeded{Name: name, Requested: value})
+			}
+		}
+
+		if !c.Bool("apply") {
+			utils.ShowResults(plan, c.String("format"))
+			return nil
+		}
+		if !plan.Allowed() {
+			utils.ShowResults(plan, c.String("format"))
+			return cli.NewExitError(fmt.Errorf("plan for pool %s exceeds quota", poolName), 1)
+		}
+		if plan.FlavorChanged {
+			utils.ShowResults(plan, c.String("format"))
+			return cli.NewExitError(fmt.Errorf("pool %s flavor cannot be changed, create a new pool with flavor %s", poolName, plan.FlavorID), 1)
+		}
+
+		tc := getTaskClient(c, client)
+		if plan.NodeCount < plan.CurrentNodeCount {
+			// the pool is shrunk before min/max are tightened so that the limits never fall below the current node count
+			results, err := pools.Resize(client, clusterName, poolName, plan.ToResizeOpts()).Extract()
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			if _, err := tasks.WaitForTaskResults(tc, results, c.Int("wait-seconds")); err != nil {
+				return cli.NewExitError(fmt.Errorf("cannot resize pool with name: %s. Error: %w", poolName, err), 1)
+			}
+			pool, err := pools.Update(client, clusterName, poolName, plan.ToUpdateOpts()).Extract()
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			utils.ShowResults(pool, c.String("format"))
+			return nil
+		}
+		if _, err := pools.Update(client, clusterName, poolName, plan.ToUpdateOpts()).Extract(); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		results, err := pools.Resize(client, clusterName, poolName, plan.ToResizeOpts()).Extract()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return utils.WaitTaskAndShowResult(c, tc, results, true, func(task tasks.TaskID) (interface{}, error) {
+			pool, err := pools.Get(client, clusterName, poolName).Extract()
+			if err != nil {
+				return nil, fmt.Errorf("cannot resize pool with name: %s. Error: %w", poolName, err)
+			}
+			return pool, err
+		})
+	},
+}
+
 var poolCommands = cli.Command{
 	Name:  "pool",
 	Usage: "GCloud k8s cluster pool commands",
@@ -368,5 +539,6 @@ var poolCommands = cli.Command{
 		&poolResizeSubCommand,
 		&poolDeleteSubCommand,
 		&poolInstancesSubCommand,
+		&poolPlanSubCommand,
 	},
 }
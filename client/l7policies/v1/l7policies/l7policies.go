@@ -8,8 +8,10 @@ import (
 	"github.com/G-Core/gcorelabscloud-go/client/flags"
 	"github.com/G-Core/gcorelabscloud-go/client/l7policies/v1/client"
 	"github.com/G-Core/gcorelabscloud-go/client/l7policies/v1/l7rules"
+	lbclient "github.com/G-Core/gcorelabscloud-go/client/loadbalancers/v1/client"
 	"github.com/G-Core/gcorelabscloud-go/client/utils"
 	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/l7policies"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/lbpools"
 	"github.com/G-Core/gcorelabscloud-go/gcore/task/v1/tasks"
 )
 
@@ -24,6 +26,7 @@ var Commands = cli.Command{
 		&l7policyReplaceSubCommand,
 		&l7policyDeleteSubCommand,
 		&l7policyCreateSubCommand,
+		&l7policyImportSubCommand,
 		&l7rules.L7RuleCommands,
 	},
 }
@@ -279,3 +282,91 @@ var l7policyReplaceSubCommand = cli.Command{
 		})
 	},
 }
+
+var l7policyImportSubCommand = cli.Command{
+	Name:     "import",
+	Usage:    "Create or replace listener l7policies from a routing config",
+	Category: "l7policy",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "listener-id",
+			Usage:    "L7policy listener id",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:     "file",
+			Usage:    "YAML routing config with host and path_prefix routes to pools, redirects or rejects",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "loadbalancer-id",
+			Usage: "Loadbalancer id to resolve pool names. Pools of the listener are used if not set.",
+		},
+		&cli.BoolFlag{
+			Name:  "prune",
+			Usage: "Delete listener l7policies missing from the routing config",
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Show translated l7policies without applying them",
+		},
+		&cli.IntFlag{
+			Name:  "wait-seconds",
+			Usage: "Required amount of time in seconds to wait for each policy and rule change",
+			Value: 300,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		content, err := utils.ReadFile(c.String("file"))
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		cfg, err := l7policies.ParseRoutingConfig(content)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+
+		poolClient, err := lbclient.NewLBPoolClientV1(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		listenerID := c.String("listener-id")
+		listOpts := lbpools.ListOpts{ListenerID: &listenerID}
+		if c.IsSet("loadbalancer-id") {
+			loadBalancerID := c.String("loadbalancer-id")
+			listOpts = lbpools.ListOpts{LoadBalancerID: &loadBalancerID}
+		}
+		pools, err := lbpools.ListAll(poolClient, listOpts)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		poolIDs := make(map[string]string, len(pools))
+		for _, p := range pools {
+			poolIDs[p.Name] = p.ID
+		}
+
+		policies, err := cfg.Translate(listenerID, poolIDs)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if c.Bool("dry-run") {
+			utils.ShowResults(policies, c.String("format"))
+			return nil
+		}
+
+		client, err := client.NewL7PoliciesClientV1(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		result, err := l7policies.ApplyRouting(client, listenerID, policies, c.Bool("prune"), c.Int("wait-seconds"))
+		if result != nil {
+			utils.ShowResults(result, c.String("format"))
+		}
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return nil
+	},
+}
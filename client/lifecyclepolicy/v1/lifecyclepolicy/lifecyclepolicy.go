@@ -32,6 +32,7 @@ var Commands = cli.Command{
 		&deleteSubCommand,
 		&createSubCommand,
 		&updateSubCommand,
+		&simulateSubCommand,
 		&volumeSubCommands,
 		&scheduleSubCommands,
 	},
@@ -0,0 +1,122 @@
+package lifecyclepolicy
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/client/flags"
+	"github.com/G-Core/gcorelabscloud-go/client/lifecyclepolicy/v1/client"
+	"github.com/G-Core/gcorelabscloud-go/client/utils"
+	volumeclient "github.com/G-Core/gcorelabscloud-go/client/volumes/v1/client"
+	"github.com/G-Core/gcorelabscloud-go/gcore/lifecyclepolicy/v1/lifecyclepolicy"
+	"github.com/G-Core/gcorelabscloud-go/gcore/volume/v1/volumes"
+	"github.com/shopspring/decimal"
+	"github.com/urfave/cli/v2"
+)
+
+func extractVolumeSizes(c *cli.Context, policy *lifecyclepolicy.LifecyclePolicy) (map[string]int, error) {
+	sizes := make(map[string]int)
+	if c.IsSet("volume-size") {
+		tags, err := utils.StringSliceToTags(c.StringSlice("volume-size"))
+		if err != nil {
+			return nil, err
+		}
+		for id, value := range tags {
+			size, err := strconv.Atoi(value)
+			if err != nil || size < 1 {
+				return nil, fmt.Errorf("invalid size of volume %s: %s", id, value)
+			}
+			sizes[id] = size
+		}
+	}
+	var volumeClient *gcorecloud.ServiceClient
+	for _, v := range policy.Volumes {
+		if _, ok := sizes[v.ID]; ok {
+			continue
+		}
+		if volumeClient == nil {
+			var err error
+			if volumeClient, err = volumeclient.NewVolumeClientV1(c); err != nil {
+				return nil, err
+			}
+		}
+		volume, err := volumes.Get(volumeClient, v.ID).Extract()
+		if err != nil {
+			return nil, fmt.Errorf("cannot get volume %s size: %w", v.ID, err)
+		}
+		sizes[v.ID] = volume.Size
+	}
+	return sizes, nil
+}
+
+var simulateSubCommand = cli.Command{
+	Name:      "simulate",
+	Usage:     "simulate lifecycle policy snapshots and storage usage locally",
+	ArgsUsage: argsUsagePolicyID,
+	Category:  category,
+	Flags: []cli.Flag{
+		&cli.TimestampFlag{
+			Name:     "start",
+			Usage:    "Simulation start in RFC3339 format. Defaults to now",
+			Layout:   time.RFC3339,
+			Required: false,
+		},
+		&cli.DurationFlag{
+			Name:     "duration",
+			Usage:    "Simulation time window",
+			Value:    30 * 24 * time.Hour,
+			Required: false,
+		},
+		&cli.StringSliceFlag{
+			Name:     "volume-size",
+			Usage:    "Volume size in GiB. Example: --volume-size <volume_id>=10. Sizes of other policy volumes are requested from the API",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "price-per-gib-hour",
+			Usage:    "Snapshot storage price used to calculate the cost",
+			Required: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		lifecyclePolicyID, err := flags.GetFirstIntArg(c, idErrorText)
+		if err != nil {
+			_ = cli.ShowCommandHelp(c, "simulate")
+			return err
+		}
+		if c.Duration("duration") <= 0 {
+			_ = cli.ShowCommandHelp(c, "simulate")
+			return cli.NewExitError(fmt.Errorf("duration should be positive"), 1)
+		}
+		client, err := client.NewLifecyclePolicyClientV1(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		policy, err := lifecyclepolicy.Get(client, lifecyclePolicyID, lifecyclepolicy.GetOpts{NeedVolumes: true}).Extract()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		opts := lifecyclepolicy.SimulateOpts{Start: time.Now().UTC().Truncate(time.Minute)}
+		if start := c.Timestamp("start"); start != nil {
+			opts.Start = *start
+		}
+		opts.End = opts.Start.Add(c.Duration("duration"))
+		if price := c.String("price-per-gib-hour"); price != "" {
+			if opts.PricePerGiBHour, err = decimal.NewFromString(price); err != nil {
+				return cli.NewExitError(fmt.Errorf("invalid price %s: %w", price, err), 1)
+			}
+		}
+		if opts.VolumeSizes, err = extractVolumeSizes(c, policy); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		simulation, err := lifecyclepolicy.Simulate(*policy, opts)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		utils.ShowResults(simulation, c.String("format"))
+		return nil
+	},
+}
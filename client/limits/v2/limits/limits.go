@@ -202,5 +202,6 @@ var Commands = cli.Command{
 		&limitDeleteCommand,
 		&limitCreateCommand,
 		&limitGetCommand,
+		&limitRequestCommand,
 	},
 }
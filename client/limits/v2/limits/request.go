@@ -0,0 +1,174 @@
+package limits
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/G-Core/gcorelabscloud-go/client/flags"
+	"github.com/G-Core/gcorelabscloud-go/client/limits/v2/client"
+	quotaclient "github.com/G-Core/gcorelabscloud-go/client/quotas/v2/client"
+	"github.com/G-Core/gcorelabscloud-go/client/utils"
+	"github.com/G-Core/gcorelabscloud-go/gcore/limit/v2/limits"
+	"github.com/G-Core/gcorelabscloud-go/gcore/quota/v2/quotas"
+	"github.com/urfave/cli/v2"
+)
+
+// readFindings reads findings of `quotas report --format json` from a file, "-" reads stdin.
+func readFindings(path string) ([]quotas.Finding, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	var findings []quotas.Finding
+	if err := json.NewDecoder(r).Decode(&findings); err != nil {
+		return nil, fmt.Errorf("cannot read quota report %s: %w", path, err)
+	}
+	return findings, nil
+}
+
+func getFindings(c *cli.Context) ([]quotas.Finding, error) {
+	if path := c.String("from-quota-report"); path != "" {
+		return readFindings(path)
+	}
+	thresholds := quotas.Thresholds{Warning: c.Float64("warn"), Critical: 100}
+	if err := thresholds.Validate(); err != nil {
+		return nil, err
+	}
+	client, err := quotaclient.NewQuotaClientV2(c)
+	if err != nil {
+		return nil, err
+	}
+	result, err := quotas.ListCombined(client, quotas.ListCombinedOpts{ClientID: c.Int("client-id")}).Extract()
+	if err != nil {
+		return nil, err
+	}
+	return result.Utilization().Findings(thresholds), nil
+}
+
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+var limitRequestCommand = cli.Command{
+	Name:     "request",
+	Usage:    "Propose limits for quotas utilized above the threshold and create a limit request",
+	Category: "limit",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "from-quota-report",
+			Usage: "findings of `quotas report --format json` to propose limits for, - reads stdin. Gets the current quota report if not set",
+		},
+		&cli.StringFlag{
+			Name:    "client-id",
+			Aliases: []string{"c"},
+			Usage:   "Id of the client to get the quota report of",
+		},
+		&cli.Float64Flag{
+			Name:  "warn",
+			Usage: "utilization percent to propose limits from when getting the current quota report",
+			Value: 80,
+		},
+		&cli.Float64Flag{
+			Name:  "headroom",
+			Usage: "factor of the usage to propose as the new limit",
+			Value: 1.5,
+		},
+		&cli.StringFlag{
+			Name:  "justification",
+			Usage: "text/template of the limit request description with .Changes, .Headroom and .HeadroomPercent",
+			Value: limits.DefaultJustification,
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "show proposed limits without creating the limit request",
+		},
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"y"},
+			Usage:   "create the limit request without confirmation",
+		},
+		&cli.IntFlag{
+			Name:  "poll-interval",
+			Usage: "seconds between limit request status checks with --wait",
+			Value: 60,
+		},
+	}, flags.WaitCommandFlags...),
+	Action: func(c *cli.Context) error {
+		if c.Int("poll-interval") <= 0 {
+			_ = cli.ShowCommandHelp(c, "request")
+			return cli.NewExitError(fmt.Errorf("--poll-interval should be positive"), 1)
+		}
+		if c.String("from-quota-report") == "-" && !c.Bool("yes") && !c.Bool("dry-run") {
+			_ = cli.ShowCommandHelp(c, "request")
+			return cli.NewExitError(fmt.Errorf("--yes is required when reading the quota report from stdin"), 1)
+		}
+		findings, err := getFindings(c)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		changes, skipped, err := limits.Propose(findings, c.Float64("headroom"))
+		if err != nil {
+			_ = cli.ShowCommandHelp(c, "request")
+			return cli.NewExitError(err, 1)
+		}
+		for _, f := range skipped {
+			fmt.Fprintf(os.Stderr, "skipping %s of region %d: limit cannot be requested\n", f.Name, f.RegionID)
+		}
+		if len(changes) == 0 {
+			fmt.Fprintln(os.Stderr, "no limits to request")
+			return nil
+		}
+		description, err := limits.Justify(c.String("justification"), changes, c.Float64("headroom"))
+		if err != nil {
+			_ = cli.ShowCommandHelp(c, "request")
+			return cli.NewExitError(err, 1)
+		}
+		opts, err := limits.NewCreateOptsFromChanges(description, changes)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+
+		fmt.Fprintln(os.Stderr, description)
+		if c.Bool("dry-run") {
+			utils.ShowResults(changes, c.String("format"))
+			return nil
+		}
+		if !c.Bool("yes") && !confirm("Create limit request?") {
+			return cli.NewExitError(fmt.Errorf("limit request cancelled"), 1)
+		}
+
+		client, err := client.NewLimitClientV2(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		result, err := limits.Create(client, opts).Extract()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if c.Bool("wait") {
+			ctx, cancel := context.WithTimeout(c.Context, time.Duration(c.Int("wait-seconds"))*time.Second)
+			defer cancel()
+			interval := time.Duration(c.Int("poll-interval")) * time.Second
+			if result, err = limits.WaitForResolved(ctx, client, result.ID, interval); err != nil {
+				return cli.NewExitError(err, 1)
+			}
+		}
+		utils.ShowResults(result, c.String("format"))
+		return nil
+	},
+}
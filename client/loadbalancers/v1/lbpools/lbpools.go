@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	gcorecloud "github.com/G-Core/gcorelabscloud-go"
 	"github.com/G-Core/gcorelabscloud-go/client/flags"
@@ -1077,6 +1078,72 @@ var lbpoolDeleteHealthMonitorSubCommand = cli.Command{
 	},
 }
 
+var lbpoolShiftSubCommand = cli.Command{
+	Name:      "shift",
+	Usage:     "gradually shift traffic between two groups of loadbalancer pool members",
+	ArgsUsage: "<pool_id>",
+	Category:  "pool",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:     "from",
+			Usage:    "source members: member ID, instance ID, address or address:port",
+			Required: true,
+		},
+		&cli.StringSliceFlag{
+			Name:     "to",
+			Usage:    "target members: member ID, instance ID, address or address:port",
+			Required: true,
+		},
+		&cli.IntFlag{
+			Name:     "steps",
+			Usage:    "number of weight shift steps",
+			Value:    5,
+			Required: false,
+		},
+		&cli.DurationFlag{
+			Name:     "interval",
+			Usage:    "time to wait after each step before checking target members health",
+			Value:    30 * time.Second,
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "wait-seconds",
+			Usage:    "Required amount of time in seconds to wait for each member update",
+			Value:    600,
+			Required: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		lbpoolID, err := flags.GetFirstStringArg(c, lbpoolIDText)
+		if err != nil {
+			_ = cli.ShowCommandHelp(c, "shift")
+			return err
+		}
+		client, err := client.NewLBPoolClientV1(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		opts := lbpools.ShiftOpts{
+			From:        c.StringSlice("from"),
+			To:          c.StringSlice("to"),
+			Steps:       c.Int("steps"),
+			Interval:    c.Duration("interval"),
+			WaitSeconds: c.Int("wait-seconds"),
+		}
+		if err := opts.Validate(); err != nil {
+			_ = cli.ShowCommandHelp(c, "shift")
+			return cli.NewExitError(err, 1)
+		}
+		lbpool, err := lbpools.Shift(c.Context, client, lbpoolID, opts)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		utils.ShowResults(lbpool, c.String("format"))
+		return nil
+	},
+}
+
 var PoolCommands = cli.Command{
 	Name:  "pool",
 	Usage: "GCloud loadbalancer pools API",
@@ -1087,6 +1154,7 @@ var PoolCommands = cli.Command{
 		&lbpoolDeleteSubCommand,
 		&lbpoolCreateSubCommand,
 		&lbpoolUnsetSubCommand,
+		&lbpoolShiftSubCommand,
 		{
 			Name:  "member",
 			Usage: "GCloud loadbalancer pool members API",
@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/G-Core/gcorelabscloud-go/client/common"
+	"github.com/G-Core/gcorelabscloud-go/client/utils"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/exporter"
+	"github.com/urfave/cli/v2"
+)
+
+// parseInstanceIDs groups <instance_id> and <region_id>:<instance_id> values by region. Plain IDs belong to defaultRegion.
+func parseInstanceIDs(values []string, defaultRegion int) (map[int][]string, error) {
+	result := make(map[int][]string)
+	for _, value := range values {
+		region, id := defaultRegion, value
+		if parts := strings.SplitN(value, ":", 2); len(parts) == 2 {
+			r, err := strconv.Atoi(parts[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid instance %s, expected <region_id>:<instance_id>", value)
+			}
+			region, id = r, parts[1]
+		}
+		result[region] = append(result[region], id)
+	}
+	return result, nil
+}
+
+func buildTargets(c *cli.Context) ([]exporter.Target, error) {
+	regions := c.IntSlice("regions")
+	if len(regions) == 0 {
+		regions = []int{c.Int("region")}
+	}
+	ids, err := parseInstanceIDs(c.StringSlice("instance-id"), regions[0])
+	if err != nil {
+		return nil, err
+	}
+	var metadata map[string]string
+	if c.IsSet("metadata") {
+		if metadata, err = utils.StringSliceToTags(c.StringSlice("metadata")); err != nil {
+			return nil, err
+		}
+	}
+	for region := range ids {
+		found := false
+		for _, r := range regions {
+			found = found || r == region
+		}
+		if !found {
+			regions = append(regions, region)
+		}
+	}
+
+	targets := make([]exporter.Target, 0, len(regions))
+	for _, region := range regions {
+		if len(ids) > 0 && len(ids[region]) == 0 {
+			continue
+		}
+		instanceClient, err := common.BuildRegionClient(c, "instances", "v1", region)
+		if err != nil {
+			return nil, err
+		}
+		target := exporter.Target{Client: instanceClient, InstanceIDs: ids[region]}
+		target.ListOpts.Metadata = metadata
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+var exporterSubCommand = cli.Command{
+	Name:     "exporter",
+	Usage:    "Serve instance metrics in Prometheus format",
+	Category: "metrics",
+	Flags: []cli.Flag{
+		&cli.IntSliceFlag{
+			Name:     "regions",
+			Usage:    "region IDs to poll. Defaults to the current region",
+			Required: false,
+		},
+		&cli.StringSliceFlag{
+			Name:     "instance-id",
+			Aliases:  []string{"i"},
+			Usage:    "instance to poll as <instance_id> in the first region or <region_id>:<instance_id>. Defaults to all instances",
+			Required: false,
+		},
+		&cli.StringSliceFlag{
+			Name:     "metadata",
+			Usage:    "poll instances with the metadata only. Example: --metadata env=prod",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "listen",
+			Usage:    "address to serve metrics on",
+			Value:    ":9191",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "path",
+			Usage:    "metrics endpoint path",
+			Value:    "/metrics",
+			Required: false,
+		},
+		&cli.DurationFlag{
+			Name:     "interval",
+			Usage:    "time between metric polls",
+			Value:    exporter.DefaultInterval,
+			Required: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.Duration("interval") < time.Second {
+			_ = cli.ShowCommandHelp(c, "exporter")
+			return cli.NewExitError(fmt.Errorf("interval should be at least a second"), 1)
+		}
+		targets, err := buildTargets(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		collector := exporter.NewCollector(targets, exporter.Opts{
+			Interval: c.Duration("interval"),
+			Errors: func(err error) {
+				_, _ = fmt.Fprintf(os.Stderr, "%s\n", err)
+			},
+		})
+		go func() {
+			_ = collector.Run(c.Context)
+		}()
+
+		mux := http.NewServeMux()
+		mux.Handle(c.String("path"), collector)
+		server := &http.Server{Addr: c.String("listen"), Handler: mux}
+		go func() {
+			<-c.Context.Done()
+			_ = server.Close()
+		}()
+		_, _ = fmt.Fprintf(os.Stderr, "serving metrics of %d regions on %s%s\n", len(targets), c.String("listen"), c.String("path"))
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return cli.NewExitError(err, 1)
+		}
+		return nil
+	},
+}
+
+var Commands = cli.Command{
+	Name:  "metrics",
+	Usage: "GCloud instance metrics",
+	Subcommands: []*cli.Command{
+		&exporterSubCommand,
+	},
+}
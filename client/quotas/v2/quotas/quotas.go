@@ -1,6 +1,8 @@
 package quotas
 
 import (
+	"fmt"
+
 	"github.com/G-Core/gcorelabscloud-go/client/quotas/v2/client"
 	"github.com/G-Core/gcorelabscloud-go/client/utils"
 	"github.com/G-Core/gcorelabscloud-go/gcore/quota/v2/quotas"
@@ -8,8 +10,9 @@ import (
 )
 
 var Commands = cli.Command{
-	Name:  "quotas",
-	Usage: "GCloud quotas API",
+	Name:    "quotas",
+	Aliases: []string{"quota"},
+	Usage:   "GCloud quotas API",
 	Subcommands: []*cli.Command{
 		{
 			Name:  "list",
@@ -20,6 +23,7 @@ var Commands = cli.Command{
 				&quotasListRegionalSubCommands,
 			},
 		},
+		&quotasReportSubCommand,
 	},
 }
 
@@ -119,3 +123,76 @@ var quotasListRegionalSubCommands = cli.Command{
 		return nil
 	},
 }
+
+var quotasReportSubCommand = cli.Command{
+	Name:  "report",
+	Usage: "Report quotas utilized above the thresholds. Exits with code 2 if any are found",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "client-id",
+			Aliases: []string{"c"},
+			Usage:   "Id of the client",
+		},
+		&cli.Float64Flag{
+			Name:  "warn",
+			Usage: "utilization percent to report a quota as warning at",
+			Value: 80,
+		},
+		&cli.Float64Flag{
+			Name:  "critical",
+			Usage: "utilization percent to report a quota as critical at",
+			Value: 100,
+		},
+		&cli.IntSliceFlag{
+			Name:    "region-id",
+			Aliases: []string{"r"},
+			Usage:   "report regions. Defaults to all regions",
+		},
+		&cli.BoolFlag{
+			Name:  "all",
+			Usage: "show utilization of all quotas instead of findings",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		thresholds := quotas.Thresholds{Warning: c.Float64("warn"), Critical: c.Float64("critical")}
+		if err := thresholds.Validate(); err != nil {
+			_ = cli.ShowCommandHelp(c, "report")
+			return cli.NewExitError(err, 1)
+		}
+		client, err := client.NewQuotaClientV2(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+
+		opts := quotas.ListCombinedOpts{ClientID: c.Int("client-id")}
+		result, err := quotas.ListCombined(client, opts).Extract()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		utilization := result.Utilization()
+		if regionIDs := c.IntSlice("region-id"); len(regionIDs) > 0 {
+			regional := utilization.Regional[:0]
+			for _, r := range utilization.Regional {
+				for _, id := range regionIDs {
+					if r.RegionID == id {
+						regional = append(regional, r)
+						break
+					}
+				}
+			}
+			utilization.Regional = regional
+		}
+
+		findings := utilization.Findings(thresholds)
+		if c.Bool("all") {
+			utils.ShowResults(utilization, c.String("format"))
+		} else {
+			utils.ShowResults(findings, c.String("format"))
+		}
+		if len(findings) > 0 {
+			return cli.NewExitError(fmt.Errorf("%d quotas utilized above %v%%", len(findings), thresholds.Warning), 2)
+		}
+		return nil
+	},
+}
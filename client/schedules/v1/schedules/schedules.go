@@ -60,9 +60,8 @@ var scheduleUpdateSubCommand = cli.Command{
 	Category:  "schedule",
 	Flags: []cli.Flag{
 		&cli.StringFlag{
-			Name:    "hour",
-			Aliases: []string{"h"},
-			Usage:   "Hour (0-23, '*') or a comma-separated string listing of hours.",
+			Name:  "hour",
+			Usage: "Hour (0-23, '*') or a comma-separated string listing of hours.",
 		},
 		&cli.StringFlag{
 			Name:  "month",
@@ -82,9 +81,8 @@ var scheduleUpdateSubCommand = cli.Command{
 			Usage: "Minute (0-59, '*') or a comma-separated string listing of minutes.",
 		},
 		&cli.StringFlag{
-			Name:    "day",
-			Aliases: []string{"d"},
-			Usage:   "Day of the month (1-31, '*') or a comma-separated string listing of days.",
+			Name:  "day",
+			Usage: "Day of the month (1-31, '*') or a comma-separated string listing of days.",
 		},
 		&cli.IntFlag{
 			Name:  "retention-time-days",
@@ -0,0 +1,51 @@
+package securitygroups
+
+import (
+	"github.com/G-Core/gcorelabscloud-go/client/flags"
+	instanceclient "github.com/G-Core/gcorelabscloud-go/client/instances/v1/client"
+	"github.com/G-Core/gcorelabscloud-go/client/securitygroups/v1/client"
+	"github.com/G-Core/gcorelabscloud-go/client/utils"
+	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/securitygroups"
+
+	"github.com/urfave/cli/v2"
+)
+
+var securityGroupExposureSubCommand = cli.Command{
+	Name:      "exposure",
+	Usage:     "Show effective ingress exposure of an instance",
+	ArgsUsage: "<instance_id>",
+	Category:  "securitygroup",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "public-only",
+			Usage: "Show only ports open to any address",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		instanceID, err := flags.GetFirstStringArg(c, "instance_id is mandatory argument")
+		if err != nil {
+			_ = cli.ShowCommandHelp(c, "exposure")
+			return err
+		}
+		instanceClient, err := instanceclient.NewInstanceClientV1(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		client, err := client.NewSecurityGroupClientV1(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		report, err := securitygroups.AnalyzeExposure(instanceClient, client, instanceID)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if c.Bool("public-only") {
+			utils.ShowResults(report.Public(), c.String("format"))
+			return nil
+		}
+		utils.ShowResults(report, c.String("format"))
+		return nil
+	},
+}
@@ -401,6 +401,8 @@ var Commands = cli.Command{
 		&securityGroupDeleteSubCommand,
 		&securityGroupCreateSubCommand,
 		&securityGroupDeepCopySubCommand,
+		&securityGroupSyncSubCommand,
+		&securityGroupExposureSubCommand,
 		{
 			Name:  "instance",
 			Usage: "Security group instances",
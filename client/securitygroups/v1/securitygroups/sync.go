@@ -0,0 +1,148 @@
+package securitygroups
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/G-Core/gcorelabscloud-go/client/flags"
+	"github.com/G-Core/gcorelabscloud-go/client/securitygroups/v1/client"
+	"github.com/G-Core/gcorelabscloud-go/client/utils"
+	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/securitygroups"
+	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/types"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v2"
+)
+
+// ruleSpec is a security group rule as described in a rules file. Ports are a single port or a range like 8000-8080.
+type ruleSpec struct {
+	Direction      string `yaml:"direction"`
+	EtherType      string `yaml:"ethertype"`
+	Protocol       string `yaml:"protocol"`
+	Ports          string `yaml:"ports"`
+	RemoteIPPrefix string `yaml:"remote_ip_prefix"`
+	RemoteGroupID  string `yaml:"remote_group_id"`
+	Description    string `yaml:"description"`
+}
+
+type rulesFile struct {
+	Rules []ruleSpec `yaml:"rules"`
+}
+
+func parsePorts(ports string) (*int, *int, error) {
+	ports = strings.TrimSpace(ports)
+	if ports == "" {
+		return nil, nil, nil
+	}
+	bounds := strings.SplitN(ports, "-", 2)
+	low, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid ports %s", ports)
+	}
+	high := low
+	if len(bounds) == 2 {
+		if high, err = strconv.Atoi(strings.TrimSpace(bounds[1])); err != nil {
+			return nil, nil, fmt.Errorf("invalid ports %s", ports)
+		}
+	}
+	return &low, &high, nil
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func (r ruleSpec) toOpts() (securitygroups.CreateSecurityGroupRuleOpts, error) {
+	low, high, err := parsePorts(r.Ports)
+	if err != nil {
+		return securitygroups.CreateSecurityGroupRuleOpts{}, err
+	}
+	return securitygroups.CreateSecurityGroupRuleOpts{
+		Direction:      types.RuleDirection(r.Direction),
+		EtherType:      types.EtherType(r.EtherType),
+		Protocol:       types.Protocol(r.Protocol),
+		PortRangeMin:   low,
+		PortRangeMax:   high,
+		RemoteIPPrefix: optionalString(r.RemoteIPPrefix),
+		RemoteGroupID:  optionalString(r.RemoteGroupID),
+		Description:    optionalString(r.Description),
+	}, nil
+}
+
+func readRulesFile(filename string) ([]securitygroups.CreateSecurityGroupRuleOpts, error) {
+	content, err := utils.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var file rulesFile
+	if err := yaml.UnmarshalStrict(content, &file); err != nil {
+		return nil, fmt.Errorf("cannot parse rules file %s: %w", filename, err)
+	}
+	rules := make([]securitygroups.CreateSecurityGroupRuleOpts, 0, len(file.Rules))
+	for idx, spec := range file.Rules {
+		opts, err := spec.toOpts()
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", idx+1, err)
+		}
+		rules = append(rules, opts)
+	}
+	return rules, nil
+}
+
+var securityGroupSyncSubCommand = cli.Command{
+	Name:      "sync",
+	Usage:     "Sync security group rules with a rules file",
+	ArgsUsage: "<securitygroup_id>",
+	Category:  "securitygroup",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "file",
+			Usage:    "YAML file with security group rules",
+			Required: true,
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Show rules to add and remove without changing the security group",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		securityGroupID, err := flags.GetFirstStringArg(c, securityGroupIDText)
+		if err != nil {
+			_ = cli.ShowCommandHelp(c, "sync")
+			return err
+		}
+		desired, err := readRulesFile(c.String("file"))
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		client, err := client.NewSecurityGroupClientV1(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+
+		if c.Bool("dry-run") {
+			sg, err := securitygroups.Get(client, securityGroupID).Extract()
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			diff, err := securitygroups.DiffRules(sg.SecurityGroupRules, desired)
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			utils.ShowResults(diff, c.String("format"))
+			return nil
+		}
+
+		diff, err := securitygroups.SyncRules(c.Context, client, securityGroupID, desired)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		utils.ShowResults(diff, c.String("format"))
+		return nil
+	},
+}
@@ -0,0 +1,168 @@
+package snapshots
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/G-Core/gcorelabscloud-go/client/flags"
+	instanceclient "github.com/G-Core/gcorelabscloud-go/client/instances/v1/client"
+	"github.com/G-Core/gcorelabscloud-go/client/snapshots/v1/client"
+	"github.com/G-Core/gcorelabscloud-go/client/utils"
+	volumeclient "github.com/G-Core/gcorelabscloud-go/client/volumes/v1/client"
+	"github.com/G-Core/gcorelabscloud-go/gcore/snapshot/v1/snapshots"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	instanceIDText = "instance_id is mandatory argument"
+	quiesceNames   = []string{string(snapshots.QuiesceStop), string(snapshots.QuiesceSuspend)}
+	restoreModes   = []string{string(snapshots.RestoreNewVolumes), string(snapshots.RestoreRevert)}
+)
+
+func buildGroupClients(c *cli.Context) (snapshots.GroupClients, error) {
+	var (
+		clients snapshots.GroupClients
+		err     error
+	)
+	if clients.Instances, err = instanceclient.NewInstanceClientV1(c); err != nil {
+		return clients, err
+	}
+	if clients.Volumes, err = volumeclient.NewVolumeClientV1(c); err != nil {
+		return clients, err
+	}
+	if clients.Snapshots, err = client.NewSnapshotClientV1(c); err != nil {
+		return clients, err
+	}
+	return clients, nil
+}
+
+var snapshotGroupCreateCommand = cli.Command{
+	Name:      "create",
+	Usage:     "Snapshot all instance volumes at once and show the group manifest",
+	ArgsUsage: "<instance_id>",
+	Category:  "group",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "name",
+			Aliases:  []string{"n"},
+			Usage:    "snapshot name prefix. Defaults to the group ID",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "description",
+			Usage:    "snapshot description",
+			Required: false,
+		},
+		&cli.GenericFlag{
+			Name: "quiesce",
+			Value: &utils.EnumValue{
+				Enum: quiesceNames,
+			},
+			Usage:    fmt.Sprintf("%s the instance while its volumes are snapshotted", strings.Join(quiesceNames, " or ")),
+			Required: false,
+		},
+		&cli.StringSliceFlag{
+			Name:     "metadata",
+			Usage:    "snapshot metadata. Example: --metadata one=two --metadata three=four",
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "wait-seconds",
+			Usage:    "time to wait for every task",
+			Value:    snapshots.GroupWaitSeconds,
+			Required: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		instanceID, err := flags.GetFirstStringArg(c, instanceIDText)
+		if err != nil {
+			_ = cli.ShowCommandHelp(c, "create")
+			return err
+		}
+		clients, err := buildGroupClients(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		opts := snapshots.CreateGroupOpts{
+			Name:        c.String("name"),
+			Description: c.String("description"),
+			Quiesce:     snapshots.Quiesce(c.String("quiesce")),
+			WaitSeconds: c.Int("wait-seconds"),
+		}
+		if c.IsSet("metadata") {
+			if opts.Metadata, err = utils.StringSliceToTags(c.StringSlice("metadata")); err != nil {
+				return cli.NewExitError(err, 1)
+			}
+		}
+		manifest, err := snapshots.CreateGroup(c.Context, clients, instanceID, opts)
+		// the manifest is returned along with an error if the instance cannot be brought back
+		if manifest != nil {
+			utils.ShowResults(manifest, c.String("format"))
+		}
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return nil
+	},
+}
+
+var snapshotGroupRestoreCommand = cli.Command{
+	Name:     "restore",
+	Usage:    "Restore volumes from a group manifest",
+	Category: "group",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "file",
+			Usage:    "group manifest json file",
+			Required: true,
+		},
+		&cli.GenericFlag{
+			Name: "mode",
+			Value: &utils.EnumValue{
+				Enum:    restoreModes,
+				Default: restoreModes[0],
+			},
+			Usage:    fmt.Sprintf("create new volumes or revert volumes in place. output in %s", strings.Join(restoreModes, ", ")),
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "name-prefix",
+			Usage:    "new volume name prefix",
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "wait-seconds",
+			Usage:    "time to wait for every task",
+			Value:    snapshots.GroupWaitSeconds,
+			Required: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		content, err := utils.ReadFile(c.String("file"))
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		var manifest snapshots.GroupManifest
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return cli.NewExitError(fmt.Errorf("cannot parse group manifest %s: %w", c.String("file"), err), 1)
+		}
+		clients, err := buildGroupClients(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		restored, err := snapshots.RestoreGroup(c.Context, clients, manifest, snapshots.RestoreGroupOpts{
+			Mode:        snapshots.RestoreMode(c.String("mode")),
+			NamePrefix:  c.String("name-prefix"),
+			WaitSeconds: c.Int("wait-seconds"),
+		})
+		utils.ShowResults(restored, c.String("format"))
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return nil
+	},
+}
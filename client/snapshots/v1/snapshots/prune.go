@@ -0,0 +1,108 @@
+package snapshots
+
+import (
+	"github.com/G-Core/gcorelabscloud-go/client/snapshots/v1/client"
+	"github.com/G-Core/gcorelabscloud-go/client/utils"
+	"github.com/G-Core/gcorelabscloud-go/gcore/snapshot/v1/snapshots"
+
+	"github.com/urfave/cli/v2"
+)
+
+var snapshotPruneCommand = cli.Command{
+	Name:     "prune",
+	Usage:    "Delete snapshots not kept by a retention policy",
+	Category: "snapshot",
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:     "last",
+			Usage:    "number of newest snapshots to keep",
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "hourly",
+			Usage:    "number of hourly snapshots to keep",
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "daily",
+			Usage:    "number of daily snapshots to keep",
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "weekly",
+			Usage:    "number of weekly snapshots to keep",
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "monthly",
+			Usage:    "number of monthly snapshots to keep",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "volume-id",
+			Aliases:  []string{"v"},
+			Usage:    "prune snapshots of the volume only. Either --volume-id or --selector is required",
+			Required: false,
+		},
+		&cli.StringSliceFlag{
+			Name:     "selector",
+			Usage:    "prune snapshots with the metadata only. Either --volume-id or --selector is required. Example: --selector policy=nightly",
+			Required: false,
+		},
+		&cli.StringSliceFlag{
+			Name:     "group-by",
+			Usage:    "metadata key splitting snapshots of a volume into retention groups",
+			Required: false,
+		},
+		&cli.BoolFlag{
+			Name:     "dry-run",
+			Usage:    "show the snapshots to delete without deleting them",
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "wait-seconds",
+			Usage:    "time to wait for every snapshot deletion",
+			Value:    snapshots.PruneWaitSeconds,
+			Required: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		opts := snapshots.PruneOpts{
+			Policy: snapshots.RetentionPolicy{
+				Last:    c.Int("last"),
+				Hourly:  c.Int("hourly"),
+				Daily:   c.Int("daily"),
+				Weekly:  c.Int("weekly"),
+				Monthly: c.Int("monthly"),
+			},
+			VolumeID:    c.String("volume-id"),
+			GroupBy:     c.StringSlice("group-by"),
+			DryRun:      c.Bool("dry-run"),
+			WaitSeconds: c.Int("wait-seconds"),
+		}
+		if c.IsSet("selector") {
+			selector, err := utils.StringSliceToTags(c.StringSlice("selector"))
+			if err != nil {
+				return cli.NewExitError(err, 1)
+			}
+			opts.Selector = selector
+		}
+		if err := opts.Validate(); err != nil {
+			_ = cli.ShowCommandHelp(c, "prune")
+			return cli.NewExitError(err, 1)
+		}
+		client, err := client.NewSnapshotClientV1(c)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		report, err := snapshots.Prune(client, opts)
+		if report != nil {
+			utils.ShowResults(report, c.String("format"))
+		}
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return nil
+	},
+}
@@ -274,6 +274,15 @@ var Commands = cli.Command{
 		&snapshotGetCommand,
 		&snapshotDeleteCommand,
 		&snapshotCreateCommand,
+		&snapshotPruneCommand,
+		{
+			Name:  "group",
+			Usage: "Consistent snapshots of all instance volumes",
+			Subcommands: []*cli.Command{
+				&snapshotGroupCreateCommand,
+				&snapshotGroupRestoreCommand,
+			},
+		},
 		{
 			Name:  "metadata",
 			Usage: "Snapshot metadata",
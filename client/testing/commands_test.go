@@ -0,0 +1,39 @@
+package testing
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/G-Core/gcorelabscloud-go/cmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func leafCommands(prefix []string, commands []*cli.Command) [][]string {
+	var result [][]string
+	for _, c := range commands {
+		path := append(append([]string{}, prefix...), c.Name)
+		if len(c.Subcommands) == 0 {
+			result = append(result, path)
+			continue
+		}
+		result = append(result, leafCommands(path, c.Subcommands)...)
+	}
+	return result
+}
+
+func TestCommandsHelp(t *testing.T) {
+	app := cmd.NewApp([]string{"gcoreclient"})
+	app.Writer = io.Discard
+	app.ErrWriter = io.Discard
+	paths := leafCommands(nil, app.Commands)
+	require.NotEmpty(t, paths)
+	for _, path := range paths {
+		args := append(append([]string{"gcoreclient"}, path...), "--help")
+		assert.NotPanics(t, func() {
+			assert.NoError(t, app.Run(args), strings.Join(path, " "))
+		}, strings.Join(path, " "))
+	}
+}
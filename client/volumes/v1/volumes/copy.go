@@ -0,0 +1,113 @@
+package volumes
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/G-Core/gcorelabscloud-go/client/common"
+	"github.com/G-Core/gcorelabscloud-go/client/flags"
+	"github.com/G-Core/gcorelabscloud-go/client/utils"
+	imagetypes "github.com/G-Core/gcorelabscloud-go/gcore/image/v1/images/types"
+	"github.com/G-Core/gcorelabscloud-go/gcore/volume/v1/transfer"
+
+	"github.com/urfave/cli/v2"
+)
+
+// buildCopyClients builds the clients used to copy a volume for the region.
+func buildCopyClients(c *cli.Context, region int, withSnapshots, withUpload bool) (transfer.CopyClients, error) {
+	var (
+		clients transfer.CopyClients
+		err     error
+	)
+	if clients.Volumes, err = common.BuildRegionClient(c, "volumes", "v1", region); err != nil {
+		return clients, err
+	}
+	if clients.Images, err = common.BuildRegionClient(c, "images", "v1", region); err != nil {
+		return clients, err
+	}
+	if withSnapshots {
+		if clients.Snapshots, err = common.BuildRegionClient(c, "snapshots", "v1", region); err != nil {
+			return clients, err
+		}
+	}
+	if withUpload {
+		if clients.ImageUpload, err = common.BuildRegionClient(c, "downloadimage", "v1", region); err != nil {
+			return clients, err
+		}
+	}
+	return clients, nil
+}
+
+var volumeCopyCommand = cli.Command{
+	Name:      "copy",
+	Usage:     "Copy volume to another region",
+	ArgsUsage: "<volume_id>",
+	Category:  "volume",
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:     "to-region",
+			Usage:    "target region ID",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:     "name",
+			Aliases:  []string{"n"},
+			Usage:    "target volume name. Defaults to the source volume name",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "os-type",
+			Usage:    "intermediate image os type",
+			Value:    imagetypes.OsLinux.String(),
+			Required: false,
+		},
+		&cli.BoolFlag{
+			Name:     "keep-images",
+			Usage:    "keep intermediate images in both regions",
+			Required: false,
+		},
+		&cli.IntFlag{
+			Name:     "wait-seconds",
+			Usage:    "time to wait for every task",
+			Value:    transfer.CopyWaitSeconds,
+			Required: false,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		volumeID, err := flags.GetFirstStringArg(c, volumeIDText)
+		if err != nil {
+			_ = cli.ShowCommandHelp(c, "copy")
+			return err
+		}
+		source, err := buildCopyClients(c, c.Int("region"), true, false)
+		if err != nil {
+			_ = cli.ShowAppHelp(c)
+			return cli.NewExitError(err, 1)
+		}
+		target, err := buildCopyClients(c, c.Int("to-region"), false, true)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if source.Volumes.RegionID == target.Volumes.RegionID {
+			return cli.NewExitError(fmt.Errorf("target region %d is the source region", target.Volumes.RegionID), 1)
+		}
+
+		opts := transfer.CopyOpts{
+			Name:        c.String("name"),
+			OSType:      imagetypes.OSType(c.String("os-type")),
+			KeepImages:  c.Bool("keep-images"),
+			WaitSeconds: c.Int("wait-seconds"),
+			Progress: func(step transfer.CopyStep) {
+				_, _ = fmt.Fprintf(os.Stderr, "region %d: %s: task %s finished %s\n", step.RegionID, step.Name, step.TaskID, step.ResourceID)
+			},
+		}
+		result, err := transfer.CopyToRegion(source, target, volumeID, opts)
+		if result != nil {
+			utils.ShowResults(result, c.String("format"))
+		}
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return nil
+	},
+}
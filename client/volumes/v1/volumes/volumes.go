@@ -478,6 +478,7 @@ var Commands = cli.Command{
 		&volumeRetypeCommand,
 		&volumeExtendCommand,
 		&volumeRevertCommand,
+		&volumeCopyCommand,
 		{
 			Name:  "metadata",
 			Usage: "Volume metadata",
@@ -8,6 +8,7 @@ import (
 	"github.com/G-Core/gcorelabscloud-go/client/ais/v1/ais"
 	"github.com/G-Core/gcorelabscloud-go/client/apitokens/v1/apitokens"
 	"github.com/G-Core/gcorelabscloud-go/client/apptemplates/v1/apptemplates"
+	"github.com/G-Core/gcorelabscloud-go/client/billing/v1/billing"
 	"github.com/G-Core/gcorelabscloud-go/client/faas/v1/functions"
 	"github.com/G-Core/gcorelabscloud-go/client/file_shares/v1/file_shares"
 	"github.com/G-Core/gcorelabscloud-go/client/flags"
@@ -24,6 +25,7 @@ import (
 	"github.com/G-Core/gcorelabscloud-go/client/lifecyclepolicy/v1/lifecyclepolicy"
 	"github.com/G-Core/gcorelabscloud-go/client/limits/v2/limits"
 	"github.com/G-Core/gcorelabscloud-go/client/loadbalancers/v1/loadbalancers"
+	"github.com/G-Core/gcorelabscloud-go/client/metrics/v1/metrics"
 	"github.com/G-Core/gcorelabscloud-go/client/networks/v1/networks"
 	"github.com/G-Core/gcorelabscloud-go/client/ports/v1/ports"
 	"github.com/G-Core/gcorelabscloud-go/client/projects/v1/projects"
@@ -81,6 +83,8 @@ var commands = []*cli.Command{
 	&ais.Commands,
 	&functions.Commands,
 	&gpu.Commands,
+	&metrics.Commands,
+	&billing.Commands,
 }
 
 type clientCommands struct {
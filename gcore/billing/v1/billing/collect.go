@@ -0,0 +1,192 @@
+package billing
+
+import (
+	"fmt"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/baremetal/v1/bminstances"
+	"github.com/G-Core/gcorelabscloud-go/gcore/flavor/v1/flavors"
+	"github.com/G-Core/gcorelabscloud-go/gcore/floatingip/v1/floatingips"
+	"github.com/G-Core/gcorelabscloud-go/gcore/gpu/v3/clusters"
+	gpuflavors "github.com/G-Core/gcorelabscloud-go/gcore/gpu/v3/flavors"
+	"github.com/G-Core/gcorelabscloud-go/gcore/gpu/v3/servers"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/lbflavors"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/loadbalancers"
+	"github.com/G-Core/gcorelabscloud-go/gcore/volume/v1/volumes"
+)
+
+// Clients represents service clients of a project region to estimate. Resources of nil clients are skipped.
+type Clients struct {
+	Instances          *gcorecloud.ServiceClient
+	Flavors            *gcorecloud.ServiceClient
+	BaremetalInstances *gcorecloud.ServiceClient
+	BaremetalFlavors   *gcorecloud.ServiceClient
+	GPUVirtual         *gcorecloud.ServiceClient
+	GPUBaremetal       *gcorecloud.ServiceClient
+	Volumes            *gcorecloud.ServiceClient
+	FloatingIPs        *gcorecloud.ServiceClient
+	LoadBalancers      *gcorecloud.ServiceClient
+	LBFlavors          *gcorecloud.ServiceClient
+}
+
+func flavorPrices(client *gcorecloud.ServiceClient) (map[string]flavors.Flavor, error) {
+	prices := make(map[string]flavors.Flavor)
+	if client == nil {
+		return prices, nil
+	}
+	includePrices := true
+	list, err := flavors.ListAll(client, flavors.ListOpts{IncludePrices: &includePrices})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list flavors: %w", err)
+	}
+	for _, f := range list {
+		prices[f.FlavorID] = f
+	}
+	return prices, nil
+}
+
+func gpuFlavorPrices(client *gcorecloud.ServiceClient, baremetal bool) (map[string]gpuflavors.Price, error) {
+	includePrices := true
+	opts := gpuflavors.ListOpts{IncludePrices: &includePrices}
+	pager := gpuflavors.ListVirtual(client, opts)
+	if baremetal {
+		pager = gpuflavors.ListBaremetal(client, opts)
+	}
+	pages, err := pager.AllPages()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list GPU flavors: %w", err)
+	}
+	prices := make(map[string]gpuflavors.Price)
+	add := func(id, name string, price *gpuflavors.Price) {
+		if price != nil {
+			prices[id], prices[name] = *price, *price
+		}
+	}
+	if baremetal {
+		list, err := gpuflavors.ExtractBMFlavors(pages)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range list {
+			add(f.ID, f.Name, f.Price)
+		}
+		return prices, nil
+	}
+	list, err := gpuflavors.ExtractVMFlavors(pages)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range list {
+		add(f.ID, f.Name, f.Price)
+	}
+	return prices, nil
+}
+
+func collectGPU(client *gcorecloud.ServiceClient, baremetal bool) ([]Item, error) {
+	prices, err := gpuFlavorPrices(client, baremetal)
+	if err != nil {
+		return nil, err
+	}
+	list, err := clusters.ListAll(client, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list GPU clusters: %w", err)
+	}
+	var items []Item
+	for _, cluster := range list {
+		clusterServers, err := servers.ListAll(client, cluster.ID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list GPU cluster %s servers: %w", cluster.ID, err)
+		}
+		for _, server := range clusterServers {
+			if server.Flavor == "" {
+				server.Flavor = cluster.Flavor
+			}
+			items = append(items, GPUServerItem(server, prices))
+		}
+	}
+	return items, nil
+}
+
+// Collect lists the project region resources and estimates their monthly prices.
+// Flavor prices are requested from the flavor APIs, volumes and floating IPs are priced by rates.
+func Collect(c Clients, rates Rates) ([]Item, error) {
+	var items []Item
+	if c.Instances != nil {
+		prices, err := flavorPrices(c.Flavors)
+		if err != nil {
+			return nil, err
+		}
+		list, err := instances.ListAll(c.Instances, nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list instances: %w", err)
+		}
+		for _, instance := range list {
+			items = append(items, InstanceItem(ResourceInstance, instance, prices))
+		}
+	}
+	if c.BaremetalInstances != nil {
+		prices, err := flavorPrices(c.BaremetalFlavors)
+		if err != nil {
+			return nil, err
+		}
+		list, err := bminstances.ListAll(c.BaremetalInstances, nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list baremetal servers: %w", err)
+		}
+		for _, instance := range list {
+			items = append(items, InstanceItem(ResourceBaremetal, instance, prices))
+		}
+	}
+	for _, gpu := range []struct {
+		client    *gcorecloud.ServiceClient
+		baremetal bool
+	}{{c.GPUVirtual, false}, {c.GPUBaremetal, true}} {
+		if gpu.client == nil {
+			continue
+		}
+		gpuItems, err := collectGPU(gpu.client, gpu.baremetal)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, gpuItems...)
+	}
+	if c.Volumes != nil {
+		list, err := volumes.ListAll(c.Volumes, nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list volumes: %w", err)
+		}
+		for _, volume := range list {
+			items = append(items, VolumeItem(volume, rates))
+		}
+	}
+	if c.FloatingIPs != nil {
+		list, err := floatingips.ListAll(c.FloatingIPs, nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list floating IPs: %w", err)
+		}
+		for _, ip := range list {
+			items = append(items, FloatingIPItem(ip, rates))
+		}
+	}
+	if c.LoadBalancers != nil {
+		prices := make(map[string]lbflavors.Flavor)
+		if c.LBFlavors != nil {
+			list, err := lbflavors.ListAll(c.LBFlavors)
+			if err != nil {
+				return nil, fmt.Errorf("cannot list load balancer flavors: %w", err)
+			}
+			for _, f := range list {
+				prices[f.FlavorID] = f
+			}
+		}
+		list, err := loadbalancers.ListAll(c.LoadBalancers, nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list load balancers: %w", err)
+		}
+		for _, lb := range list {
+			items = append(items, LoadBalancerItem(lb, prices))
+		}
+	}
+	return items, nil
+}
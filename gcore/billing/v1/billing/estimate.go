@@ -0,0 +1,239 @@
+package billing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/flavor/v1/flavors"
+	"github.com/G-Core/gcorelabscloud-go/gcore/floatingip/v1/floatingips"
+	gpuflavors "github.com/G-Core/gcorelabscloud-go/gcore/gpu/v3/flavors"
+	"github.com/G-Core/gcorelabscloud-go/gcore/gpu/v3/servers"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/lbflavors"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/loadbalancers"
+	"github.com/G-Core/gcorelabscloud-go/gcore/utils/metadata"
+	"github.com/G-Core/gcorelabscloud-go/gcore/volume/v1/volumes"
+	"github.com/shopspring/decimal"
+)
+
+// ResourceType represents a type of billed resources.
+type ResourceType string
+
+const (
+	ResourceInstance     ResourceType = "instance"
+	ResourceBaremetal    ResourceType = "baremetal"
+	ResourceGPUServer    ResourceType = "gpu_server"
+	ResourceVolume       ResourceType = "volume"
+	ResourceFloatingIP   ResourceType = "floating_ip"
+	ResourceLoadBalancer ResourceType = "loadbalancer"
+
+	// NoValue is the breakdown key of resources without tags or the metadata key.
+	NoValue = "-"
+)
+
+// Rates represents monthly prices of resources without a price API.
+type Rates struct {
+	// VolumePerGiBMonth is the price of a GiB by volume type, an empty type applies to the other types.
+	VolumePerGiBMonth map[string]decimal.Decimal
+	// FloatingIPPerMonth is the price of a floating IP.
+	FloatingIPPerMonth *decimal.Decimal
+}
+
+// Item represents the estimated monthly price of a resource. PricePerMonth is nil if the resource price is unknown.
+type Item struct {
+	Type          ResourceType      `json:"type"`
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Flavor        string            `json:"flavor,omitempty"`
+	Size          int               `json:"size,omitempty"`
+	PricePerMonth *decimal.Decimal  `json:"price_per_month,omitempty"`
+	Tags          []string          `json:"tags,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+func (i *Item) setMetadata(md []metadata.Metadata) {
+	for _, m := range md {
+		if m.ReadOnly {
+			continue
+		}
+		if i.Metadata == nil {
+			i.Metadata = make(map[string]string)
+		}
+		i.Metadata[m.Key] = m.Value
+		i.Tags = append(i.Tags, fmt.Sprintf("%s=%s", m.Key, m.Value))
+	}
+	sort.Strings(i.Tags)
+}
+
+func monthly(perMonth, perHour *decimal.Decimal) *decimal.Decimal {
+	return instances.MonthlyPrice(flavors.Flavor{PricePerMonth: perMonth, PricePerHour: perHour})
+}
+
+// InstanceItem prices an instance or a baremetal server by its flavor.
+func InstanceItem(resourceType ResourceType, instance instances.Instance, prices map[string]flavors.Flavor) Item {
+	item := Item{Type: resourceType, ID: instance.ID, Name: instance.Name, Flavor: instance.Flavor.FlavorID}
+	if f, ok := prices[instance.Flavor.FlavorID]; ok {
+		item.PricePerMonth = instances.MonthlyPrice(f)
+	}
+	if item.PricePerMonth == nil {
+		item.PricePerMonth = instances.MonthlyPrice(instance.Flavor)
+	}
+	item.setMetadata(instance.MetadataDetailed)
+	return item
+}
+
+// GPUServerItem prices a GPU cluster server by its flavor. Prices are looked up by flavor ID and name.
+func GPUServerItem(server servers.Server, prices map[string]gpuflavors.Price) Item {
+	item := Item{Type: ResourceGPUServer, ID: server.ID, Name: server.Name, Flavor: server.Flavor}
+	if p, ok := prices[server.Flavor]; ok {
+		item.PricePerMonth = monthly(p.PricePerMonth, p.PricePerHour)
+	}
+	md := make([]metadata.Metadata, 0, len(server.Tags))
+	for _, t := range server.Tags {
+		md = append(md, metadata.Metadata{Key: t.Key, Value: t.Value, ReadOnly: t.ReadOnly})
+	}
+	item.setMetadata(md)
+	return item
+}
+
+// VolumeItem prices a volume by its size and type.
+func VolumeItem(volume volumes.Volume, rates Rates) Item {
+	item := Item{Type: ResourceVolume, ID: volume.ID, Name: volume.Name, Flavor: string(volume.VolumeType), Size: volume.Size}
+	rate, ok := rates.VolumePerGiBMonth[string(volume.VolumeType)]
+	if !ok {
+		rate, ok = rates.VolumePerGiBMonth[""]
+	}
+	if ok {
+		price := rate.Mul(decimal.NewFromInt(int64(volume.Size)))
+		item.PricePerMonth = &price
+	}
+	item.setMetadata(volume.Metadata)
+	return item
+}
+
+// FloatingIPItem prices a floating IP.
+func FloatingIPItem(ip floatingips.FloatingIPDetail, rates Rates) Item {
+	item := Item{Type: ResourceFloatingIP, ID: ip.ID, Name: ip.FloatingIPAddress.String()}
+	if rates.FloatingIPPerMonth != nil {
+		price := *rates.FloatingIPPerMonth
+		item.PricePerMonth = &price
+	}
+	item.setMetadata(ip.Metadata)
+	return item
+}
+
+// LoadBalancerItem prices a load balancer by its flavor.
+func LoadBalancerItem(lb loadbalancers.LoadBalancer, prices map[string]lbflavors.Flavor) Item {
+	item := Item{Type: ResourceLoadBalancer, ID: lb.ID, Name: lb.Name, Flavor: lb.Flavor.FlavorID}
+	f := lb.Flavor
+	if p, ok := prices[f.FlavorID]; ok {
+		f = p
+	}
+	switch {
+	case f.PricePerMonth > 0:
+		price := decimal.NewFromFloat(f.PricePerMonth)
+		item.PricePerMonth = &price
+	case f.PricePerHour > 0:
+		price := decimal.NewFromFloat(f.PricePerHour).Mul(decimal.NewFromInt(instances.HoursPerMonth))
+		item.PricePerMonth = &price
+	}
+	item.setMetadata(lb.Metadata)
+	item.Tags = append(item.Tags, lb.Tags...)
+	sort.Strings(item.Tags)
+	return item
+}
+
+// Cost represents the estimated monthly price of a group of resources.
+// Unpriced is the number of resources without a price which are not included in PricePerMonth.
+type Cost struct {
+	Key           string          `json:"key"`
+	Count         int             `json:"count"`
+	Unpriced      int             `json:"unpriced"`
+	PricePerMonth decimal.Decimal `json:"price_per_month"`
+}
+
+func breakdown(items []Item, keys func(Item) []string) []Cost {
+	costs := make(map[string]*Cost)
+	for _, item := range items {
+		for _, key := range keys(item) {
+			cost, ok := costs[key]
+			if !ok {
+				cost = &Cost{Key: key}
+				costs[key] = cost
+			}
+			cost.Count++
+			if item.PricePerMonth == nil {
+				cost.Unpriced++
+				continue
+			}
+			cost.PricePerMonth = cost.PricePerMonth.Add(*item.PricePerMonth)
+		}
+	}
+	result := make([]Cost, 0, len(costs))
+	for _, cost := range costs {
+		result = append(result, *cost)
+	}
+	// most expensive first
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].PricePerMonth.Equal(result[j].PricePerMonth) {
+			return result[i].PricePerMonth.GreaterThan(result[j].PricePerMonth)
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// Total returns the cost of all items.
+func Total(items []Item) Cost {
+	costs := breakdown(items, func(Item) []string { return []string{"total"} })
+	if len(costs) == 0 {
+		return Cost{Key: "total"}
+	}
+	return costs[0]
+}
+
+// ByType returns the costs of items by resource type.
+func ByType(items []Item) []Cost {
+	return breakdown(items, func(i Item) []string { return []string{string(i.Type)} })
+}
+
+// ByTag returns the costs of items by tag. An item with several tags is counted in each of them.
+func ByTag(items []Item) []Cost {
+	return breakdown(items, func(i Item) []string {
+		if len(i.Tags) == 0 {
+			return []string{NoValue}
+		}
+		return i.Tags
+	})
+}
+
+// ByMetadata returns the costs of items by the value of a metadata key.
+func ByMetadata(items []Item, key string) []Cost {
+	return breakdown(items, func(i Item) []string {
+		if value, ok := i.Metadata[key]; ok {
+			return []string{value}
+		}
+		return []string{NoValue}
+	})
+}
+
+// ParseVolumeRates parses volume prices given as <volume_type>=<price per GiB month> or as <price> applying to all types.
+func ParseVolumeRates(values []string) (map[string]decimal.Decimal, error) {
+	rates := make(map[string]decimal.Decimal, len(values))
+	for _, value := range values {
+		volumeType, price := "", value
+		if idx := strings.Index(value, "="); idx >= 0 {
+			volumeType, price = value[:idx], value[idx+1:]
+			if err := volumes.VolumeType(volumeType).IsValid(); err != nil {
+				return nil, err
+			}
+		}
+		rate, err := decimal.NewFromString(price)
+		if err != nil || rate.IsNegative() {
+			return nil, fmt.Errorf("invalid volume price %s", value)
+		}
+		rates[volumeType] = rate
+	}
+	return rates, nil
+}
@@ -0,0 +1,162 @@
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/billing/v1/billing"
+	"github.com/G-Core/gcorelabscloud-go/gcore/flavor/v1/flavors"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/lbflavors"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/loadbalancers"
+	"github.com/G-Core/gcorelabscloud-go/gcore/utils/metadata"
+	"github.com/G-Core/gcorelabscloud-go/gcore/volume/v1/volumes"
+	th "github.com/G-Core/gcorelabscloud-go/testhelper"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+const instancesResponse = `
+{
+  "count": 2,
+  "results": [
+    {
+      "instance_id": "a7e7e8d6-0bf7-4ac9-8170-831b47ee2ba9",
+      "instance_name": "web",
+      "flavor": {"flavor_id": "g1-standard-1-2", "vcpus": 1, "ram": 2048},
+      "metadata_detailed": [
+        {"key": "team", "value": "web", "read_only": false},
+        {"key": "os_distro", "value": "ubuntu", "read_only": true}
+      ]
+    },
+    {
+      "instance_id": "b8f8f9e7-1cf8-4bd0-9281-942c58ff3cb0",
+      "instance_name": "legacy",
+      "flavor": {"flavor_id": "g0-legacy", "vcpus": 1, "ram": 1024}
+    }
+  ]
+}
+`
+
+const flavorsResponse = `
+{
+  "count": 1,
+  "results": [
+    {"flavor_id": "g1-standard-1-2", "flavor_name": "g1-standard-1-2", "vcpus": 1, "ram": 2048, "price_per_hour": 0.02, "price_status": "show"}
+  ]
+}
+`
+
+const volumesResponse = `
+{
+  "count": 1,
+  "results": [
+    {
+      "id": "726ecfcc-7fd0-4e30-a86e-7892524aa483",
+      "name": "data",
+      "size": 10,
+      "volume_type": "ssd_hiiops",
+      "metadata_detailed": [{"key": "team", "value": "db", "read_only": false}]
+    }
+  ]
+}
+`
+
+func handle(t *testing.T, url, response string) {
+	th.Mux.HandleFunc(url, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, response)
+	})
+}
+
+func price(value string) *decimal.Decimal {
+	d := decimal.RequireFromString(value)
+	return &d
+}
+
+func TestCollect(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	handle(t, fmt.Sprintf("/v1/instances/%d/%d", fake.ProjectID, fake.RegionID), instancesResponse)
+	handle(t, fmt.Sprintf("/v1/flavors/%d/%d", fake.ProjectID, fake.RegionID), flavorsResponse)
+	handle(t, fmt.Sprintf("/v1/volumes/%d/%d", fake.ProjectID, fake.RegionID), volumesResponse)
+
+	rates := billing.Rates{VolumePerGiBMonth: map[string]decimal.Decimal{"": decimal.RequireFromString("0.1")}}
+	items, err := billing.Collect(billing.Clients{
+		Instances: fake.ServiceTokenClient("instances", "v1"),
+		Flavors:   fake.ServiceTokenClient("flavors", "v1"),
+		Volumes:   fake.ServiceTokenClient("volumes", "v1"),
+	}, rates)
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	require.Equal(t, billing.ResourceInstance, items[0].Type)
+	require.Equal(t, "14.6", items[0].PricePerMonth.String())
+	require.Equal(t, []string{"team=web"}, items[0].Tags)
+	require.Nil(t, items[1].PricePerMonth)
+	require.Equal(t, billing.ResourceVolume, items[2].Type)
+	require.Equal(t, "1", items[2].PricePerMonth.String())
+
+	total := billing.Total(items)
+	require.Equal(t, 3, total.Count)
+	require.Equal(t, 1, total.Unpriced)
+	require.Equal(t, "15.6", total.PricePerMonth.String())
+
+	byType := billing.ByType(items)
+	require.Equal(t, "instance", byType[0].Key)
+	require.Equal(t, 2, byType[0].Count)
+	require.Equal(t, "volume", byType[1].Key)
+
+	byTeam := billing.ByMetadata(items, "team")
+	require.Len(t, byTeam, 3)
+	require.Equal(t, "web", byTeam[0].Key)
+	require.Equal(t, "db", byTeam[1].Key)
+	require.Equal(t, billing.NoValue, byTeam[2].Key)
+	require.Equal(t, 1, byTeam[2].Unpriced)
+}
+
+func TestItems(t *testing.T) {
+	instance := instances.Instance{ID: "instance", Flavor: flavors.Flavor{FlavorID: "g1", PricePerMonth: price("10")}}
+	require.Equal(t, "10", billing.InstanceItem(billing.ResourceBaremetal, instance, nil).PricePerMonth.String())
+	prices := map[string]flavors.Flavor{"g1": {FlavorID: "g1", PricePerMonth: price("12")}}
+	require.Equal(t, "12", billing.InstanceItem(billing.ResourceBaremetal, instance, prices).PricePerMonth.String())
+
+	rates := billing.Rates{VolumePerGiBMonth: map[string]decimal.Decimal{
+		"":      decimal.RequireFromString("0.05"),
+		"ultra": decimal.RequireFromString("0.2"),
+	}}
+	require.Equal(t, "20", billing.VolumeItem(volumes.Volume{Size: 100, VolumeType: volumes.Ultra}, rates).PricePerMonth.String())
+	require.Equal(t, "5", billing.VolumeItem(volumes.Volume{Size: 100, VolumeType: volumes.Cold}, rates).PricePerMonth.String())
+	require.Nil(t, billing.VolumeItem(volumes.Volume{Size: 100}, billing.Rates{}).PricePerMonth)
+
+	lb := loadbalancers.LoadBalancer{
+		ID:       "lb",
+		Tags:     []string{"public"},
+		Flavor:   lbflavors.Flavor{FlavorID: "lb1-1-2"},
+		Metadata: []metadata.Metadata{{Key: "team", Value: "web"}},
+	}
+	item := billing.LoadBalancerItem(lb, map[string]lbflavors.Flavor{"lb1-1-2": {FlavorID: "lb1-1-2", PricePerHour: 0.01}})
+	require.Equal(t, "7.3", item.PricePerMonth.String())
+	require.Equal(t, []string{"public", "team=web"}, item.Tags)
+
+	byTag := billing.ByTag([]billing.Item{item, {Type: billing.ResourceFloatingIP}})
+	require.Equal(t, []string{"public", "team=web", billing.NoValue}, []string{byTag[0].Key, byTag[1].Key, byTag[2].Key})
+}
+
+func TestParseVolumeRates(t *testing.T) {
+	rates, err := billing.ParseVolumeRates([]string{"0.05", "ssd_hiiops=0.1"})
+	require.NoError(t, err)
+	require.Equal(t, "0.05", rates[""].String())
+	require.Equal(t, "0.1", rates["ssd_hiiops"].String())
+
+	_, err = billing.ParseVolumeRates([]string{"unknown=0.1"})
+	require.Error(t, err)
+	_, err = billing.ParseVolumeRates([]string{"ssd_hiiops=-1"})
+	require.Error(t, err)
+}
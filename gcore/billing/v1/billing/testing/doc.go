@@ -0,0 +1,2 @@
+// billing unit tests
+package testing
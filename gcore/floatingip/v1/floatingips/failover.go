@@ -0,0 +1,121 @@
+package floatingips
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+)
+
+const failoverPollInterval = time.Second
+
+// waitForPort polls the floating IP until it is assigned to the port, an empty port waits until it is unassigned.
+func waitForPort(ctx context.Context, c *gcorecloud.ServiceClient, floatingIPID, portID string) (*instances.FloatingIP, error) {
+	ticker := time.NewTicker(failoverPollInterval)
+	defer ticker.Stop()
+	for {
+		ip, err := Get(c, floatingIPID).Extract()
+		if err != nil {
+			return nil, err
+		}
+		if ip.PortID == portID {
+			return ip, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("floating IP %s is assigned to port %q instead of %q: %w", floatingIPID, ip.PortID, portID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Failover moves the floating IP from fromPort to toPort and waits until the floating IP is verified on toPort.
+// The floating IP is left as is if it is already assigned to toPort. It is not moved if it is assigned
+// to a port other than fromPort, an empty fromPort moves the floating IP from any port.
+func Failover(ctx context.Context, c *gcorecloud.ServiceClient, floatingIPID, fromPort, toPort string) (*instances.FloatingIP, error) {
+	if toPort == "" {
+		return nil, fmt.Errorf("port to assign floating IP %s to is required", floatingIPID)
+	}
+	ip, err := Get(c, floatingIPID).Extract()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case ip.PortID == toPort:
+		return ip, nil
+	case fromPort != "" && ip.PortID != "" && ip.PortID != fromPort:
+		return nil, fmt.Errorf("floating IP %s is assigned to port %s, not %s", floatingIPID, ip.PortID, fromPort)
+	}
+
+	if ip.PortID != "" {
+		if _, err := UnAssign(c, floatingIPID).Extract(); err != nil {
+			return nil, fmt.Errorf("cannot unassign floating IP %s from port %s: %w", floatingIPID, ip.PortID, err)
+		}
+		if _, err := waitForPort(ctx, c, floatingIPID, ""); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := Assign(c, floatingIPID, CreateOpts{PortID: toPort}).Extract(); err != nil {
+		return nil, fmt.Errorf("cannot assign unassigned floating IP %s to port %s: %w", floatingIPID, toPort, err)
+	}
+	return waitForPort(ctx, c, floatingIPID, toPort)
+}
+
+// WatchOpts represents options of a TCP health check.
+type WatchOpts struct {
+	// Interval is the time between checks.
+	Interval time.Duration `validate:"gt=0"`
+	// Timeout is the connection timeout of a check.
+	Timeout time.Duration `validate:"gt=0"`
+	// Failures is the number of consecutive failed checks the target is considered down after.
+	Failures int `validate:"gt=0"`
+}
+
+// Validate WatchOpts
+func (opts WatchOpts) Validate() error {
+	return gcorecloud.TranslateValidationError(gcorecloud.Validate.Struct(opts))
+}
+
+// CheckTCP connects to the address and closes the connection.
+func CheckTCP(ctx context.Context, address string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// WatchTCP checks the address every interval and returns the last check error once the address failed
+// the number of consecutive checks. It returns the context error when the context is done.
+func WatchTCP(ctx context.Context, address string, opts WatchOpts) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	failures := 0
+	for {
+		if err := CheckTCP(ctx, address, opts.Timeout); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failures++
+			if failures >= opts.Failures {
+				return fmt.Errorf("%s failed %d health checks: %w", address, failures, err)
+			}
+		} else {
+			failures = 0
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
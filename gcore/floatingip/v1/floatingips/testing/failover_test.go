@@ -0,0 +1,100 @@
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/floatingip/v1/floatingips"
+	th "github.com/G-Core/gcorelabscloud-go/testhelper"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	primaryPortID = "ee2402d0-f0cd-4503-9b75-69be1d11c5f1"
+	standbyPortID = "1f0ca628-a73b-42c0-bdac-7b10d023e097"
+)
+
+// handleFloatingIP serves a floating IP assigned to the port and records the assign and unassign calls.
+func handleFloatingIP(t *testing.T, port *string, calls *[]string) {
+	write := func(w http.ResponseWriter) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, `{"id": %q, "port_id": %q, "status": "ACTIVE"}`, floatingIP.ID, *port)
+	}
+	th.Mux.HandleFunc(prepareGetTestURL(floatingIP.ID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+		write(w)
+	})
+	th.Mux.HandleFunc(prepareAssignTestURL(floatingIP.ID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		var opts struct {
+			PortID string `json:"port_id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&opts))
+		*calls = append(*calls, "assign "+opts.PortID)
+		*port = opts.PortID
+		write(w)
+	})
+	th.Mux.HandleFunc(prepareUnAssignTestURL(floatingIP.ID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		*calls = append(*calls, "unassign")
+		*port = ""
+		write(w)
+	})
+}
+
+func TestFailover(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	port := primaryPortID
+	var calls []string
+	handleFloatingIP(t, &port, &calls)
+	client := fake.ServiceTokenClient("floatingips", "v1")
+
+	_, err := floatingips.Failover(context.Background(), client, floatingIP.ID, "other", standbyPortID)
+	require.Error(t, err)
+	require.Empty(t, calls)
+
+	ip, err := floatingips.Failover(context.Background(), client, floatingIP.ID, primaryPortID, standbyPortID)
+	require.NoError(t, err)
+	require.Equal(t, standbyPortID, ip.PortID)
+	require.Equal(t, []string{"unassign", "assign " + standbyPortID}, calls)
+
+	ip, err = floatingips.Failover(context.Background(), client, floatingIP.ID, primaryPortID, standbyPortID)
+	require.NoError(t, err)
+	require.Equal(t, standbyPortID, ip.PortID)
+	require.Len(t, calls, 2)
+
+	port = ""
+	_, err = floatingips.Failover(context.Background(), client, floatingIP.ID, "", primaryPortID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"unassign", "assign " + standbyPortID, "assign " + primaryPortID}, calls)
+}
+
+func TestWatchTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	opts := floatingips.WatchOpts{Interval: time.Millisecond, Timeout: time.Second, Failures: 2}
+
+	require.NoError(t, floatingips.CheckTCP(context.Background(), address, time.Second))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.True(t, errors.Is(floatingips.WatchTCP(ctx, address, opts), context.DeadlineExceeded))
+
+	require.NoError(t, listener.Close())
+	err = floatingips.WatchTCP(context.Background(), address, opts)
+	require.Error(t, err)
+	require.False(t, errors.Is(err, context.DeadlineExceeded))
+
+	require.Error(t, floatingips.WatchTCP(context.Background(), address, floatingips.WatchOpts{}))
+}
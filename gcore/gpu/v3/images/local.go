@@ -0,0 +1,97 @@
+package images
+
+import (
+	"fmt"
+	"net"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	v1images "github.com/G-Core/gcorelabscloud-go/gcore/image/v1/images"
+	"github.com/G-Core/gcorelabscloud-go/gcore/image/v1/images/types"
+	"github.com/G-Core/gcorelabscloud-go/gcore/task/v1/tasks"
+)
+
+// ValidateLocalImage checks the GPU image options against each other and the image file.
+// Returned warnings do not prevent the upload.
+func ValidateLocalImage(image v1images.LocalImage, opts ImageOpts) ([]string, error) {
+	uploadOpts := v1images.UploadOpts{Name: opts.Name}
+	if opts.Architecture != nil {
+		uploadOpts.Architecture = types.ImageArchitectureType(*opts.Architecture)
+	}
+	if opts.HwFirmwareType != nil {
+		uploadOpts.HwFirmwareType = types.HwFirmwareType(*opts.HwFirmwareType)
+	}
+	if opts.OsType != nil {
+		uploadOpts.OSType = types.OSType(*opts.OsType)
+	}
+	if opts.SshKey != nil {
+		uploadOpts.SshKey = types.SshKeyType(*opts.SshKey)
+	}
+	return v1images.ValidateLocalImage(image, uploadOpts)
+}
+
+// UploadFileOpts represents options used to upload a local GPU image file.
+// ImageOpts.URL is set to the URL of the served file.
+type UploadFileOpts struct {
+	ImageOpts
+	Path string
+	// Listener accepts the API connections fetching the file, PublicURL is its address reachable by the API.
+	Listener    net.Listener
+	PublicURL   string
+	WaitSeconds int
+	// Hashing is called while checksums are computed, Sending is called every second while the API fetches the file.
+	Hashing func(done, total int64)
+	Sending func(sent, total int64)
+	// Warn is called with validation warnings.
+	Warn func(warning string)
+}
+
+// UploadFileResult represents a GPU image uploaded from a local file.
+type UploadFileResult struct {
+	ImageID string              `json:"image_id"`
+	Local   v1images.LocalImage `json:"local"`
+	Sent    int64               `json:"sent"`
+}
+
+// UploadFile inspects and validates the local image, serves it for the API and waits until the API has fetched it.
+// The upload task is polled with taskClient, a v1 tasks client, as GPU clients use the v3 API.
+func UploadFile(c *gcorecloud.ServiceClient, taskClient *gcorecloud.ServiceClient, opts UploadFileOpts) (*UploadFileResult, error) {
+	if opts.Listener == nil {
+		return nil, fmt.Errorf("listener is required")
+	}
+	defer opts.Listener.Close()
+	if opts.WaitSeconds == 0 {
+		opts.WaitSeconds = v1images.UploadFileWaitSeconds
+	}
+	local, err := v1images.InspectLocalImage(opts.Path, opts.Hashing)
+	if err != nil {
+		return nil, err
+	}
+	warnings, err := ValidateLocalImage(*local, opts.ImageOpts)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range warnings {
+		if opts.Warn != nil {
+			opts.Warn(w)
+		}
+	}
+
+	var imageID string
+	sent, err := v1images.UploadServedFile(opts.Listener, opts.Path, opts.PublicURL, opts.Sending, func(url string) error {
+		opts.ImageOpts.URL = url
+		results, err := UploadImage(c, opts.ImageOpts).Extract()
+		if err != nil {
+			return err
+		}
+		task, err := tasks.WaitForTaskResults(taskClient, results, opts.WaitSeconds)
+		if err != nil {
+			return err
+		}
+		imageID, err = ExtractImageIDFromTask(task)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &UploadFileResult{ImageID: imageID, Local: *local, Sent: sent}, nil
+}
@@ -0,0 +1,85 @@
+package events
+
+import (
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/pagination"
+)
+
+// ListOptsBuilder allows extensions to add additional parameters to the List request.
+type ListOptsBuilder interface {
+	ToEventListQuery() (string, error)
+}
+
+// ListOpts allows the filtering and sorting of paginated collections through the API.
+type ListOpts struct {
+	// ResourceAction filters events by an action, e.g. CREATE.
+	ResourceAction string `q:"resource_action"`
+	// ResourceStatus filters events by a status, e.g. FAILED.
+	ResourceStatus string `q:"resource_status"`
+	// ResourceName filters events by a resource name.
+	ResourceName string `q:"resource_name"`
+	// ResourceType filters events by a resource type.
+	ResourceType string `q:"resource_type"`
+	// Marker is the ID of the last-seen event.
+	Marker string `q:"marker"`
+	// Limit is the maximum number of events per page.
+	Limit int `q:"limit"`
+	// SortKey sorts events by event_time, resource_name, resource_status etc.
+	SortKey string `q:"sort_keys"`
+	// SortDir is asc or desc.
+	SortDir string `q:"sort_dir"`
+	// NestedDepth includes events of nested stacks up to the given depth.
+	NestedDepth int `q:"nested_depth"`
+}
+
+// ToEventListQuery formats a ListOpts into a query string.
+func (opts ListOpts) ToEventListQuery() (string, error) {
+	q, err := gcorecloud.BuildQueryString(opts)
+	if err != nil {
+		return "", err
+	}
+	return q.String(), err
+}
+
+func list(c *gcorecloud.ServiceClient, url string, opts ListOptsBuilder) pagination.Pager {
+	if opts != nil {
+		query, err := opts.ToEventListQuery()
+		if err != nil {
+			return pagination.Pager{Err: err}
+		}
+		url += query
+	}
+	return pagination.NewPager(c, url, func(r pagination.PageResult) pagination.Page {
+		p := EventPage{pagination.MarkerPageBase{PageResult: r}}
+		p.MarkerPageBase.Owner = p
+		return p
+	})
+}
+
+// List retrieves the events of a stack. Pages are requested with the ID of the last event as a marker.
+func List(c *gcorecloud.ServiceClient, stackID string, opts ListOptsBuilder) pagination.Pager {
+	return list(c, listURL(c, stackID), opts)
+}
+
+// ListResource retrieves the events of a single stack resource.
+func ListResource(c *gcorecloud.ServiceClient, stackID, resourceName string, opts ListOptsBuilder) pagination.Pager {
+	return list(c, resourceListURL(c, stackID, resourceName), opts)
+}
+
+// ListAll is a convenience function that returns all stack events.
+func ListAll(c *gcorecloud.ServiceClient, stackID string, opts ListOptsBuilder) ([]Event, error) {
+	pages, err := List(c, stackID, opts).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	return ExtractEvents(pages)
+}
+
+// ListResourceAll is a convenience function that returns all events of a stack resource.
+func ListResourceAll(c *gcorecloud.ServiceClient, stackID, resourceName string, opts ListOptsBuilder) ([]Event, error) {
+	pages, err := ListResource(c, stackID, resourceName, opts).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	return ExtractEvents(pages)
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/pagination"
+)
+
+// Event represents a stack resource status change.
+type Event struct {
+	ID                   string            `json:"id"`
+	EventTime            time.Time         `json:"event_time"`
+	Links                []gcorecloud.Link `json:"links"`
+	LogicalResourceID    string            `json:"logical_resource_id"`
+	PhysicalResourceID   string            `json:"physical_resource_id"`
+	ResourceName         string            `json:"resource_name"`
+	ResourceStatus       string            `json:"resource_status"`
+	ResourceStatusReason string            `json:"resource_status_reason"`
+	ResourceType         string            `json:"resource_type,omitempty"`
+}
+
+// EventPage is the page returned by a pager when traversing over a collection of stack events.
+type EventPage struct {
+	pagination.MarkerPageBase
+}
+
+// LastMarker returns the ID of the last event on the page.
+func (r EventPage) LastMarker() (string, error) {
+	events, err := ExtractEvents(r)
+	if err != nil || len(events) == 0 {
+		return "", err
+	}
+	return events[len(events)-1].ID, nil
+}
+
+// NextPageURL returns the URL of the page following the last event, or an empty string if the page is empty.
+func (r EventPage) NextPageURL() (string, error) {
+	marker, err := r.LastMarker()
+	if err != nil || marker == "" {
+		return "", err
+	}
+	return r.MarkerPageBase.NextPageURL()
+}
+
+// IsEmpty checks whether an EventPage struct is empty.
+func (r EventPage) IsEmpty() (bool, error) {
+	events, err := ExtractEvents(r)
+	return len(events) == 0, err
+}
+
+// ExtractEvents accepts a Page struct, specifically an EventPage struct, and extracts the elements into a slice of Event structs.
+func ExtractEvents(r pagination.Page) ([]Event, error) {
+	var s []Event
+	err := ExtractEventsInto(r, &s)
+	return s, err
+}
+
+func ExtractEventsInto(r pagination.Page, v interface{}) error {
+	return r.(EventPage).Result.ExtractIntoSlicePtr(v, "results")
+}
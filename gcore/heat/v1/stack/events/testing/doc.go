@@ -0,0 +1,2 @@
+// events unit tests
+package testing
@@ -0,0 +1,68 @@
+package testing
+
+import (
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"time"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/heat/v1/stack/events"
+)
+
+const ListResponse = `
+{
+  "count": 2,
+  "results": [
+    {
+      "id": "474bfdf0-a450-46ec-a78a-0c7faa404073",
+      "event_time": "2020-03-17T21:26:07+00:00",
+      "links": [],
+      "logical_resource_id": "etcd_lb",
+      "physical_resource_id": "",
+      "resource_name": "etcd_lb",
+      "resource_status": "CREATE_IN_PROGRESS",
+      "resource_status_reason": "state changed"
+    },
+    {
+      "id": "66fa95b6-e6f8-4f05-b1af-e828f5aba04c",
+      "event_time": "2020-03-17T21:27:11+00:00",
+      "links": [],
+      "logical_resource_id": "etcd_lb",
+      "physical_resource_id": "da7a7e75-28ff-4813-88a8-9ab583ac227f",
+      "resource_name": "etcd_lb",
+      "resource_status": "CREATE_FAILED",
+      "resource_status_reason": "Quota exceeded for resources: ['loadbalancer']"
+    }
+  ]
+}
+`
+
+const EmptyListResponse = `
+{
+  "count": 0,
+  "results": []
+}
+`
+
+var (
+	startTime, _ = time.Parse(time.RFC3339, "2020-03-17T21:26:07+00:00")
+	failTime, _  = time.Parse(time.RFC3339, "2020-03-17T21:27:11+00:00")
+	Event1       = events.Event{
+		ID:                   "474bfdf0-a450-46ec-a78a-0c7faa404073",
+		EventTime:            startTime,
+		Links:                []gcorecloud.Link{},
+		LogicalResourceID:    "etcd_lb",
+		ResourceName:         "etcd_lb",
+		ResourceStatus:       "CREATE_IN_PROGRESS",
+		ResourceStatusReason: "state changed",
+	}
+	Event2 = events.Event{
+		ID:                   "66fa95b6-e6f8-4f05-b1af-e828f5aba04c",
+		EventTime:            failTime,
+		Links:                []gcorecloud.Link{},
+		LogicalResourceID:    "etcd_lb",
+		PhysicalResourceID:   "da7a7e75-28ff-4813-88a8-9ab583ac227f",
+		ResourceName:         "etcd_lb",
+		ResourceStatus:       "CREATE_FAILED",
+		ResourceStatusReason: "Quota exceeded for resources: ['loadbalancer']",
+	}
+	ExpectedEvents = []events.Event{Event1, Event2}
+)
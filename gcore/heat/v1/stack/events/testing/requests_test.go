@@ -0,0 +1,89 @@
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/heat/v1/stack/events"
+	"github.com/G-Core/gcorelabscloud-go/pagination"
+	th "github.com/G-Core/gcorelabscloud-go/testhelper"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+
+	"github.com/stretchr/testify/require"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var stackID = "stack"
+var resourceName = "etcd_lb"
+
+func prepareListTestURL(stackID string) string {
+	return fmt.Sprintf("/v1/heat/%d/%d/stacks/%s/events", fake.ProjectID, fake.RegionID, stackID)
+}
+
+func prepareResourceListTestURL(stackID, resourceName string) string {
+	return fmt.Sprintf("/v1/heat/%d/%d/stacks/%s/resources/%s/events", fake.ProjectID, fake.RegionID, stackID, resourceName)
+}
+
+func handleMarkerPages(t *testing.T) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+		require.Equal(t, "asc", r.URL.Query().Get("sort_dir"))
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		body := ListResponse
+		if marker := r.URL.Query().Get("marker"); marker != "" {
+			require.Equal(t, Event2.ID, marker)
+			body = EmptyListResponse
+		}
+		_, err := fmt.Fprint(w, body)
+		if err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc(prepareListTestURL(stackID), handleMarkerPages(t))
+
+	client := fake.ServiceTokenClient("heat", "v1")
+	count := 0
+	err := events.List(client, stackID, events.ListOpts{SortDir: "asc"}).EachPage(func(page pagination.Page) (bool, error) {
+		count++
+		actual, err := events.ExtractEvents(page)
+		require.NoError(t, err)
+		require.Equal(t, ExpectedEvents, actual)
+		return true, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func TestListAll(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc(prepareListTestURL(stackID), handleMarkerPages(t))
+
+	client := fake.ServiceTokenClient("heat", "v1")
+	actual, err := events.ListAll(client, stackID, events.ListOpts{SortDir: "asc"})
+	require.NoError(t, err)
+	require.Equal(t, ExpectedEvents, actual)
+}
+
+func TestListResourceAll(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc(prepareResourceListTestURL(stackID, resourceName), handleMarkerPages(t))
+
+	client := fake.ServiceTokenClient("heat", "v1")
+	actual, err := events.ListResourceAll(client, stackID, resourceName, events.ListOpts{SortDir: "asc"})
+	require.NoError(t, err)
+	require.Equal(t, ExpectedEvents, actual)
+}
@@ -0,0 +1,155 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/heat/v1/stack/events"
+	"github.com/G-Core/gcorelabscloud-go/gcore/heat/v1/stack/resources"
+	th "github.com/G-Core/gcorelabscloud-go/testhelper"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+
+	"github.com/stretchr/testify/require"
+)
+
+func prepareStackTestURL(stackID string) string {
+	return fmt.Sprintf("/v1/heat/%d/%d/stacks/%s", fake.ProjectID, fake.RegionID, stackID)
+}
+
+func prepareResourcesTestURL(stackID string) string {
+	return fmt.Sprintf("/v1/heat/%d/%d/stacks/%s/resources", fake.ProjectID, fake.RegionID, stackID)
+}
+
+func eventsResponse(events ...string) string {
+	return fmt.Sprintf(`{"count": %d, "results": [%s]}`, len(events), strings.Join(events, ","))
+}
+
+func eventResponse(id, status, reason string) string {
+	return fmt.Sprintf(`{
+  "id": "%s",
+  "event_time": "2020-03-17T21:26:07+00:00",
+  "links": [],
+  "logical_resource_id": "etcd_lb",
+  "physical_resource_id": "",
+  "resource_name": "etcd_lb",
+  "resource_status": "%s",
+  "resource_status_reason": "%s"
+}`, id, status, reason)
+}
+
+// watchServer serves the stack and its resource with the given statuses on consecutive polls and the events pages
+// keyed by marker.
+type watchServer struct {
+	stackPolls    int
+	stackStatus   string
+	eventRequests int
+}
+
+func newWatchServer(t *testing.T, statuses []string, pages map[string]string) *watchServer {
+	s := &watchServer{}
+	th.Mux.HandleFunc(prepareStackTestURL(stackID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		s.stackStatus = statuses[s.stackPolls]
+		if s.stackPolls < len(statuses)-1 {
+			s.stackPolls++
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, `{"id": "%s", "stack_name": "test", "creation_time": "2020-03-17T21:26:07+00:00", "stack_status": "%s", "stack_status_reason": "Resource CREATE failed"}`, stackID, s.stackStatus)
+	})
+	th.Mux.HandleFunc(prepareListTestURL(stackID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		s.eventRequests++
+		body, ok := pages[r.URL.Query().Get("marker")]
+		require.True(t, ok, "unexpected marker %s", r.URL.Query().Get("marker"))
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, body)
+	})
+	th.Mux.HandleFunc(prepareResourcesTestURL(stackID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, `{"count": 1, "results": [{"resource_name": "etcd_lb", "creation_time": "2020-03-17T21:26:07+00:00", "resource_status": "%s"}]}`, s.stackStatus)
+	})
+	return s
+}
+
+func watchOpts(seen *[]string, transitions *[]string) events.WatchOpts {
+	return events.WatchOpts{
+		Interval: time.Millisecond,
+		Event: func(e events.Event) {
+			*seen = append(*seen, e.ID)
+		},
+		Transition: func(r resources.ResourceList, previous string) {
+			*transitions = append(*transitions, fmt.Sprintf("%s:%s->%s", r.ResourceName, previous, r.ResourceStatus))
+		},
+	}
+}
+
+func TestWatchFailed(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	server := newWatchServer(t, []string{"CREATE_IN_PROGRESS", "CREATE_FAILED"}, map[string]string{
+		"":   eventsResponse(eventResponse("e1", "CREATE_IN_PROGRESS", "state changed")),
+		"e1": eventsResponse(eventResponse("e2", "CREATE_IN_PROGRESS", "state changed")),
+		"e2": eventsResponse(eventResponse("e3", "CREATE_FAILED", "Quota exceeded for resources: ['loadbalancer']")),
+		"e3": EmptyListResponse,
+	})
+
+	var seen, transitions []string
+	client := fake.ServiceTokenClient("heat", "v1")
+	stack, err := events.Watch(context.Background(), client, stackID, watchOpts(&seen, &transitions))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "resource etcd_lb: Quota exceeded for resources: ['loadbalancer']")
+	require.Equal(t, "CREATE_FAILED", stack.StackStatus)
+	// a single events page is read while the stack is in progress, the remaining pages once it has failed
+	require.Equal(t, []string{"e1", "e2", "e3"}, seen)
+	require.Equal(t, 4, server.eventRequests)
+	require.Equal(t, []string{"etcd_lb:->CREATE_IN_PROGRESS", "etcd_lb:CREATE_IN_PROGRESS->CREATE_FAILED"}, transitions)
+}
+
+func TestWatchComplete(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	newWatchServer(t, []string{"CREATE_COMPLETE"}, map[string]string{
+		"":   eventsResponse(eventResponse("e1", "CREATE_IN_PROGRESS", "state changed")),
+		"e1": eventsResponse(eventResponse("e2", "CREATE_COMPLETE", "state changed")),
+		"e2": EmptyListResponse,
+	})
+
+	var seen, transitions []string
+	client := fake.ServiceTokenClient("heat", "v1")
+	stack, err := events.Watch(context.Background(), client, stackID, watchOpts(&seen, &transitions))
+	require.NoError(t, err)
+	require.Equal(t, "CREATE_COMPLETE", stack.StackStatus)
+	require.Equal(t, []string{"e1", "e2"}, seen)
+}
+
+func TestWatchContextDone(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	newWatchServer(t, []string{"CREATE_IN_PROGRESS"}, map[string]string{
+		"":   eventsResponse(eventResponse("e1", "CREATE_IN_PROGRESS", "state changed")),
+		"e1": EmptyListResponse,
+	})
+
+	var seen, transitions []string
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client := fake.ServiceTokenClient("heat", "v1")
+	_, err := events.Watch(ctx, client, stackID, watchOpts(&seen, &transitions))
+	require.True(t, errors.Is(err, context.Canceled))
+	require.Equal(t, []string{"e1"}, seen)
+
+	_, err = events.Watch(context.Background(), client, stackID, events.WatchOpts{})
+	require.Error(t, err)
+}
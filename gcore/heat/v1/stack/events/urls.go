@@ -0,0 +1,11 @@
+package events
+
+import gcorecloud "github.com/G-Core/gcorelabscloud-go"
+
+func listURL(c *gcorecloud.ServiceClient, stackID string) string {
+	return c.ServiceURL("stacks", stackID, "events")
+}
+
+func resourceListURL(c *gcorecloud.ServiceClient, stackID, resourceName string) string {
+	return c.ServiceURL("stacks", stackID, "resources", resourceName, "events")
+}
@@ -0,0 +1,147 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/heat/v1/stack/resources"
+	"github.com/G-Core/gcorelabscloud-go/gcore/heat/v1/stack/stacks"
+	"github.com/G-Core/gcorelabscloud-go/pagination"
+)
+
+// WatchOpts represents options used to watch a stack.
+type WatchOpts struct {
+	// Interval between stack polls.
+	Interval time.Duration
+	// Event is called with every new stack event.
+	Event func(event Event)
+	// Transition is called when a resource status changes. previous is empty the first time a resource is seen.
+	Transition func(resource resources.ResourceList, previous string)
+}
+
+// stackWatcher tails stack events and resource status transitions until the stack settles.
+type stackWatcher struct {
+	client   *gcorecloud.ServiceClient
+	stackID  string
+	opts     WatchOpts
+	marker   string
+	statuses map[string]string
+	failures map[string]string
+	failed   []string
+}
+
+// watchEvents reports the events after the marker. While the stack is in progress a single page is read per poll,
+// once the stack has settled all remaining pages are read so that the events leading to its status are reported.
+func (w *stackWatcher) watchEvents(settled bool) error {
+	err := List(w.client, w.stackID, ListOpts{Marker: w.marker, SortDir: "asc"}).EachPage(func(page pagination.Page) (bool, error) {
+		pageEvents, err := ExtractEvents(page)
+		if err != nil {
+			return false, err
+		}
+		for _, e := range pageEvents {
+			if w.opts.Event != nil {
+				w.opts.Event(e)
+			}
+			w.marker = e.ID
+			if strings.HasSuffix(e.ResourceStatus, "_FAILED") {
+				w.fail(e.ResourceName, e.ResourceStatusReason)
+			}
+		}
+		return settled, nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot list stack %s events: %w", w.stackID, err)
+	}
+	return nil
+}
+
+func (w *stackWatcher) watchTransitions() error {
+	all, err := resources.ListAll(w.client, w.stackID, nil)
+	if err != nil {
+		return fmt.Errorf("cannot list stack %s resources: %w", w.stackID, err)
+	}
+	for _, r := range all {
+		previous, ok := w.statuses[r.ResourceName]
+		if ok && previous == r.ResourceStatus {
+			continue
+		}
+		if w.opts.Transition != nil {
+			w.opts.Transition(r, previous)
+		}
+		w.statuses[r.ResourceName] = r.ResourceStatus
+		if strings.HasSuffix(r.ResourceStatus, "_FAILED") && r.ResourceStatusReason != nil {
+			w.fail(r.ResourceName, *r.ResourceStatusReason)
+		}
+	}
+	return nil
+}
+
+// fail records the first failure reason reported for a resource.
+func (w *stackWatcher) fail(resourceName, reason string) {
+	if _, ok := w.failures[resourceName]; ok {
+		return
+	}
+	w.failures[resourceName] = reason
+	w.failed = append(w.failed, resourceName)
+}
+
+func (w *stackWatcher) failureReason(stack *stacks.Stack) string {
+	reasons := make([]string, 0, len(w.failed))
+	for _, name := range w.failed {
+		reasons = append(reasons, fmt.Sprintf("resource %s: %s", name, w.failures[name]))
+	}
+	if len(reasons) == 0 && stack.StackStatusReason != nil {
+		reasons = append(reasons, *stack.StackStatusReason)
+	}
+	return strings.Join(reasons, "; ")
+}
+
+func stackFailed(status string) bool {
+	return strings.HasSuffix(status, "_FAILED") ||
+		strings.HasPrefix(status, "ROLLBACK_") && !strings.HasSuffix(status, "_IN_PROGRESS")
+}
+
+// Watch polls the stack until it reaches a COMPLETE or FAILED status or the context is done, reporting new events
+// and resource status transitions. A FAILED or rolled back stack is returned with an error carrying the status
+// reasons of the failing resources.
+func Watch(ctx context.Context, client *gcorecloud.ServiceClient, stackID string, opts WatchOpts) (*stacks.Stack, error) {
+	if opts.Interval <= 0 {
+		return nil, fmt.Errorf("watch interval must be positive")
+	}
+	w := &stackWatcher{
+		client:   client,
+		stackID:  stackID,
+		opts:     opts,
+		statuses: make(map[string]string),
+		failures: make(map[string]string),
+	}
+	for {
+		// the stack is fetched first so that events leading to its final status are reported before returning
+		stack, err := stacks.Get(client, stackID).Extract()
+		if err != nil {
+			return nil, err
+		}
+		failed := stackFailed(stack.StackStatus)
+		complete := strings.HasSuffix(stack.StackStatus, "_COMPLETE")
+		if err := w.watchEvents(failed || complete); err != nil {
+			return nil, err
+		}
+		if err := w.watchTransitions(); err != nil {
+			return nil, err
+		}
+		switch {
+		case failed:
+			return stack, fmt.Errorf("stack %s is %s: %s", stackID, stack.StackStatus, w.failureReason(stack))
+		case complete:
+			return stack, nil
+		}
+		select {
+		case <-ctx.Done():
+			return stack, fmt.Errorf("stack %s is %s: %w", stackID, stack.StackStatus, ctx.Err())
+		case <-time.After(opts.Interval):
+		}
+	}
+}
@@ -0,0 +1,570 @@
+package stacks
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LintSeverity is a severity of a template lint issue.
+type LintSeverity string
+
+const (
+	LintError   LintSeverity = "error"
+	LintWarning LintSeverity = "warning"
+)
+
+// LintIssue represents a problem found in a template.
+type LintIssue struct {
+	Severity LintSeverity `json:"severity"`
+	Path     string       `json:"path"`
+	Message  string       `json:"message"`
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Severity, i.Path, i.Message)
+}
+
+// LintIssues is a list of template lint issues.
+type LintIssues []LintIssue
+
+// HasErrors checks whether any of the issues is an error.
+func (l LintIssues) HasErrors() bool {
+	for _, i := range l {
+		if i.Severity == LintError {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *LintIssues) add(severity LintSeverity, path, format string, args ...interface{}) {
+	*l = append(*l, LintIssue{Severity: severity, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// ResourceSchema describes a supported resource type.
+type ResourceSchema struct {
+	Required []string
+}
+
+// SupportedResourceTypes contains resource types supported by GCore Heat with their required properties.
+var SupportedResourceTypes = map[string]ResourceSchema{
+	"OS::Cinder::Volume":                 {},
+	"OS::Cinder::VolumeAttachment":       {Required: []string{"instance_uuid", "volume_id"}},
+	"OS::Heat::CloudConfig":              {},
+	"OS::Heat::MultipartMime":            {Required: []string{"parts"}},
+	"OS::Heat::None":                     {},
+	"OS::Heat::RandomString":             {},
+	"OS::Heat::ResourceGroup":            {Required: []string{"resource_def"}},
+	"OS::Heat::SoftwareConfig":           {},
+	"OS::Heat::Value":                    {Required: []string{"value"}},
+	"OS::Heat::WaitCondition":            {Required: []string{"handle"}},
+	"OS::Heat::WaitConditionHandle":      {},
+	"OS::Neutron::FloatingIP":            {Required: []string{"floating_network"}},
+	"OS::Neutron::FloatingIPAssociation": {Required: []string{"floatingip_id", "port_id"}},
+	"OS::Neutron::Net":                   {},
+	"OS::Neutron::Port":                  {Required: []string{"network"}},
+	"OS::Neutron::Router":                {},
+	"OS::Neutron::RouterInterface":       {Required: []string{"router"}},
+	"OS::Neutron::SecurityGroup":         {},
+	"OS::Neutron::SecurityGroupRule":     {Required: []string{"security_group"}},
+	"OS::Neutron::Subnet":                {Required: []string{"network"}},
+	"OS::Nova::KeyPair":                  {Required: []string{"name"}},
+	"OS::Nova::Server":                   {Required: []string{"flavor"}},
+	"OS::Nova::ServerGroup":              {},
+	"OS::Octavia::HealthMonitor":         {Required: []string{"delay", "max_retries", "pool", "timeout", "type"}},
+	"OS::Octavia::Listener":              {Required: []string{"protocol"}},
+	"OS::Octavia::LoadBalancer":          {Required: []string{"vip_subnet"}},
+	"OS::Octavia::Pool":                  {Required: []string{"lb_algorithm", "protocol"}},
+	"OS::Octavia::PoolMember":            {Required: []string{"address", "pool", "protocol_port"}},
+}
+
+// HOTVersions contains supported heat_template_version values.
+var HOTVersions = map[string]bool{
+	"2013-05-23": true, "2014-10-16": true, "2015-04-30": true, "2015-10-15": true,
+	"2016-04-08": true, "2016-10-14": true, "2017-02-24": true, "2017-09-01": true,
+	"2018-03-02": true, "2018-08-31": true, "newton": true, "ocata": true,
+	"pike": true, "queens": true, "rocky": true,
+}
+
+// HOTSections contains allowed top level sections of a HOT template.
+var HOTSections = map[string]bool{
+	"heat_template_version": true,
+	"description":           true,
+	"parameter_groups":      true,
+	"parameters":            true,
+	"resources":             true,
+	"outputs":               true,
+	"conditions":            true,
+}
+
+var pseudoParameters = map[string]bool{
+	"OS::stack_name": true,
+	"OS::stack_id":   true,
+	"OS::project_id": true,
+}
+
+// LintOpts represents options of the offline template validation.
+type LintOpts struct {
+	// Parameters passed to the stack, checked against parameter types and constraints.
+	// Parameters not defined in the template are reported.
+	Parameters map[string]interface{}
+	// ParameterDefaults are used for parameters without a value in Parameters. As they also apply
+	// to nested templates, defaults of parameters not defined in the template are not reported.
+	ParameterDefaults map[string]interface{}
+	// ResourceTypes are additional supported resource types, e.g. from the environment resource registry.
+	ResourceTypes map[string]ResourceSchema
+}
+
+// Lint validates the HOT template offline: sections, resource types and required properties,
+// parameter values against their types and constraints, and get_resource, get_attr, get_param
+// and depends_on references. An error is returned only if the template can not be parsed.
+func (t *Template) Lint(opts LintOpts) (LintIssues, error) {
+	if t.Parsed == nil {
+		if err := t.Parse(); err != nil {
+			return nil, err
+		}
+	}
+	issues := LintIssues{}
+
+	version, ok := t.Parsed["heat_template_version"]
+	switch {
+	case !ok:
+		issues.add(LintError, "heat_template_version", "is missing")
+	default:
+		v := fmt.Sprint(version)
+		if tm, ok := version.(time.Time); ok {
+			v = tm.Format("2006-01-02")
+		}
+		if !HOTVersions[v] {
+			issues.add(LintError, "heat_template_version", "unsupported version %s", v)
+		}
+	}
+	for _, key := range sortedKeys(t.Parsed) {
+		if !HOTSections[key] {
+			issues.add(LintError, key, "unknown section")
+		}
+	}
+
+	parameters, err := toStringKeys(orEmptyMap(t.Parsed["parameters"]))
+	if err != nil {
+		issues.add(LintError, "parameters", "should be a map")
+	}
+	resources, err := toStringKeys(orEmptyMap(t.Parsed["resources"]))
+	if err != nil {
+		issues.add(LintError, "resources", "should be a map")
+	}
+	if len(resources) == 0 {
+		issues.add(LintWarning, "resources", "template has no resources")
+	}
+
+	lintParameters(&issues, parameters, opts.Parameters, opts.ParameterDefaults)
+	for _, name := range sortedKeys(resources) {
+		lintResource(&issues, name, resources[name], opts.ResourceTypes)
+	}
+
+	refs := referenceChecker{issues: &issues, parameters: parameters, resources: resources}
+	for _, name := range sortedKeys(resources) {
+		refs.walk("resources."+name, resources[name])
+	}
+	if outputs, err := toStringKeys(orEmptyMap(t.Parsed["outputs"])); err == nil {
+		for _, name := range sortedKeys(outputs) {
+			refs.walk("outputs."+name, outputs[name])
+		}
+	} else {
+		issues.add(LintError, "outputs", "should be a map")
+	}
+	return issues, nil
+}
+
+// ResourceRegistryTypes returns resource types mapped in the environment resource registry.
+func (e *Environment) ResourceRegistryTypes() map[string]ResourceSchema {
+	result := make(map[string]ResourceSchema)
+	rr, err := toStringKeys(orEmptyMap(e.Parsed["resource_registry"]))
+	if err != nil {
+		return result
+	}
+	for key := range rr {
+		if key != "resources" && key != "base_url" {
+			result[key] = ResourceSchema{}
+		}
+	}
+	return result
+}
+
+// Lint validates the template offline with the stack parameters. Parameters and parameter defaults
+// of the environment and its resource registry types are taken into account.
+func (opts CreateOpts) Lint() (LintIssues, error) {
+	if opts.TemplateOpts == nil {
+		return nil, ErrTemplateRequired{}
+	}
+	lintOpts := LintOpts{Parameters: make(map[string]interface{}), ParameterDefaults: make(map[string]interface{})}
+	if opts.EnvironmentOpts != nil {
+		if err := opts.EnvironmentOpts.Parse(); err != nil {
+			return nil, err
+		}
+		if err := opts.EnvironmentOpts.Validate(); err != nil {
+			return nil, err
+		}
+		for section, target := range map[string]map[string]interface{}{
+			"parameter_defaults": lintOpts.ParameterDefaults,
+			"parameters":         lintOpts.Parameters,
+		} {
+			values, err := toStringKeys(orEmptyMap(opts.EnvironmentOpts.Parsed[section]))
+			if err != nil {
+				return nil, ErrInvalidEnvironment{Section: section}
+			}
+			for k, v := range values {
+				target[k] = v
+			}
+		}
+		lintOpts.ResourceTypes = opts.EnvironmentOpts.ResourceRegistryTypes()
+	}
+	for k, v := range opts.Parameters {
+		lintOpts.Parameters[k] = v
+	}
+	return opts.TemplateOpts.Lint(lintOpts)
+}
+
+func orEmptyMap(v interface{}) interface{} {
+	if v == nil {
+		return map[string]interface{}{}
+	}
+	return v
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func lintResource(issues *LintIssues, name string, definition interface{}, extraTypes map[string]ResourceSchema) {
+	path := "resources." + name
+	resource, err := toStringKeys(definition)
+	if err != nil {
+		issues.add(LintError, path, "should be a map")
+		return
+	}
+	resourceType, _ := resource["type"].(string)
+	if resourceType == "" {
+		issues.add(LintError, path+".type", "is missing")
+		return
+	}
+	schema, ok := SupportedResourceTypes[resourceType]
+	if !ok {
+		schema, ok = extraTypes[resourceType]
+	}
+	if !ok {
+		if strings.HasSuffix(resourceType, ".yaml") || strings.HasSuffix(resourceType, ".template") {
+			return
+		}
+		issues.add(LintError, path+".type", "unsupported resource type %s", resourceType)
+		return
+	}
+	properties, err := toStringKeys(orEmptyMap(resource["properties"]))
+	if err != nil {
+		issues.add(LintError, path+".properties", "should be a map")
+		return
+	}
+	for _, p := range schema.Required {
+		if _, ok := properties[p]; !ok {
+			issues.add(LintError, path+".properties."+p, "required property of %s is missing", resourceType)
+		}
+	}
+}
+
+func lintParameters(issues *LintIssues, parameters, values, defaults map[string]interface{}) {
+	for _, name := range sortedKeys(parameters) {
+		path := "parameters." + name
+		definition, err := toStringKeys(parameters[name])
+		if err != nil {
+			issues.add(LintError, path, "should be a map")
+			continue
+		}
+		paramType, _ := definition["type"].(string)
+		value, provided := values[name]
+		if !provided {
+			value, provided = defaults[name]
+		}
+		if !provided {
+			value, provided = definition["default"]
+		}
+		if !provided || value == nil {
+			issues.add(LintError, path, "no value provided and no default")
+			continue
+		}
+		normalised, err := checkParameterType(paramType, value)
+		if err != nil {
+			issues.add(LintError, path, "%s", err)
+			continue
+		}
+		constraints, ok := definition["constraints"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, c := range constraints {
+			constraint, err := toStringKeys(c)
+			if err != nil {
+				issues.add(LintError, path+".constraints", "constraint should be a map")
+				continue
+			}
+			if err := checkConstraint(constraint, normalised); err != nil {
+				issues.add(LintError, path, "%s", err)
+			}
+		}
+	}
+	for _, name := range sortedMapKeys(values) {
+		if _, ok := parameters[name]; !ok {
+			issues.add(LintError, "parameters."+name, "parameter is not defined in template")
+		}
+	}
+}
+
+func sortedMapKeys(m map[string]interface{}) []string {
+	if m == nil {
+		return nil
+	}
+	return sortedKeys(m)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// checkParameterType checks the value against the parameter type and returns the value
+// to check constraints against: a number, a string, a list or a parsed json.
+func checkParameterType(paramType string, value interface{}) (interface{}, error) {
+	switch paramType {
+	case "string":
+		switch value.(type) {
+		case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+			return nil, fmt.Errorf("value should be a string")
+		}
+		return fmt.Sprint(value), nil
+	case "number":
+		f, ok := toFloat(value)
+		if !ok {
+			return nil, fmt.Errorf("value %v is not a number", value)
+		}
+		return f, nil
+	case "boolean":
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			switch strings.ToLower(v) {
+			case "true", "t", "yes", "y", "on", "1":
+				return true, nil
+			case "false", "f", "no", "n", "off", "0":
+				return false, nil
+			}
+		case int:
+			if v == 0 || v == 1 {
+				return v == 1, nil
+			}
+		}
+		return nil, fmt.Errorf("value %v is not a boolean", value)
+	case "comma_delimited_list":
+		switch v := value.(type) {
+		case []interface{}:
+			return v, nil
+		case string:
+			if v == "" {
+				return []interface{}{}, nil
+			}
+			var result []interface{}
+			for _, s := range strings.Split(v, ",") {
+				result = append(result, strings.TrimSpace(s))
+			}
+			return result, nil
+		}
+		return nil, fmt.Errorf("value %v is not a comma delimited list", value)
+	case "json":
+		if s, ok := value.(string); ok {
+			var parsed interface{}
+			if err := json.Unmarshal([]byte(s), &parsed); err != nil {
+				return nil, fmt.Errorf("value is not a valid json: %w", err)
+			}
+			return parsed, nil
+		}
+		return value, nil
+	case "":
+		return nil, fmt.Errorf("type is missing")
+	}
+	return nil, fmt.Errorf("unknown type %s", paramType)
+}
+
+func valueLength(v interface{}) (int, bool) {
+	switch value := v.(type) {
+	case string:
+		return len(value), true
+	case []interface{}:
+		return len(value), true
+	case map[string]interface{}:
+		return len(value), true
+	case map[interface{}]interface{}:
+		return len(value), true
+	}
+	return 0, false
+}
+
+func checkBounds(kind string, bounds interface{}, actual float64) error {
+	b, err := toStringKeys(bounds)
+	if err != nil {
+		return fmt.Errorf("%s constraint should be a map", kind)
+	}
+	if min, ok := toFloat(b["min"]); ok && actual < min {
+		return fmt.Errorf("%s %v is less than %v", kind, actual, min)
+	}
+	if max, ok := toFloat(b["max"]); ok && actual > max {
+		return fmt.Errorf("%s %v is greater than %v", kind, actual, max)
+	}
+	return nil
+}
+
+func checkConstraint(constraint map[string]interface{}, value interface{}) error {
+	if bounds, ok := constraint["length"]; ok {
+		length, ok := valueLength(value)
+		if !ok {
+			return fmt.Errorf("length constraint is not applicable")
+		}
+		if err := checkBounds("length", bounds, float64(length)); err != nil {
+			return err
+		}
+	}
+	if bounds, ok := constraint["range"]; ok {
+		number, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("range constraint is applicable to numbers only")
+		}
+		if err := checkBounds("value", bounds, number); err != nil {
+			return err
+		}
+	}
+	if allowed, ok := constraint["allowed_values"]; ok {
+		values, ok := allowed.([]interface{})
+		if !ok {
+			return fmt.Errorf("allowed_values constraint should be a list")
+		}
+		items := []interface{}{value}
+		if list, ok := value.([]interface{}); ok {
+			items = list
+		}
+		for _, item := range items {
+			found := false
+			for _, a := range values {
+				if fmt.Sprint(a) == fmt.Sprint(item) {
+					found = true
+					break
+				}
+				if x, ok := toFloat(a); ok {
+					if y, ok := item.(float64); ok && x == y {
+						found = true
+						break
+					}
+				}
+			}
+			if !found {
+				return fmt.Errorf("value %v is not allowed", item)
+			}
+		}
+	}
+	if pattern, ok := constraint["allowed_pattern"]; ok {
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("allowed_pattern constraint is applicable to strings only")
+		}
+		re, err := regexp.Compile("^(?:" + fmt.Sprint(pattern) + ")$")
+		if err != nil {
+			return fmt.Errorf("invalid allowed_pattern %v: %w", pattern, err)
+		}
+		if !re.MatchString(s) {
+			return fmt.Errorf("value %s does not match pattern %v", s, pattern)
+		}
+	}
+	return nil
+}
+
+type referenceChecker struct {
+	issues     *LintIssues
+	parameters map[string]interface{}
+	resources  map[string]interface{}
+}
+
+func (r referenceChecker) checkResource(path, function string, name interface{}) {
+	s, ok := name.(string)
+	if !ok {
+		r.issues.add(LintError, path, "%s should reference a resource name", function)
+		return
+	}
+	if _, ok := r.resources[s]; !ok {
+		r.issues.add(LintError, path, "%s references unknown resource %s", function, s)
+	}
+}
+
+func (r referenceChecker) walk(path string, node interface{}) {
+	switch n := node.(type) {
+	case []interface{}:
+		for idx, item := range n {
+			r.walk(fmt.Sprintf("%s[%d]", path, idx), item)
+		}
+	case map[string]interface{}, map[interface{}]interface{}:
+		m, err := toStringKeys(n)
+		if err != nil {
+			r.issues.add(LintError, path, "%s", err)
+			return
+		}
+		for _, key := range sortedKeys(m) {
+			value := m[key]
+			switch key {
+			case "get_resource":
+				r.checkResource(path, key, value)
+			case "get_attr":
+				if args, ok := value.([]interface{}); ok && len(args) > 0 {
+					r.checkResource(path, key, args[0])
+				} else {
+					r.issues.add(LintError, path, "get_attr should be a list of a resource name and an attribute")
+				}
+			case "depends_on":
+				if list, ok := value.([]interface{}); ok {
+					for _, item := range list {
+						r.checkResource(path, key, item)
+					}
+				} else {
+					r.checkResource(path, key, value)
+				}
+			case "get_param":
+				name := value
+				if args, ok := value.([]interface{}); ok && len(args) > 0 {
+					name = args[0]
+				}
+				s, ok := name.(string)
+				if !ok {
+					r.issues.add(LintError, path, "get_param should reference a parameter name")
+				} else if _, defined := r.parameters[s]; !defined && !pseudoParameters[s] {
+					r.issues.add(LintError, path, "get_param references unknown parameter %s", s)
+				}
+			}
+			r.walk(path+"."+key, value)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package stacks
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/G-Core/gcorelabscloud-go/gcore/heat/v1/stack/stacks/types"
@@ -319,3 +320,29 @@ func Delete(c *gcorecloud.ServiceClient, stackID string) (r DeleteResult) {
 	_, r.Err = c.Delete(deleteURL(c, stackID), nil)
 	return
 }
+
+// UpdatePreview shows the resources which would be added, replaced, updated and deleted
+// if the stack were updated with Update and the same options. Nothing is changed.
+func UpdatePreview(c *gcorecloud.ServiceClient, stackID string, opts UpdateOptsBuilder) (r PreviewResult) {
+	b, err := opts.ToStackUpdateMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	_, r.Err = c.Put(previewURL(c, stackID), b, &r.Body, &gcorecloud.RequestOpts{
+		OkCodes: []int{http.StatusOK},
+	})
+	return
+}
+
+// UpdatePatchPreview shows the resources which would be added, replaced, updated and deleted
+// if the stack were updated with UpdatePatch and the same options. Nothing is changed.
+func UpdatePatchPreview(c *gcorecloud.ServiceClient, stackID string, opts UpdatePatchOptsBuilder) (r PreviewResult) {
+	b, err := opts.ToStackUpdatePatchMap()
+	if err != nil {
+		r.Err = err
+		return
+	}
+	_, r.Err = c.Patch(previewURL(c, stackID), b, &r.Body, nil)
+	return
+}
@@ -124,3 +124,85 @@ func ExtractStacks(r pagination.Page) ([]StackList, error) {
 func ExtractStacksInto(r pagination.Page, v interface{}) error {
 	return r.(StackPage).Result.ExtractIntoSlicePtr(v, "results")
 }
+
+// PreviewResult represents the result of an update preview operation.
+type PreviewResult struct {
+	gcorecloud.Result
+}
+
+// Extract is a function that accepts a result and extracts the resource changes of an update preview.
+func (r PreviewResult) Extract() (*ResourceChanges, error) {
+	var s struct {
+		ResourceChanges *ResourceChanges `json:"resource_changes"`
+	}
+	err := r.ExtractInto(&s)
+	if err == nil && s.ResourceChanges == nil {
+		s.ResourceChanges = &ResourceChanges{}
+	}
+	return s.ResourceChanges, err
+}
+
+// ResourceChangeAction is the kind of change an update would make to a stack resource.
+type ResourceChangeAction string
+
+const (
+	ResourceChangeAdded     ResourceChangeAction = "added"
+	ResourceChangeReplaced  ResourceChangeAction = "replaced"
+	ResourceChangeUpdated   ResourceChangeAction = "updated"
+	ResourceChangeDeleted   ResourceChangeAction = "deleted"
+	ResourceChangeUnchanged ResourceChangeAction = "unchanged"
+)
+
+// PreviewResource represents a stack resource in an update preview.
+type PreviewResource struct {
+	ResourceName       string `json:"resource_name"`
+	ResourceType       string `json:"resource_type"`
+	ResourceAction     string `json:"resource_action"`
+	ResourceStatus     string `json:"resource_status"`
+	PhysicalResourceID string `json:"physical_resource_id"`
+}
+
+// ResourceChanges groups the stack resources of an update preview by the change an update would make.
+type ResourceChanges struct {
+	Added     []PreviewResource `json:"added"`
+	Replaced  []PreviewResource `json:"replaced"`
+	Updated   []PreviewResource `json:"updated"`
+	Deleted   []PreviewResource `json:"deleted"`
+	Unchanged []PreviewResource `json:"unchanged"`
+}
+
+// ResourceChange is a single change of a stack update preview.
+type ResourceChange struct {
+	Action             ResourceChangeAction `json:"action"`
+	ResourceName       string               `json:"resource_name"`
+	ResourceType       string               `json:"resource_type"`
+	PhysicalResourceID string               `json:"physical_resource_id,omitempty"`
+}
+
+// IsEmpty checks whether the update would leave all stack resources unchanged.
+func (rc ResourceChanges) IsEmpty() bool {
+	return len(rc.Added) == 0 && len(rc.Replaced) == 0 && len(rc.Updated) == 0 && len(rc.Deleted) == 0
+}
+
+// Changes flattens the preview into a list of changes ordered as added, replaced, updated and deleted.
+// Unchanged resources are included only if withUnchanged is set.
+func (rc ResourceChanges) Changes(withUnchanged bool) []ResourceChange {
+	actions := []ResourceChangeAction{ResourceChangeAdded, ResourceChangeReplaced, ResourceChangeUpdated, ResourceChangeDeleted}
+	groups := [][]PreviewResource{rc.Added, rc.Replaced, rc.Updated, rc.Deleted}
+	if withUnchanged {
+		actions = append(actions, ResourceChangeUnchanged)
+		groups = append(groups, rc.Unchanged)
+	}
+	var result []ResourceChange
+	for idx, group := range groups {
+		for _, r := range group {
+			result = append(result, ResourceChange{
+				Action:             actions[idx],
+				ResourceName:       r.ResourceName,
+				ResourceType:       r.ResourceType,
+				PhysicalResourceID: r.PhysicalResourceID,
+			})
+		}
+	}
+	return result
+}
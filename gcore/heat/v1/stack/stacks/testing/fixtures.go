@@ -168,3 +168,54 @@ var (
 
 	ExpectedStackList1 = []stacks.StackList{StackList1}
 )
+
+// PreviewResponse represents the response body from an update preview request.
+const PreviewResponse = `
+{
+  "resource_changes": {
+    "added": [
+      {
+        "resource_name": "server_group",
+        "resource_type": "OS::Nova::ServerGroup",
+        "resource_action": "INIT",
+        "resource_status": "COMPLETE",
+        "physical_resource_id": ""
+      }
+    ],
+    "deleted": [],
+    "replaced": [
+      {
+        "resource_name": "server",
+        "resource_type": "OS::Nova::Server",
+        "resource_action": "CREATE",
+        "resource_status": "COMPLETE",
+        "physical_resource_id": "a1b2c3d4-0000-4000-8000-000000000001"
+      }
+    ],
+    "unchanged": [
+      {
+        "resource_name": "network",
+        "resource_type": "OS::Neutron::Net",
+        "resource_action": "CREATE",
+        "resource_status": "COMPLETE",
+        "physical_resource_id": "a1b2c3d4-0000-4000-8000-000000000002"
+      }
+    ],
+    "updated": []
+  }
+}
+`
+
+var PreviewChanges = []stacks.ResourceChange{
+	{
+		Action:       stacks.ResourceChangeAdded,
+		ResourceName: "server_group",
+		ResourceType: "OS::Nova::ServerGroup",
+	},
+	{
+		Action:             stacks.ResourceChangeReplaced,
+		ResourceName:       "server",
+		ResourceType:       "OS::Nova::Server",
+		PhysicalResourceID: "a1b2c3d4-0000-4000-8000-000000000001",
+	},
+}
@@ -0,0 +1,161 @@
+package testing
+
+import (
+	"testing"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/heat/v1/stack/stacks"
+
+	"github.com/stretchr/testify/require"
+)
+
+const LintTemplate = `
+heat_template_version: 2016-10-14
+description: lint test
+parameters:
+  flavor:
+    type: string
+    default: g1-standard-1-2
+    constraints:
+      - allowed_pattern: "g[0-9]-.*"
+  count:
+    type: number
+    constraints:
+      - range: {min: 1, max: 3}
+  zones:
+    type: comma_delimited_list
+    default: "a,b"
+    constraints:
+      - allowed_values: [a, b, c]
+      - length: {max: 2}
+  debug:
+    type: boolean
+    default: "yes"
+resources:
+  network:
+    type: OS::Neutron::Net
+  subnet:
+    type: OS::Neutron::Subnet
+    properties:
+      network: {get_resource: network}
+      cidr: 192.168.0.0/24
+  server:
+    type: OS::Nova::Server
+    depends_on: [subnet]
+    properties:
+      flavor: {get_param: flavor}
+      name: {get_param: OS::stack_name}
+      networks:
+        - network: {get_resource: network}
+outputs:
+  address:
+    value: {get_attr: [server, first_address]}
+`
+
+const BrokenTemplate = `
+heat_template_version: 2016-10-14
+parameter: {}
+parameters:
+  size:
+    type: number
+    default: ten
+resources:
+  volume:
+    type: OS::Cinder::Volume
+    properties:
+      size: {get_param: volume_size}
+  attachment:
+    type: OS::Cinder::VolumeAttachment
+    depends_on: missing
+    properties:
+      volume_id: {get_resource: volume}
+  custom:
+    type: GCore::Custom::Thing
+  nested:
+    type: nested.yaml
+outputs:
+  server:
+    value: {get_attr: [server, name]}
+`
+
+func lintTemplate(t *testing.T, content string, params map[string]interface{}) stacks.LintIssues {
+	template := &stacks.Template{}
+	template.TE = stacks.TE{Bin: []byte(content)}
+	issues, err := template.Lint(stacks.LintOpts{Parameters: params})
+	require.NoError(t, err)
+	return issues
+}
+
+func TestLintValidTemplate(t *testing.T) {
+	issues := lintTemplate(t, LintTemplate, map[string]interface{}{"count": "2"})
+	require.Len(t, issues, 0)
+	require.False(t, issues.HasErrors())
+}
+
+func TestLintParameters(t *testing.T) {
+	issues := lintTemplate(t, LintTemplate, map[string]interface{}{
+		"count":   5,
+		"flavor":  "x1",
+		"zones":   "a,d",
+		"debug":   "maybe",
+		"unknown": "value",
+	})
+	paths := make(map[string]string)
+	for _, i := range issues {
+		require.Equal(t, stacks.LintError, i.Severity)
+		paths[i.Path] = i.Message
+	}
+	require.Equal(t, map[string]string{
+		"parameters.count":   "value 5 is greater than 3",
+		"parameters.flavor":  "value x1 does not match pattern g[0-9]-.*",
+		"parameters.zones":   "value d is not allowed",
+		"parameters.debug":   "value maybe is not a boolean",
+		"parameters.unknown": "parameter is not defined in template",
+	}, paths)
+
+	issues = lintTemplate(t, LintTemplate, nil)
+	require.Len(t, issues, 1)
+	require.Equal(t, "parameters.count", issues[0].Path)
+}
+
+func TestLintReferences(t *testing.T) {
+	issues := lintTemplate(t, BrokenTemplate, nil)
+	var messages []string
+	for _, i := range issues {
+		messages = append(messages, i.String())
+	}
+	require.ElementsMatch(t, []string{
+		"error: parameter: unknown section",
+		"error: parameters.size: value ten is not a number",
+		"error: resources.attachment.properties.instance_uuid: required property of OS::Cinder::VolumeAttachment is missing",
+		"error: resources.custom.type: unsupported resource type GCore::Custom::Thing",
+		"error: resources.attachment: depends_on references unknown resource missing",
+		"error: resources.volume.properties.size: get_param references unknown parameter volume_size",
+		"error: outputs.server.value: get_attr references unknown resource server",
+	}, messages)
+}
+
+func TestCreateOptsLint(t *testing.T) {
+	template := &stacks.Template{}
+	template.TE = stacks.TE{Bin: []byte(BrokenTemplate)}
+	env := &stacks.Environment{}
+	env.TE = stacks.TE{Bin: []byte("parameter_defaults:\n  size: 10\nresource_registry:\n  GCore::Custom::Thing: custom.yaml\n")}
+	issues, err := stacks.CreateOpts{Name: "stack", TemplateOpts: template, EnvironmentOpts: env}.Lint()
+	require.NoError(t, err)
+	require.Len(t, issues, 5)
+
+	// parameter defaults may be meant for nested templates
+	env = &stacks.Environment{}
+	env.TE = stacks.TE{Bin: []byte("parameter_defaults:\n  size: 10\n  nested_only: x\nparameters:\n  unknown: y\n")}
+	issues, err = stacks.CreateOpts{Name: "stack", TemplateOpts: template, EnvironmentOpts: env}.Lint()
+	require.NoError(t, err)
+	var paths []string
+	for _, i := range issues {
+		paths = append(paths, i.Path)
+	}
+	require.Contains(t, paths, "parameters.unknown")
+	require.NotContains(t, paths, "parameters.nested_only")
+	require.NotContains(t, paths, "parameters.size")
+
+	_, err = stacks.CreateOpts{Name: "stack"}.Lint()
+	require.Error(t, err)
+}
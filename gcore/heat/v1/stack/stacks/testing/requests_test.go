@@ -300,3 +300,60 @@ func TestUpdatePatch(t *testing.T) {
 	require.NoError(t, err)
 
 }
+
+func TestUpdatePreview(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	testURL := prepareUpdateTestURL(Stack1.ID) + "/preview"
+
+	th.Mux.HandleFunc(testURL, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestHeader(t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+		th.TestHeader(t, r, "Content-Type", "application/json")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := fmt.Fprint(w, PreviewResponse)
+		if err != nil {
+			log.Error(err)
+		}
+	})
+
+	template := new(stacks.Template)
+	template.Bin = []byte(`{"heat_template_version": "2013-05-23"}`)
+
+	client := fake.ServiceTokenClient("heat", "v1")
+	changes, err := stacks.UpdatePreview(client, Stack1.ID, stacks.UpdateOpts{TemplateOpts: template}).Extract()
+	require.NoError(t, err)
+	require.False(t, changes.IsEmpty())
+	require.Equal(t, PreviewChanges, changes.Changes(false))
+	require.Len(t, changes.Changes(true), 3)
+
+	_, err = stacks.UpdatePreview(client, Stack1.ID, stacks.UpdateOpts{}).Extract()
+	require.Equal(t, stacks.ErrTemplateRequired{}, err)
+}
+
+func TestUpdatePatchPreview(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	testURL := prepareUpdateTestURL(Stack1.ID) + "/preview"
+
+	th.Mux.HandleFunc(testURL, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PATCH")
+		th.TestJSONRequest(t, r, `{"parameters": {"flavor": "m1.tiny"}}`)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := fmt.Fprint(w, `{"resource_changes": {"updated": [], "unchanged": []}}`)
+		if err != nil {
+			log.Error(err)
+		}
+	})
+
+	client := fake.ServiceTokenClient("heat", "v1")
+	opts := stacks.UpdateOpts{Parameters: map[string]interface{}{"flavor": "m1.tiny"}}
+	changes, err := stacks.UpdatePatchPreview(client, Stack1.ID, opts).Extract()
+	require.NoError(t, err)
+	require.True(t, changes.IsEmpty())
+	require.Len(t, changes.Changes(false), 0)
+}
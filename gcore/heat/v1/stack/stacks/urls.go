@@ -29,3 +29,7 @@ func listURL(c *gcorecloud.ServiceClient) string {
 func createURL(c *gcorecloud.ServiceClient) string {
 	return rootURL(c)
 }
+
+func previewURL(c *gcorecloud.ServiceClient, stackID string) string {
+	return c.ServiceURL("stacks", stackID, "preview")
+}
@@ -0,0 +1,359 @@
+package images
+
+import (
+	"bytes"
+	"crypto/md5" // nolint:gosec
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/image/v1/images/types"
+	"github.com/G-Core/gcorelabscloud-go/gcore/task/v1/tasks"
+	uuid "github.com/satori/go.uuid"
+)
+
+// UploadFileWaitSeconds is the default time to wait for the API to fetch a local image.
+const UploadFileWaitSeconds = 3600
+
+// LocalImageFormat is a disk format detected from the image file header.
+type LocalImageFormat string
+
+const (
+	LocalImageQcow2 LocalImageFormat = "qcow2"
+	LocalImageVMDK  LocalImageFormat = "vmdk"
+	LocalImageVHD   LocalImageFormat = "vhd"
+	LocalImageVHDX  LocalImageFormat = "vhdx"
+	LocalImageISO   LocalImageFormat = "iso"
+	LocalImageRaw   LocalImageFormat = "raw"
+)
+
+// LocalImage represents an image file with its checksums.
+type LocalImage struct {
+	Path   string           `json:"path"`
+	Size   int64            `json:"size"`
+	Format LocalImageFormat `json:"format"`
+	// PartitionTable is "gpt" or "mbr" for raw images with a partition table.
+	PartitionTable string `json:"partition_table,omitempty"`
+	// MD5 and SHA256 are reported to compare against a published checksum, the API does not return image checksums.
+	MD5    string `json:"md5"`
+	SHA256 string `json:"sha256"`
+}
+
+func detectFormat(f *os.File, size int64) (LocalImageFormat, string, error) {
+	header := make([]byte, 0x8006)
+	n, err := f.ReadAt(header, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", "", err
+	}
+	header = header[:n]
+	switch {
+	case bytes.HasPrefix(header, []byte("QFI\xfb")):
+		return LocalImageQcow2, "", nil
+	case bytes.HasPrefix(header, []byte("KDMV")), bytes.HasPrefix(header, []byte("# Disk DescriptorFile")):
+		return LocalImageVMDK, "", nil
+	case bytes.HasPrefix(header, []byte("vhdxfile")):
+		return LocalImageVHDX, "", nil
+	case len(header) >= 0x8006 && bytes.Equal(header[0x8001:0x8006], []byte("CD001")):
+		return LocalImageISO, "", nil
+	}
+	if size >= 512 {
+		footer := make([]byte, 8)
+		if _, err := f.ReadAt(footer, size-512); err != nil {
+			return "", "", err
+		}
+		if bytes.Equal(footer, []byte("conectix")) {
+			return LocalImageVHD, "", nil
+		}
+	}
+	switch {
+	case len(header) >= 520 && bytes.Equal(header[512:520], []byte("EFI PART")):
+		return LocalImageRaw, "gpt", nil
+	case len(header) >= 512 && header[510] == 0x55 && header[511] == 0xaa:
+		return LocalImageRaw, "mbr", nil
+	}
+	return LocalImageRaw, "", nil
+}
+
+type countingWriter struct {
+	written  int64
+	total    int64
+	progress func(done, total int64)
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.written += int64(len(p))
+	if w.progress != nil {
+		w.progress(w.written, w.total)
+	}
+	return len(p), nil
+}
+
+// InspectLocalImage detects the image format and computes its checksums. progress is called while the file is read.
+func InspectLocalImage(filePath string, progress func(done, total int64)) (*LocalImage, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", filePath)
+	}
+	if info.Size() == 0 {
+		return nil, fmt.Errorf("%s is empty", filePath)
+	}
+	image := &LocalImage{Path: filePath, Size: info.Size()}
+	if image.Format, image.PartitionTable, err = detectFormat(f, info.Size()); err != nil {
+		return nil, err
+	}
+
+	md5Hash, sha256Hash := md5.New(), sha256.New() // nolint:gosec
+	counter := &countingWriter{total: info.Size(), progress: progress}
+	if _, err := io.Copy(io.MultiWriter(md5Hash, sha256Hash, counter), io.NewSectionReader(f, 0, info.Size())); err != nil {
+		return nil, err
+	}
+	image.MD5 = hex.EncodeToString(md5Hash.Sum(nil))
+	image.SHA256 = hex.EncodeToString(sha256Hash.Sum(nil))
+	return image, nil
+}
+
+// ValidateLocalImage checks the upload options against each other and the image file.
+// Returned warnings do not prevent the upload.
+func ValidateLocalImage(image LocalImage, opts UploadOpts) (warnings []string, err error) {
+	if opts.Name == "" {
+		return nil, fmt.Errorf("image name is required")
+	}
+	for _, v := range []interface {
+		String() string
+		IsValid() error
+	}{opts.OSType, opts.HwMachineType, opts.SshKey, opts.HwFirmwareType, opts.Architecture} {
+		if v.String() == "" {
+			continue
+		}
+		if err := v.IsValid(); err != nil {
+			return nil, err
+		}
+	}
+	if image.Format == LocalImageISO {
+		return nil, fmt.Errorf("%s is an ISO image, only disk images can be uploaded", image.Path)
+	}
+	if opts.Architecture == types.ArchitectureAarch64 {
+		if opts.HwMachineType != "" {
+			return nil, fmt.Errorf("hw_machine_type cannot be provided for %s images", types.ArchitectureAarch64)
+		}
+		if opts.HwFirmwareType == types.HwFirmwareBIOS {
+			return nil, fmt.Errorf("%s images boot with %s firmware only", types.ArchitectureAarch64, types.HwFirmwareUEFI)
+		}
+	}
+	switch {
+	case image.PartitionTable == "gpt" && opts.HwFirmwareType == "":
+		warnings = append(warnings, fmt.Sprintf("image has a GPT partition table, %s hw_firmware_type may be required", types.HwFirmwareUEFI))
+	case image.PartitionTable == "mbr" && opts.HwFirmwareType == types.HwFirmwareUEFI:
+		warnings = append(warnings, fmt.Sprintf("image has an MBR partition table, it may not boot with %s firmware", types.HwFirmwareUEFI))
+	}
+	if image.Format == LocalImageRaw && image.PartitionTable == "" {
+		warnings = append(warnings, "image format is not recognised, it is uploaded as raw")
+	}
+	return warnings, nil
+}
+
+// FileSource serves a local file over HTTP on a random path so that the API can fetch it.
+type FileSource struct {
+	sent     int64
+	url      string
+	size     int64
+	server   *http.Server
+	done     chan struct{}
+	closeErr error
+	once     sync.Once
+}
+
+// ServeFile serves the file on the listener. publicURL is the listener address as the API can reach it,
+// i.e. http://203.0.113.10:8080. The file is only served on a path containing a random token.
+func ServeFile(listener net.Listener, filePath, publicURL string) (*FileSource, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	base, err := url.Parse(publicURL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid public URL %q", publicURL)
+	}
+	servePath := "/" + uuid.NewV4().String() + "/" + url.PathEscape(path.Base(filePath))
+	source := &FileSource{
+		url:  strings.TrimSuffix(base.String(), "/") + servePath,
+		size: info.Size(),
+		done: make(chan struct{}),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(servePath, func(w http.ResponseWriter, r *http.Request) {
+		f, err := os.Open(filePath)
+		if err != nil {
+			http.Error(w, "cannot open image", http.StatusInternalServerError)
+			return
+		}
+		defer f.Close()
+		http.ServeContent(w, r, path.Base(filePath), info.ModTime(), &countingReader{ReadSeeker: f, count: &source.sent})
+	})
+	source.server = &http.Server{Handler: mux}
+	go func() {
+		defer close(source.done)
+		if err := source.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			source.closeErr = err
+		}
+	}()
+	return source, nil
+}
+
+type countingReader struct {
+	io.ReadSeeker
+	count *int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadSeeker.Read(p)
+	atomic.AddInt64(r.count, int64(n))
+	return n, err
+}
+
+// URL returns the URL the file is served on.
+func (s *FileSource) URL() string {
+	return s.url
+}
+
+// Sent returns the number of bytes served and the file size. Sent may exceed the size if the file is fetched more than once.
+func (s *FileSource) Sent() (int64, int64) {
+	return atomic.LoadInt64(&s.sent), s.size
+}
+
+// Close stops serving the file.
+func (s *FileSource) Close() error {
+	s.once.Do(func() {
+		if err := s.server.Close(); err != nil {
+			s.closeErr = err
+		}
+		<-s.done
+	})
+	return s.closeErr
+}
+
+// UploadFileOpts represents options used to upload a local image file.
+// UploadOpts.URL is set to the URL of the served file.
+type UploadFileOpts struct {
+	UploadOpts
+	Path string
+	// Listener accepts the API connections fetching the file, PublicURL is its address reachable by the API.
+	Listener    net.Listener
+	PublicURL   string
+	WaitSeconds int
+	// Hashing is called while checksums are computed, Sending is called every second while the API fetches the file.
+	Hashing func(done, total int64)
+	Sending func(sent, total int64)
+	// Warn is called with validation warnings.
+	Warn func(warning string)
+}
+
+// UploadFileResult represents an image uploaded from a local file.
+type UploadFileResult struct {
+	ImageID string     `json:"image_id"`
+	Local   LocalImage `json:"local"`
+	Sent    int64      `json:"sent"`
+}
+
+// UploadFile inspects and validates the local image, serves it for the API and waits until the API has fetched it.
+func UploadFile(c *gcorecloud.ServiceClient, opts UploadFileOpts) (*UploadFileResult, error) {
+	if opts.Listener == nil {
+		return nil, fmt.Errorf("listener is required")
+	}
+	defer opts.Listener.Close()
+	if opts.WaitSeconds == 0 {
+		opts.WaitSeconds = UploadFileWaitSeconds
+	}
+	local, err := InspectLocalImage(opts.Path, opts.Hashing)
+	if err != nil {
+		return nil, err
+	}
+	warnings, err := ValidateLocalImage(*local, opts.UploadOpts)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range warnings {
+		if opts.Warn != nil {
+			opts.Warn(w)
+		}
+	}
+
+	var imageID string
+	sent, err := UploadServedFile(opts.Listener, opts.Path, opts.PublicURL, opts.Sending, func(url string) error {
+		opts.UploadOpts.URL = url
+		results, err := Upload(c, opts.UploadOpts).Extract()
+		if err != nil {
+			return err
+		}
+		task, err := tasks.WaitForTaskResults(c, results, opts.WaitSeconds)
+		if err != nil {
+			return err
+		}
+		imageID, err = ExtractImageIDFromTask(task)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &UploadFileResult{ImageID: imageID, Local: *local, Sent: sent}, nil
+}
+
+// UploadServedFile serves the file on the listener while upload runs. upload is called with the URL the file is
+// served on and should return once the API has fetched it. sending is called every second until upload returns.
+// It returns the number of bytes served.
+func UploadServedFile(listener net.Listener, filePath, publicURL string, sending func(sent, total int64), upload func(url string) error) (int64, error) {
+	source, err := ServeFile(listener, filePath, publicURL)
+	if err != nil {
+		return 0, err
+	}
+	defer source.Close()
+	if sending != nil {
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-stop:
+					return
+				case <-ticker.C:
+					sending(source.Sent())
+				}
+			}
+		}()
+	}
+	if err := upload(source.URL()); err != nil {
+		return 0, err
+	}
+	sent, _ := source.Sent()
+	return sent, nil
+}
+
+// VerifyUploadedImage checks the uploaded image size against the local file when the image kept the local format.
+func VerifyUploadedImage(image Image, local LocalImage) error {
+	if image.Size != 0 && image.DiskFormat == string(local.Format) && image.Size != local.Size {
+		return fmt.Errorf("image %s size %d does not match local file size %d", image.ID, image.Size, local.Size)
+	}
+	return nil
+}
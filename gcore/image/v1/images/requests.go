@@ -206,3 +206,11 @@ func Upload(client *gcorecloud.ServiceClient, opts UploadOptsBuilder) (r tasks.R
 	_, r.Err = client.Post(uploadURL(client), b, &r.Body, nil) // nolint
 	return
 }
+
+// Export makes an image downloadable from a temporary URL, i.e. to upload it into another region.
+func Export(client *gcorecloud.ServiceClient, id string) (r ExportResult) {
+	_, r.Err = client.Post(exportURL(client, id), nil, &r.Body, &gcorecloud.RequestOpts{ // nolint
+		OkCodes: []int{200, 201},
+	})
+	return
+}
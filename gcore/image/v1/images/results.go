@@ -37,6 +37,29 @@ type UpdateResult struct {
 	commonResult
 }
 
+// ExportResult represents the result of an export operation. Call its Extract
+// method to interpret it as a ImageExport.
+type ExportResult struct {
+	gcorecloud.Result
+}
+
+// Extract is a function that accepts a result and extracts an image export resource.
+func (r ExportResult) Extract() (*ImageExport, error) {
+	var s ImageExport
+	err := r.ExtractInto(&s)
+	return &s, err
+}
+
+func (r ExportResult) ExtractInto(v interface{}) error {
+	return r.Result.ExtractIntoStructPtr(v, "")
+}
+
+// ImageExport represents a temporary URL an image can be downloaded from.
+type ImageExport struct {
+	URL       string                  `json:"url"`
+	ExpiresAt gcorecloud.JSONRFC3339Z `json:"expires_at"`
+}
+
 type Image struct {
 	ID            string                   `json:"id"`
 	Name          string                   `json:"name"`
@@ -130,6 +130,13 @@ const DeleteResponse = `
 }
 `
 
+const ExportResponse = `
+{
+  "url": "https://images.example.com/4a44e5a2-e7ba-41b8-bf78-ddfa2e22974b?signature=f2a9",
+  "expires_at": "2020-03-09T11:16:45+0000"
+}
+`
+
 var (
 	ctm, _      = time.Parse(gcorecloud.RFC3339Z, "2020-03-09T10:16:45+0000")
 	createdTime = gcorecloud.JSONRFC3339Z{Time: ctm}
@@ -158,7 +165,12 @@ var (
 		Metadata:      []metadata.Metadata{ResourceMetadataReadOnly},
 	}
 	ExpectedImagesSlice = []images.Image{Image1}
-	Tasks1              = tasks.TaskResults{
+	etm, _              = time.Parse(gcorecloud.RFC3339Z, "2020-03-09T11:16:45+0000")
+	ImageExport1        = images.ImageExport{
+		URL:       "https://images.example.com/4a44e5a2-e7ba-41b8-bf78-ddfa2e22974b?signature=f2a9",
+		ExpiresAt: gcorecloud.JSONRFC3339Z{Time: etm},
+	}
+	Tasks1 = tasks.TaskResults{
 		Tasks: []tasks.TaskID{"50f53a35-42ed-40c4-82b2-5a37fb3e00bc"},
 	}
 
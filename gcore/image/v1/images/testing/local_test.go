@@ -0,0 +1,184 @@
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/image/v1/images"
+	"github.com/G-Core/gcorelabscloud-go/gcore/image/v1/images/types"
+	th "github.com/G-Core/gcorelabscloud-go/testhelper"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+	"github.com/stretchr/testify/require"
+)
+
+func writeImageFile(t *testing.T, name string, content []byte) string {
+	filePath := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(filePath, content, 0600))
+	return filePath
+}
+
+func gptDisk() []byte {
+	disk := make([]byte, 4096)
+	disk[510], disk[511] = 0x55, 0xaa
+	copy(disk[512:], "EFI PART")
+	return disk
+}
+
+func TestInspectLocalImage(t *testing.T) {
+	qcow2 := append([]byte("QFI\xfb\x00\x00\x00\x03"), make([]byte, 1024)...)
+	image, err := images.InspectLocalImage(writeImageFile(t, "disk.qcow2", qcow2), nil)
+	require.NoError(t, err)
+	require.Equal(t, images.LocalImageQcow2, image.Format)
+	require.Equal(t, int64(len(qcow2)), image.Size)
+
+	var hashed int64
+	image, err = images.InspectLocalImage(writeImageFile(t, "disk.img", []byte("raw")), func(done, total int64) {
+		hashed = done
+	})
+	require.NoError(t, err)
+	require.Equal(t, images.LocalImageRaw, image.Format)
+	require.Equal(t, "", image.PartitionTable)
+	require.Equal(t, "bdd166af3a63f7be696dd17a218a6ffb", image.MD5)
+	require.Equal(t, "d7439bee24773bcbfa2d0a97947ee36227b10d1022b1a55847e928965bb6bfde", image.SHA256)
+	require.Equal(t, int64(3), hashed)
+
+	image, err = images.InspectLocalImage(writeImageFile(t, "disk.raw", gptDisk()), nil)
+	require.NoError(t, err)
+	require.Equal(t, "gpt", image.PartitionTable)
+
+	_, err = images.InspectLocalImage(writeImageFile(t, "empty.img", nil), nil)
+	require.Error(t, err)
+}
+
+func TestValidateLocalImage(t *testing.T) {
+	local := images.LocalImage{Path: "disk.raw", Format: images.LocalImageRaw, PartitionTable: "gpt"}
+	opts := images.UploadOpts{Name: "image", OSType: types.OsLinux}
+
+	warnings, err := images.ValidateLocalImage(local, opts)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+
+	opts.HwFirmwareType = types.HwFirmwareUEFI
+	warnings, err = images.ValidateLocalImage(local, opts)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+
+	opts.Architecture = types.ArchitectureAarch64
+	opts.HwMachineType = types.HwMachineQ35
+	_, err = images.ValidateLocalImage(local, opts)
+	require.Error(t, err)
+
+	opts.HwMachineType = ""
+	opts.HwFirmwareType = types.HwFirmwareBIOS
+	_, err = images.ValidateLocalImage(local, opts)
+	require.Error(t, err)
+
+	opts = images.UploadOpts{Name: "image", OSType: types.OsLinux, HwFirmwareType: "efi"}
+	_, err = images.ValidateLocalImage(local, opts)
+	require.Error(t, err)
+
+	local.Format = images.LocalImageISO
+	_, err = images.ValidateLocalImage(local, images.UploadOpts{Name: "image", OSType: types.OsLinux})
+	require.Error(t, err)
+}
+
+func TestServeFile(t *testing.T) {
+	content := []byte("image content")
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	source, err := images.ServeFile(listener, writeImageFile(t, "disk.img", content), "http://"+listener.Addr().String())
+	require.NoError(t, err)
+	defer source.Close()
+
+	resp, err := http.Get(source.URL())
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.NoError(t, err)
+	require.Equal(t, content, body)
+	sent, size := source.Sent()
+	require.Equal(t, int64(len(content)), sent)
+	require.Equal(t, int64(len(content)), size)
+
+	resp, err = http.Get("http://" + listener.Addr().String() + "/disk.img")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	require.NoError(t, source.Close())
+	_, err = http.Get(source.URL())
+	require.Error(t, err)
+}
+
+func TestUploadFile(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	const (
+		taskID  = "50f53a35-42ed-40c4-82b2-5a37fb3e00bc"
+		imageID = "f01fd9a0-9548-48ba-82dc-a8c8b2d6f2f1"
+	)
+	content := gptDisk()
+	var fetched []byte
+
+	th.Mux.HandleFunc(prepareUploadTestURL(), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "uefi", body["hw_firmware_type"])
+		resp, err := http.Get(body["url"].(string))
+		require.NoError(t, err)
+		fetched, err = io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		require.NoError(t, err)
+
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, `{"tasks": ["%s"]}`, taskID)
+	})
+	th.Mux.HandleFunc("/v1/tasks/"+taskID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, `
+{
+  "id": "%s",
+  "state": "FINISHED",
+  "task_type": "upload_image",
+  "created_on": "2020-09-14T14:45:30",
+  "created_resources": {"images": ["%s"]},
+  "error": null
+}
+`, taskID, imageID)
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	client := fake.ServiceTokenClient("downloadimage", "v1")
+	result, err := images.UploadFile(client, images.UploadFileOpts{
+		UploadOpts: images.UploadOpts{
+			Name:           "image",
+			OSType:         types.OsLinux,
+			HwFirmwareType: types.HwFirmwareUEFI,
+		},
+		Path:      writeImageFile(t, "disk.raw", content),
+		Listener:  listener,
+		PublicURL: "http://" + listener.Addr().String(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, imageID, result.ImageID)
+	require.Equal(t, content, fetched)
+	require.Equal(t, int64(len(content)), result.Sent)
+	require.Equal(t, "gpt", result.Local.PartitionTable)
+
+	image := images.Image{ID: imageID, DiskFormat: "raw", Size: int64(len(content))}
+	require.NoError(t, images.VerifyUploadedImage(image, result.Local))
+	image.Size--
+	require.Error(t, images.VerifyUploadedImage(image, result.Local))
+}
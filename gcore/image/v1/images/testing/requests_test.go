@@ -213,6 +213,28 @@ func TestDelete(t *testing.T) {
 
 }
 
+func TestExport(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc(prepareGetTestURL(Image1.ID)+"/export", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		_, err := fmt.Fprint(w, ExportResponse)
+		if err != nil {
+			log.Error(err)
+		}
+	})
+
+	client := fake.ServiceTokenClient("images", "v1")
+	export, err := images.Export(client, Image1.ID).Extract()
+	require.NoError(t, err)
+	require.Equal(t, ImageExport1, *export)
+}
+
 func TestUpload(t *testing.T) {
 	th.SetupHTTP()
 	defer th.TeardownHTTP()
@@ -33,3 +33,7 @@ func createURL(c *gcorecloud.ServiceClient) string {
 func uploadURL(c *gcorecloud.ServiceClient) string {
 	return rootURL(c)
 }
+
+func exportURL(c *gcorecloud.ServiceClient, id string) string {
+	return c.ServiceURL(id, "export")
+}
@@ -0,0 +1,326 @@
+package exporter
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/types"
+)
+
+const (
+	// DefaultInterval is the default time between metric polls.
+	DefaultInterval = time.Minute
+
+	ContentTypeText        = "text/plain; version=0.0.4; charset=utf-8"
+	ContentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// Target represents instances of a project region to poll metrics for.
+// All instances matching ListOpts are polled if InstanceIDs is empty.
+type Target struct {
+	Client *gcorecloud.ServiceClient
+	// Region is the region label, the client region ID is used if it is empty.
+	Region      string
+	InstanceIDs []string
+	ListOpts    instances.ListOpts
+}
+
+func (t Target) region() string {
+	if t.Region != "" {
+		return t.Region
+	}
+	return strconv.Itoa(t.Client.RegionID)
+}
+
+func (t Target) project() string {
+	return strconv.Itoa(t.Client.ProjectID)
+}
+
+func (t Target) instances() ([]instances.Instance, error) {
+	if len(t.InstanceIDs) == 0 {
+		return instances.ListAll(t.Client, t.ListOpts)
+	}
+	result := make([]instances.Instance, 0, len(t.InstanceIDs))
+	for _, id := range t.InstanceIDs {
+		instance, err := instances.Get(t.Client, id).Extract()
+		if err != nil {
+			return nil, fmt.Errorf("cannot get instance %s: %w", id, err)
+		}
+		result = append(result, *instance)
+	}
+	return result, nil
+}
+
+// Opts represents options used to poll instance metrics.
+type Opts struct {
+	// Interval is the time between polls, DefaultInterval is used if it is zero.
+	Interval time.Duration
+	// MetricsOpts are the options of every metrics request, the last hour is requested if they are empty.
+	MetricsOpts instances.ListMetricsOpts
+	// Errors is called with every poll error.
+	Errors func(error)
+}
+
+type sample struct {
+	name   string
+	labels [][2]string
+	value  float64
+}
+
+type family struct {
+	name, help, typ string
+}
+
+var families = []family{
+	{"gcore_instance_cpu_utilization_percent", "Instance CPU utilization.", "gauge"},
+	{"gcore_instance_memory_utilization_percent", "Instance memory utilization.", "gauge"},
+	{"gcore_instance_network_receive_bytes_per_second", "Instance network ingress rate.", "gauge"},
+	{"gcore_instance_network_transmit_bytes_per_second", "Instance network egress rate.", "gauge"},
+	{"gcore_instance_network_receive_packets_per_second", "Instance network ingress packet rate.", "gauge"},
+	{"gcore_instance_network_transmit_packets_per_second", "Instance network egress packet rate.", "gauge"},
+	{"gcore_instance_disk_read_bytes_per_second", "Instance disk read rate.", "gauge"},
+	{"gcore_instance_disk_write_bytes_per_second", "Instance disk write rate.", "gauge"},
+	{"gcore_instance_disk_read_iops", "Instance disk read operations per second.", "gauge"},
+	{"gcore_instance_disk_write_iops", "Instance disk write operations per second.", "gauge"},
+	{"gcore_instance_metrics_timestamp_seconds", "Time of the exported instance metrics sample.", "gauge"},
+	{"gcore_exporter_up", "Whether the last poll of the region succeeded.", "gauge"},
+	{"gcore_exporter_last_poll_timestamp_seconds", "Time of the last poll of the region.", "gauge"},
+	{"gcore_exporter_poll_errors", "Number of failed instance and region polls.", "counter"},
+}
+
+// Collector polls instance metrics and exposes the latest samples in Prometheus text and OpenMetrics formats.
+type Collector struct {
+	targets []Target
+	opts    Opts
+
+	mu      sync.RWMutex
+	samples map[string][]sample
+	errors  map[[2]string]float64
+}
+
+// NewCollector creates a collector of the targets' instance metrics.
+func NewCollector(targets []Target, opts Opts) *Collector {
+	if opts.Interval == 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.MetricsOpts.TimeUnit == "" {
+		opts.MetricsOpts = instances.ListMetricsOpts{TimeUnit: types.HourMetricsTimeUnit, TimeInterval: 1}
+	}
+	return &Collector{
+		targets: targets,
+		opts:    opts,
+		samples: make(map[string][]sample),
+		errors:  make(map[[2]string]float64),
+	}
+}
+
+// Latest returns the newest metrics sample.
+func Latest(metrics []instances.InstanceMetrics) (instances.InstanceMetrics, bool) {
+	if len(metrics) == 0 {
+		return instances.InstanceMetrics{}, false
+	}
+	latest := metrics[0]
+	for _, m := range metrics[1:] {
+		if m.Time.After(latest.Time.Time) {
+			latest = m
+		}
+	}
+	return latest, true
+}
+
+func instanceSamples(labels [][2]string, m instances.InstanceMetrics) []sample {
+	result := []sample{
+		{"gcore_instance_cpu_utilization_percent", labels, m.CPUUtil},
+		{"gcore_instance_memory_utilization_percent", labels, m.MemoryUtil},
+		{"gcore_instance_network_receive_bytes_per_second", labels, m.NetworkBPSIngress},
+		{"gcore_instance_network_transmit_bytes_per_second", labels, m.NetworkBPSEgress},
+		{"gcore_instance_network_receive_packets_per_second", labels, m.NetworkPPSIngress},
+		{"gcore_instance_network_transmit_packets_per_second", labels, m.NetworkPPSEgress},
+		{"gcore_instance_metrics_timestamp_seconds", labels, float64(m.Time.Unix())},
+	}
+	for _, d := range m.Disks {
+		diskLabels := append(append([][2]string{}, labels...), [2]string{"disk", d.Name})
+		result = append(result,
+			sample{"gcore_instance_disk_read_bytes_per_second", diskLabels, d.BpsRead},
+			sample{"gcore_instance_disk_write_bytes_per_second", diskLabels, d.BpsWrite},
+			sample{"gcore_instance_disk_read_iops", diskLabels, d.IOPSRead},
+			sample{"gcore_instance_disk_write_iops", diskLabels, d.IOPSWrite},
+		)
+	}
+	return result
+}
+
+func (c *Collector) fail(key [2]string, err error) {
+	c.mu.Lock()
+	c.errors[key]++
+	c.mu.Unlock()
+	if c.opts.Errors != nil {
+		c.opts.Errors(err)
+	}
+}
+
+func (c *Collector) collectTarget(t Target) {
+	region, project := t.region(), t.project()
+	key := [2]string{region, project}
+	targetLabels := [][2]string{{"region", region}, {"project", project}}
+	up := 1.0
+
+	var result []sample
+	list, err := t.instances()
+	if err != nil {
+		up = 0
+		c.fail(key, fmt.Errorf("cannot list instances of project %s region %s: %w", project, region, err))
+	}
+	for _, instance := range list {
+		metrics, err := instances.ListInstanceMetrics(t.Client, instance.ID, c.opts.MetricsOpts).Extract()
+		if err != nil {
+			c.fail(key, fmt.Errorf("cannot get instance %s metrics: %w", instance.ID, err))
+			continue
+		}
+		latest, ok := Latest(metrics)
+		if !ok {
+			continue
+		}
+		labels := [][2]string{
+			{"instance_id", instance.ID},
+			{"instance_name", instance.Name},
+			{"flavor", instance.Flavor.FlavorName},
+			{"region", region},
+			{"project", project},
+		}
+		result = append(result, instanceSamples(labels, latest)...)
+	}
+	result = append(result,
+		sample{"gcore_exporter_up", targetLabels, up},
+		sample{"gcore_exporter_last_poll_timestamp_seconds", targetLabels, float64(time.Now().Unix())},
+	)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if up == 0 {
+		// keep the instance samples of the previous poll
+		for _, s := range c.samples[region+"/"+project] {
+			if strings.HasPrefix(s.name, "gcore_instance_") {
+				result = append(result, s)
+			}
+		}
+	}
+	c.samples[region+"/"+project] = result
+}
+
+// Collect polls metrics of all targets once. Errors are counted and passed to Opts.Errors.
+func (c *Collector) Collect() {
+	var wg sync.WaitGroup
+	for _, t := range c.targets {
+		wg.Add(1)
+		go func(t Target) {
+			defer wg.Done()
+			c.collectTarget(t)
+		}(t)
+	}
+	wg.Wait()
+}
+
+// Run collects metrics every interval until the context is done.
+func (c *Collector) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.opts.Interval)
+	defer ticker.Stop()
+	for {
+		c.Collect()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func writeSample(w *bufio.Writer, name string, labels [][2]string, value float64) {
+	w.WriteString(name)
+	if len(labels) > 0 {
+		w.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, `%s="%s"`, l[0], escapeLabel(l[1]))
+		}
+		w.WriteByte('}')
+	}
+	fmt.Fprintf(w, " %s\n", strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// Write writes the latest samples in Prometheus text format or in OpenMetrics format.
+func (c *Collector) Write(out io.Writer, openMetrics bool) error {
+	c.mu.RLock()
+	byName := make(map[string][]sample)
+	keys := make([]string, 0, len(c.samples))
+	for key := range c.samples {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, s := range c.samples[key] {
+			byName[s.name] = append(byName[s.name], s)
+		}
+	}
+	errorKeys := make([][2]string, 0, len(c.errors))
+	for key := range c.errors {
+		errorKeys = append(errorKeys, key)
+	}
+	counts := make(map[[2]string]float64, len(c.errors))
+	for key, value := range c.errors {
+		counts[key] = value
+	}
+	c.mu.RUnlock()
+	sort.Slice(errorKeys, func(i, j int) bool {
+		return errorKeys[i][0]+"/"+errorKeys[i][1] < errorKeys[j][0]+"/"+errorKeys[j][1]
+	})
+
+	w := bufio.NewWriter(out)
+	for _, f := range families {
+		name := f.name
+		if f.typ == "counter" && !openMetrics {
+			name += "_total"
+		}
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, f.help, name, f.typ)
+		if f.typ == "counter" {
+			for _, key := range errorKeys {
+				writeSample(w, f.name+"_total", [][2]string{{"region", key[0]}, {"project", key[1]}}, counts[key])
+			}
+			continue
+		}
+		for _, s := range byName[f.name] {
+			writeSample(w, s.name, s.labels, s.value)
+		}
+	}
+	if openMetrics {
+		w.WriteString("# EOF\n")
+	}
+	return w.Flush()
+}
+
+// ServeHTTP serves the latest samples. OpenMetrics format is used if the client accepts it.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+	if openMetrics {
+		w.Header().Set("Content-Type", ContentTypeOpenMetrics)
+	} else {
+		w.Header().Set("Content-Type", ContentTypeText)
+	}
+	_ = c.Write(w, openMetrics)
+}
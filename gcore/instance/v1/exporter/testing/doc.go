@@ -0,0 +1,2 @@
+// exporter unit tests
+package testing
@@ -0,0 +1,124 @@
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/exporter"
+	th "github.com/G-Core/gcorelabscloud-go/testhelper"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+	"github.com/stretchr/testify/require"
+)
+
+const instanceID = "a7e7e8d6-0bf7-4ac9-8170-831b47ee2ba9"
+
+const ListResponse = `
+{
+  "count": 1,
+  "results": [
+    {
+      "instance_id": "a7e7e8d6-0bf7-4ac9-8170-831b47ee2ba9",
+      "instance_name": "web \"1\"",
+      "status": "ACTIVE",
+      "vm_state": "active",
+      "instance_created": "2019-07-11T06:58:48Z",
+      "flavor": {"flavor_id": "g1-standard-1-2", "flavor_name": "g1-standard-1-2", "ram": 2048, "vcpus": 1}
+    }
+  ]
+}
+`
+
+const MetricsResponse = `
+{
+  "count": 2,
+  "results": [
+    {
+      "cpu_util": 8,
+      "disks": [{"disk_Bps_read": 16384, "disk_Bps_write": 86016, "disk_iops_read": 3, "disk_iops_write": 12, "disk_name": "sda"}],
+      "memory_util": 33.5,
+      "network_Bps_egress": 102,
+      "network_Bps_ingress": 204,
+      "network_pps_egress": 0.7,
+      "network_pps_ingress": 1.4,
+      "time": "2020-07-07T12:58:00Z"
+    },
+    {
+      "cpu_util": 50,
+      "disks": [],
+      "memory_util": 10,
+      "network_Bps_egress": 0,
+      "network_Bps_ingress": 0,
+      "network_pps_egress": 0,
+      "network_pps_ingress": 0,
+      "time": "2020-07-07T12:57:00Z"
+    }
+  ]
+}
+`
+
+func TestCollector(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	listCalls := 0
+	th.Mux.HandleFunc(fmt.Sprintf("/v1/instances/%d/%d", fake.ProjectID, fake.RegionID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+		listCalls++
+		w.Header().Add("Content-Type", "application/json")
+		if listCalls > 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, ListResponse)
+	})
+	th.Mux.HandleFunc(fmt.Sprintf("/v1/instances/%d/%d/%s/metrics", fake.ProjectID, fake.RegionID, instanceID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestJSONRequest(t, r, `{"time_unit": "hour", "time_interval": 1}`)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, MetricsResponse)
+	})
+
+	var pollErrors []error
+	client := fake.ServiceTokenClient("instances", "v1")
+	collector := exporter.NewCollector([]exporter.Target{{Client: client, Region: "Luxembourg"}}, exporter.Opts{
+		Errors: func(err error) { pollErrors = append(pollErrors, err) },
+	})
+	collector.Collect()
+	require.Empty(t, pollErrors)
+
+	recorder := httptest.NewRecorder()
+	collector.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, exporter.ContentTypeText, recorder.Header().Get("Content-Type"))
+	body := recorder.Body.String()
+	labels := `instance_id="a7e7e8d6-0bf7-4ac9-8170-831b47ee2ba9",instance_name="web \"1\"",flavor="g1-standard-1-2",region="Luxembourg",project="1"`
+	require.Contains(t, body, "# TYPE gcore_instance_cpu_utilization_percent gauge\n")
+	require.Contains(t, body, "gcore_instance_cpu_utilization_percent{"+labels+"} 8\n")
+	require.Contains(t, body, "gcore_instance_memory_utilization_percent{"+labels+"} 33.5\n")
+	require.Contains(t, body, "gcore_instance_network_receive_bytes_per_second{"+labels+"} 204\n")
+	require.Contains(t, body, "gcore_instance_disk_write_bytes_per_second{"+labels+`,disk="sda"} 86016`+"\n")
+	require.Contains(t, body, "gcore_instance_metrics_timestamp_seconds{"+labels+"} 1594126680\n")
+	require.Contains(t, body, `gcore_exporter_up{region="Luxembourg",project="1"} 1`+"\n")
+	require.Contains(t, body, "# TYPE gcore_exporter_poll_errors_total counter\n")
+	require.NotContains(t, body, "# EOF")
+
+	collector.Collect()
+	require.Len(t, pollErrors, 1)
+
+	recorder = httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	request.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	collector.ServeHTTP(recorder, request)
+	require.Equal(t, exporter.ContentTypeOpenMetrics, recorder.Header().Get("Content-Type"))
+	body = recorder.Body.String()
+	require.Contains(t, body, `gcore_exporter_up{region="Luxembourg",project="1"} 0`+"\n")
+	require.Contains(t, body, "gcore_instance_cpu_utilization_percent{"+labels+"} 8\n")
+	require.Contains(t, body, "# TYPE gcore_exporter_poll_errors counter\n")
+	require.Contains(t, body, `gcore_exporter_poll_errors_total{region="Luxembourg",project="1"} 1`+"\n")
+	require.True(t, strings.HasSuffix(body, "# EOF\n"))
+}
@@ -0,0 +1,116 @@
+package instances
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+
+	"golang.org/x/net/websocket"
+)
+
+// DefaultConsoleEscape is the byte which ends a console session, Ctrl-].
+const DefaultConsoleEscape byte = 0x1d
+
+// IsSerial reports whether the console is a serial console which can be attached to a terminal.
+func (rc RemoteConsole) IsSerial() bool {
+	return rc.Protocol == "serial" || rc.Type == "serial"
+}
+
+// DialConsole opens the websocket of a serial console. The console URL scheme and host are used as origin if it is empty.
+func DialConsole(console RemoteConsole, origin string) (*websocket.Conn, error) {
+	if !console.IsSerial() {
+		return nil, fmt.Errorf("%s console is not a serial console, open %s in a browser", console.Type, console.URL)
+	}
+	if origin == "" {
+		u, err := url.Parse(console.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid console URL %s: %w", console.URL, err)
+		}
+		scheme := "http"
+		if u.Scheme == "wss" || u.Scheme == "https" {
+			scheme = "https"
+		}
+		origin = scheme + "://" + u.Host
+	}
+	config, err := websocket.NewConfig(console.URL, origin)
+	if err != nil {
+		return nil, fmt.Errorf("invalid console URL %s: %w", console.URL, err)
+	}
+	config.Protocol = []string{"binary"}
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to console: %w", err)
+	}
+	conn.PayloadType = websocket.BinaryFrame
+	return conn, nil
+}
+
+// ConsoleSessionOpts represents options of an attached console session.
+type ConsoleSessionOpts struct {
+	// Escape ends the session when it is read from the input, DefaultConsoleEscape is used if it is zero.
+	Escape byte
+	// Log receives a copy of the console output.
+	Log io.Writer
+}
+
+// AttachConsole copies input to the console and the console output to out until the escape byte is read,
+// the input ends or the console is closed. The console is closed on return.
+func AttachConsole(conn io.ReadWriteCloser, in io.Reader, out io.Writer, opts ConsoleSessionOpts) error {
+	escape := opts.Escape
+	if escape == 0 {
+		escape = DefaultConsoleEscape
+	}
+	if opts.Log != nil {
+		out = io.MultiWriter(out, opts.Log)
+	}
+
+	remote := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(out, conn)
+		remote <- err
+	}()
+	local := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := in.Read(buf)
+			if n > 0 {
+				data := buf[:n]
+				idx := bytes.IndexByte(data, escape)
+				if idx >= 0 {
+					data = data[:idx]
+				}
+				if len(data) > 0 {
+					if _, err := conn.Write(data); err != nil {
+						local <- err
+						return
+					}
+				}
+				if idx >= 0 {
+					local <- nil
+					return
+				}
+			}
+			if err == io.EOF {
+				local <- nil
+				return
+			}
+			if err != nil {
+				local <- err
+				return
+			}
+		}
+	}()
+
+	select {
+	case err := <-local:
+		_ = conn.Close()
+		// wait for the output copy to stop writing before the caller closes the log
+		<-remote
+		return err
+	case err := <-remote:
+		_ = conn.Close()
+		return err
+	}
+}
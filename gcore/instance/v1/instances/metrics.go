@@ -0,0 +1,150 @@
+package instances
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// MetricPoint represents a metric value at an aligned time. Value is nil if there is no sample for the time.
+type MetricPoint struct {
+	Time  time.Time `json:"time"`
+	Value *float64  `json:"value"`
+}
+
+// MetricSeries represents aligned values of a single instance metric.
+type MetricSeries struct {
+	Metric string        `json:"metric"`
+	Points []MetricPoint `json:"points"`
+}
+
+// MetricStats represents a summary of a metric series. Gaps are not counted.
+type MetricStats struct {
+	Metric  string  `json:"metric"`
+	Samples int     `json:"samples"`
+	Min     float64 `json:"min"`
+	Avg     float64 `json:"avg"`
+	Max     float64 `json:"max"`
+	P95     float64 `json:"p95"`
+}
+
+func metricValues(m InstanceMetrics) ([]string, []float64) {
+	names := []string{"cpu_util", "memory_util", "network_Bps_ingress", "network_Bps_egress", "network_pps_ingress", "network_pps_egress"}
+	values := []float64{m.CPUUtil, m.MemoryUtil, m.NetworkBPSIngress, m.NetworkBPSEgress, m.NetworkPPSIngress, m.NetworkPPSEgress}
+	for _, d := range m.Disks {
+		prefix := fmt.Sprintf("disk_%s_", d.Name)
+		names = append(names, prefix+"Bps_read", prefix+"Bps_write", prefix+"iops_read", prefix+"iops_write")
+		values = append(values, d.BpsRead, d.BpsWrite, d.IOPSRead, d.IOPSWrite)
+	}
+	return names, values
+}
+
+// MetricsStep returns the smallest positive interval between samples, it is a minute if there are not enough samples.
+func MetricsStep(samples []InstanceMetrics) time.Duration {
+	times := make([]time.Time, 0, len(samples))
+	for _, s := range samples {
+		times = append(times, s.Time.Time)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	var step time.Duration
+	for i := 1; i < len(times); i++ {
+		if d := times[i].Sub(times[i-1]); d > 0 && (step == 0 || d < step) {
+			step = d
+		}
+	}
+	if step == 0 {
+		step = time.Minute
+	}
+	return step
+}
+
+// AlignMetrics converts samples to series with points every step from the first to the last sample.
+// Sample times are truncated to the step, samples falling into the same point are averaged and missing points are nil.
+// Series are ordered as the metrics of the first sample, then disks in order of appearance.
+func AlignMetrics(samples []InstanceMetrics, step time.Duration) []MetricSeries {
+	if len(samples) == 0 || step <= 0 {
+		return nil
+	}
+	type bucket struct {
+		sum   float64
+		count int
+	}
+	var (
+		order   []string
+		buckets = make(map[string]map[time.Time]*bucket)
+		first   time.Time
+		last    time.Time
+	)
+	for i, s := range samples {
+		t := s.Time.UTC().Truncate(step)
+		if i == 0 || t.Before(first) {
+			first = t
+		}
+		if i == 0 || t.After(last) {
+			last = t
+		}
+		names, values := metricValues(s)
+		for idx, name := range names {
+			if _, ok := buckets[name]; !ok {
+				order = append(order, name)
+				buckets[name] = make(map[time.Time]*bucket)
+			}
+			b, ok := buckets[name][t]
+			if !ok {
+				b = &bucket{}
+				buckets[name][t] = b
+			}
+			b.sum += values[idx]
+			b.count++
+		}
+	}
+
+	result := make([]MetricSeries, 0, len(order))
+	for _, name := range order {
+		series := MetricSeries{Metric: name}
+		for t := first; !t.After(last); t = t.Add(step) {
+			point := MetricPoint{Time: t}
+			if b, ok := buckets[name][t]; ok {
+				value := b.sum / float64(b.count)
+				point.Value = &value
+			}
+			series.Points = append(series.Points, point)
+		}
+		result = append(result, series)
+	}
+	return result
+}
+
+// Stats returns min, avg, max and 95th percentile of the series. The percentile uses the nearest-rank method.
+func (s MetricSeries) Stats() MetricStats {
+	stats := MetricStats{Metric: s.Metric}
+	var values []float64
+	for _, p := range s.Points {
+		if p.Value != nil {
+			values = append(values, *p.Value)
+		}
+	}
+	stats.Samples = len(values)
+	if len(values) == 0 {
+		return stats
+	}
+	sort.Float64s(values)
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	stats.Min, stats.Max = values[0], values[len(values)-1]
+	stats.Avg = sum / float64(len(values))
+	stats.P95 = values[int(math.Ceil(0.95*float64(len(values))))-1]
+	return stats
+}
+
+// SummarizeMetrics returns the stats of every series.
+func SummarizeMetrics(series []MetricSeries) []MetricStats {
+	result := make([]MetricStats, 0, len(series))
+	for _, s := range series {
+		result = append(result, s.Stats())
+	}
+	return result
+}
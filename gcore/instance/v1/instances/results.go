@@ -158,6 +158,7 @@ type Instance struct {
 	Volumes          []InstanceVolume             `json:"volumes"`
 	Addresses        map[string][]InstanceAddress `json:"addresses"`
 	SecurityGroups   []gcorecloud.ItemName        `json:"security_groups"`
+	KeypairName      string                       `json:"keypair_name"`
 	CreatorTaskID    *string                      `json:"creator_task_id"`
 	TaskID           *string                      `json:"task_id"`
 	ProjectID        int                          `json:"project_id"`
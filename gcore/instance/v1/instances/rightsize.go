@@ -0,0 +1,200 @@
+package instances
+
+import (
+	"fmt"
+	"sort"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/flavor/v1/flavors"
+	"github.com/shopspring/decimal"
+)
+
+// HoursPerMonth is used to get a monthly price of flavors with an hourly price only.
+const HoursPerMonth = 730
+
+// RightsizeVerdict represents how well an instance flavor fits its utilization.
+type RightsizeVerdict string
+
+const (
+	RightsizeOverProvisioned  RightsizeVerdict = "over-provisioned"
+	RightsizeUnderProvisioned RightsizeVerdict = "under-provisioned"
+	RightsizeOptimal          RightsizeVerdict = "optimal"
+	RightsizeUnknown          RightsizeVerdict = "unknown"
+)
+
+// RightsizeOpts represents utilization thresholds in percent used to rightsize instances.
+// Zero values are replaced with the defaults: 30, 85, 70 and 24 samples.
+type RightsizeOpts struct {
+	// Low is the p95 CPU and memory utilization an instance is over-provisioned below.
+	Low float64 `validate:"omitempty,gt=0,lt=100"`
+	// High is the p95 CPU or memory utilization an instance is under-provisioned above.
+	High float64 `validate:"omitempty,gt=0,lte=100"`
+	// Target is the highest p95 utilization projected on a recommended flavor.
+	Target float64 `validate:"omitempty,gt=0,lte=100"`
+	// MinSamples is the number of metric samples required to give a verdict.
+	MinSamples int `validate:"omitempty,gt=0"`
+}
+
+// Validate RightsizeOpts
+func (opts RightsizeOpts) Validate() error {
+	return gcorecloud.TranslateValidationError(gcorecloud.Validate.Struct(opts))
+}
+
+func (opts RightsizeOpts) withDefaults() RightsizeOpts {
+	if opts.Low == 0 {
+		opts.Low = 30
+	}
+	if opts.High == 0 {
+		opts.High = 85
+	}
+	if opts.Target == 0 {
+		opts.Target = 70
+	}
+	if opts.MinSamples == 0 {
+		opts.MinSamples = 24
+	}
+	return opts
+}
+
+// RightsizeRecommendation represents the rightsizing result of an instance.
+// MonthlySavings is negative if the recommended flavor is more expensive.
+type RightsizeRecommendation struct {
+	InstanceID               string           `json:"instance_id"`
+	InstanceName             string           `json:"instance_name"`
+	FlavorID                 string           `json:"flavor_id"`
+	Verdict                  RightsizeVerdict `json:"verdict"`
+	Samples                  int              `json:"samples"`
+	CPUP95                   float64          `json:"cpu_p95"`
+	MemoryP95                float64          `json:"memory_p95"`
+	RecommendedFlavorID      string           `json:"recommended_flavor_id,omitempty"`
+	PricePerMonth            *decimal.Decimal `json:"price_per_month,omitempty"`
+	RecommendedPricePerMonth *decimal.Decimal `json:"recommended_price_per_month,omitempty"`
+	MonthlySavings           *decimal.Decimal `json:"monthly_savings,omitempty"`
+	RecommendedVCPUS         int              `json:"recommended_vcpus,omitempty"`
+	RecommendedRAM           int              `json:"recommended_ram,omitempty"`
+	Reason                   string           `json:"reason"`
+}
+
+// ToChangeFlavorOpts returns options resizing the instance to the recommended flavor.
+func (r RightsizeRecommendation) ToChangeFlavorOpts() (ChangeFlavorOpts, error) {
+	if r.RecommendedFlavorID == "" {
+		return ChangeFlavorOpts{}, fmt.Errorf("instance %s has no recommended flavor", r.InstanceID)
+	}
+	return ChangeFlavorOpts{FlavorID: r.RecommendedFlavorID}, nil
+}
+
+// MonthlyPrice returns the monthly price of the flavor, the hourly price is used if there is no monthly price.
+func MonthlyPrice(f flavors.Flavor) *decimal.Decimal {
+	if f.PricePerMonth != nil {
+		price := *f.PricePerMonth
+		return &price
+	}
+	if f.PricePerHour != nil {
+		price := f.PricePerHour.Mul(decimal.NewFromInt(HoursPerMonth))
+		return &price
+	}
+	return nil
+}
+
+func utilizationP95(series []MetricSeries, metric string) (float64, int) {
+	for _, s := range series {
+		if s.Metric == metric {
+			stats := s.Stats()
+			return stats.P95, stats.Samples
+		}
+	}
+	return 0, 0
+}
+
+// Rightsize compares the p95 CPU and memory utilization of the instance metrics with the thresholds and recommends
+// the cheapest available flavor keeping the projected utilization under the target.
+// Only over- and under-provisioned instances get a recommendation, the current flavor is never recommended.
+func Rightsize(instance Instance, metrics []InstanceMetrics, available []flavors.Flavor, opts RightsizeOpts) RightsizeRecommendation {
+	opts = opts.withDefaults()
+	current := instance.Flavor
+	for _, f := range available {
+		if f.FlavorID == current.FlavorID && MonthlyPrice(f) != nil {
+			current.PricePerHour, current.PricePerMonth = f.PricePerHour, f.PricePerMonth
+		}
+	}
+	r := RightsizeRecommendation{
+		InstanceID:    instance.ID,
+		InstanceName:  instance.Name,
+		FlavorID:      current.FlavorID,
+		PricePerMonth: MonthlyPrice(current),
+	}
+
+	series := AlignMetrics(metrics, MetricsStep(metrics))
+	var memorySamples int
+	r.CPUP95, r.Samples = utilizationP95(series, "cpu_util")
+	r.MemoryP95, memorySamples = utilizationP95(series, "memory_util")
+	if memorySamples < r.Samples {
+		r.Samples = memorySamples
+	}
+	switch {
+	case r.Samples < opts.MinSamples:
+		r.Verdict = RightsizeUnknown
+		r.Reason = fmt.Sprintf("%d samples, %d required", r.Samples, opts.MinSamples)
+		return r
+	case r.CPUP95 > opts.High || r.MemoryP95 > opts.High:
+		r.Verdict = RightsizeUnderProvisioned
+		r.Reason = fmt.Sprintf("p95 utilization above %g%%", opts.High)
+	case r.CPUP95 < opts.Low && r.MemoryP95 < opts.Low:
+		r.Verdict = RightsizeOverProvisioned
+		r.Reason = fmt.Sprintf("p95 utilization below %g%%", opts.Low)
+	default:
+		r.Verdict = RightsizeOptimal
+		r.Reason = fmt.Sprintf("p95 utilization between %g%% and %g%%", opts.Low, opts.High)
+		return r
+	}
+
+	requiredCPU := float64(current.VCPUS) * r.CPUP95 / opts.Target
+	requiredRAM := float64(current.RAM) * r.MemoryP95 / opts.Target
+	var candidates []flavors.Flavor
+	for _, f := range available {
+		if f.FlavorID == current.FlavorID || f.VCPUS <= 0 || f.RAM <= 0 || MonthlyPrice(f) == nil {
+			continue
+		}
+		if current.Architecture != "" && f.Architecture != "" && f.Architecture != current.Architecture {
+			continue
+		}
+		if float64(f.VCPUS) < requiredCPU || float64(f.RAM) < requiredRAM {
+			continue
+		}
+		if r.Verdict == RightsizeOverProvisioned {
+			if r.PricePerMonth != nil && !MonthlyPrice(f).LessThan(*r.PricePerMonth) {
+				continue
+			}
+			if r.PricePerMonth == nil && (f.VCPUS > current.VCPUS || f.RAM > current.RAM) {
+				continue
+			}
+		}
+		candidates = append(candidates, f)
+	}
+	if len(candidates) == 0 {
+		r.Reason += ", no available flavor fits"
+		return r
+	}
+
+	// cheapest first, then the least over-provisioned
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if pa, pb := MonthlyPrice(a), MonthlyPrice(b); !pa.Equal(*pb) {
+			return pa.LessThan(*pb)
+		}
+		if a.VCPUS != b.VCPUS {
+			return a.VCPUS < b.VCPUS
+		}
+		return a.RAM < b.RAM
+	})
+	recommended := candidates[0]
+	r.RecommendedFlavorID = recommended.FlavorID
+	r.RecommendedVCPUS = recommended.VCPUS
+	r.RecommendedRAM = recommended.RAM
+	r.RecommendedPricePerMonth = MonthlyPrice(recommended)
+	if r.PricePerMonth != nil {
+		savings := r.PricePerMonth.Sub(*r.RecommendedPricePerMonth)
+		r.MonthlySavings = &savings
+	}
+	return r
+}
@@ -0,0 +1,103 @@
+package instances
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/types"
+)
+
+// DefaultLoginUser is the login user of instances with an unknown image distribution.
+const DefaultLoginUser = "root"
+
+// LoginUsers maps the os_distro image metadata to the default login user of the distribution cloud images.
+var LoginUsers = map[string]string{
+	"almalinux": "almalinux",
+	"centos":    "centos",
+	"debian":    "debian",
+	"fedora":    "fedora",
+	"freebsd":   "freebsd",
+	"rocky":     "rocky",
+	"ubuntu":    "ubuntu",
+	"windows":   "Admin",
+}
+
+// IDFromName is a convenience function that returns an instance ID, given its name.
+func IDFromName(client *gcorecloud.ServiceClient, name string) (string, error) {
+	count := 0
+	id := ""
+
+	all, err := ListAll(client, ListOpts{Name: name})
+	if err != nil {
+		return "", err
+	}
+
+	for _, s := range all {
+		if s.Name == name {
+			count++
+			id = s.ID
+		}
+	}
+
+	switch count {
+	case 0:
+		return "", gcorecloud.ErrResourceNotFound{Name: name, ResourceType: "instances"}
+	case 1:
+		return id, nil
+	default:
+		return "", gcorecloud.ErrMultipleResourcesFound{Name: name, Count: count, ResourceType: "instances"}
+	}
+}
+
+// LoginUser returns the default login user of the instance image distribution.
+func LoginUser(instance Instance) string {
+	distro, _ := instance.Metadata["os_distro"].(string)
+	if user, ok := LoginUsers[strings.ToLower(distro)]; ok {
+		return user
+	}
+	return DefaultLoginUser
+}
+
+// SSHAddress returns the floating address of the instance or the first fixed address accepted by reachable.
+// Networks are checked in name order and IPv4 addresses go first. The first fixed address is returned if reachable is nil.
+func SSHAddress(instance Instance, reachable func(net.IP) bool) (net.IP, error) {
+	networks := make([]string, 0, len(instance.Addresses))
+	for network := range instance.Addresses {
+		networks = append(networks, network)
+	}
+	sort.Strings(networks)
+	var floating, fixed []net.IP
+	for _, network := range networks {
+		for _, address := range instance.Addresses[network] {
+			if address.Address == nil {
+				continue
+			}
+			if address.Type == types.AddressTypeFloating {
+				floating = append(floating, address.Address)
+			} else {
+				fixed = append(fixed, address.Address)
+			}
+		}
+	}
+	byFamily := func(ips []net.IP) {
+		sort.SliceStable(ips, func(i, j int) bool { return ips[i].To4() != nil && ips[j].To4() == nil })
+	}
+	byFamily(floating)
+	byFamily(fixed)
+
+	if len(floating) > 0 {
+		return floating[0], nil
+	}
+	for _, ip := range fixed {
+		if reachable == nil || reachable(ip) {
+			return ip, nil
+		}
+	}
+	if len(fixed) > 0 {
+		return nil, fmt.Errorf("no address of instance %s is reachable", instance.ID)
+	}
+	return nil, fmt.Errorf("instance %s has no addresses", instance.ID)
+}
@@ -0,0 +1,86 @@
+package testing
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func serialConsole(t *testing.T, handler func(ws *websocket.Conn)) instances.RemoteConsole {
+	server := httptest.NewServer(websocket.Handler(handler))
+	t.Cleanup(server.Close)
+	return instances.RemoteConsole{
+		URL:      "ws" + strings.TrimPrefix(server.URL, "http"),
+		Type:     "serial",
+		Protocol: "serial",
+	}
+}
+
+func TestDialConsole(t *testing.T) {
+	_, err := instances.DialConsole(instances.RemoteConsole{URL: "https://console/vnc_auto.html", Type: "novnc", Protocol: "vnc"}, "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "https://console/vnc_auto.html")
+}
+
+func TestAttachConsole(t *testing.T) {
+	console := serialConsole(t, func(ws *websocket.Conn) {
+		_, _ = ws.Write([]byte("login: "))
+		buf := make([]byte, 5)
+		_, _ = io.ReadFull(ws, buf)
+		_, _ = ws.Write(append([]byte("welcome "), buf...))
+	})
+	conn, err := instances.DialConsole(console, "")
+	require.NoError(t, err)
+
+	in, input := io.Pipe()
+	defer input.Close()
+	go func() { _, _ = input.Write([]byte("root\n")) }()
+	var out, log bytes.Buffer
+	require.NoError(t, instances.AttachConsole(conn, in, &out, instances.ConsoleSessionOpts{Log: &log}))
+	require.Equal(t, "login: welcome root\n", out.String())
+	require.Equal(t, out.String(), log.String())
+}
+
+func TestAttachConsoleEscape(t *testing.T) {
+	received := make(chan []byte, 1)
+	console := serialConsole(t, func(ws *websocket.Conn) {
+		data, _ := io.ReadAll(ws)
+		received <- data
+	})
+	conn, err := instances.DialConsole(console, "")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	in := strings.NewReader("ls\r\x1dexit\r")
+	require.NoError(t, instances.AttachConsole(conn, in, &out, instances.ConsoleSessionOpts{}))
+	require.Equal(t, []byte("ls\r"), <-received)
+
+	conn, err = instances.DialConsole(console, "")
+	require.NoError(t, err)
+	in = strings.NewReader("ls\r~.exit\r")
+	require.NoError(t, instances.AttachConsole(conn, in, &out, instances.ConsoleSessionOpts{Escape: '~'}))
+	require.Equal(t, []byte("ls\r"), <-received)
+}
+
+func TestAttachConsoleEscapeWaitsForOutput(t *testing.T) {
+	console := serialConsole(t, func(ws *websocket.Conn) {
+		for {
+			if _, err := ws.Write([]byte("boot\n")); err != nil {
+				return
+			}
+		}
+	})
+	conn, err := instances.DialConsole(console, "")
+	require.NoError(t, err)
+
+	var out, log bytes.Buffer
+	require.NoError(t, instances.AttachConsole(conn, strings.NewReader("\x1d"), &out, instances.ConsoleSessionOpts{Log: &log}))
+	// the output is not written any more once AttachConsole returns
+	require.Equal(t, out.String(), log.String())
+}
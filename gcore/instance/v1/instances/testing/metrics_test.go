@@ -0,0 +1,64 @@
+package testing
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/stretchr/testify/require"
+)
+
+func metricsSample(minute int, cpu float64, disks ...instances.DiskMetrics) instances.InstanceMetrics {
+	t := time.Date(2020, 7, 7, 12, minute, 30, 0, time.UTC)
+	return instances.InstanceMetrics{CPUUtil: cpu, MemoryUtil: 50, Disks: disks, Time: gcorecloud.JSONRFC3339ZZ{Time: t}}
+}
+
+func TestAlignMetrics(t *testing.T) {
+	samples := []instances.InstanceMetrics{
+		metricsSample(3, 30, instances.DiskMetrics{Name: "sda", IOPSRead: 4}),
+		metricsSample(0, 10, instances.DiskMetrics{Name: "sda", IOPSRead: 2}),
+		metricsSample(1, 20),
+	}
+	require.Equal(t, time.Minute, instances.MetricsStep(samples))
+	require.Equal(t, time.Minute, instances.MetricsStep(samples[:1]))
+
+	series := instances.AlignMetrics(samples, time.Minute)
+	require.Len(t, series, 10)
+	cpu := series[0]
+	require.Equal(t, "cpu_util", cpu.Metric)
+	require.Len(t, cpu.Points, 4)
+	require.Equal(t, time.Date(2020, 7, 7, 12, 0, 0, 0, time.UTC), cpu.Points[0].Time)
+	require.Equal(t, 10.0, *cpu.Points[0].Value)
+	require.Nil(t, cpu.Points[2].Value)
+	require.Equal(t, 30.0, *cpu.Points[3].Value)
+
+	disk := series[8]
+	require.Equal(t, "disk_sda_iops_read", disk.Metric)
+	require.Nil(t, disk.Points[1].Value)
+
+	// gaps are null
+	data, err := json.Marshal(disk.Points[:2])
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"time": "2020-07-07T12:00:00Z", "value": 2}, {"time": "2020-07-07T12:01:00Z", "value": null}]`, string(data))
+
+	series = instances.AlignMetrics(samples, 5*time.Minute)
+	require.Len(t, series[0].Points, 1)
+	require.Equal(t, 20.0, *series[0].Points[0].Value)
+}
+
+func TestMetricStats(t *testing.T) {
+	series := instances.MetricSeries{Metric: "cpu_util"}
+	for i := 1; i <= 20; i++ {
+		value := float64(i)
+		series.Points = append(series.Points, instances.MetricPoint{Value: &value})
+	}
+	series.Points = append(series.Points, instances.MetricPoint{})
+
+	stats := series.Stats()
+	require.Equal(t, instances.MetricStats{Metric: "cpu_util", Samples: 20, Min: 1, Avg: 10.5, Max: 20, P95: 19}, stats)
+
+	stats = instances.MetricSeries{Metric: "empty"}.Stats()
+	require.Equal(t, 0, stats.Samples)
+}
@@ -0,0 +1,76 @@
+package testing
+
+import (
+	"testing"
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/flavor/v1/flavors"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func hourlyMetrics(hours int, cpu, memory float64) []instances.InstanceMetrics {
+	start := time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+	result := make([]instances.InstanceMetrics, 0, hours)
+	for i := 0; i < hours; i++ {
+		t := start.Add(time.Duration(i) * time.Hour)
+		result = append(result, instances.InstanceMetrics{CPUUtil: cpu, MemoryUtil: memory, Time: gcorecloud.JSONRFC3339ZZ{Time: t}})
+	}
+	return result
+}
+
+func monthlyFlavor(id string, vcpus, ram int, perMonth int64) flavors.Flavor {
+	price := decimal.NewFromInt(perMonth)
+	return flavors.Flavor{FlavorID: id, VCPUS: vcpus, RAM: ram, PricePerMonth: &price}
+}
+
+func TestRightsize(t *testing.T) {
+	instance := instances.Instance{ID: "instance", Flavor: flavors.Flavor{FlavorID: "g1-4-8", VCPUS: 4, RAM: 8192}}
+	perHour := decimal.NewFromFloat(0.01)
+	available := []flavors.Flavor{
+		monthlyFlavor("g1-4-8", 4, 8192, 100),
+		monthlyFlavor("g1-2-4", 2, 4096, 50),
+		monthlyFlavor("g1-1-2", 1, 2048, 25),
+		monthlyFlavor("g1-8-16", 8, 16384, 200),
+		{FlavorID: "g1-1-1", VCPUS: 1, RAM: 1024, PricePerHour: &perHour},
+	}
+
+	r := instances.Rightsize(instance, hourlyMetrics(48, 10, 15), available, instances.RightsizeOpts{})
+	require.Equal(t, instances.RightsizeOverProvisioned, r.Verdict)
+	require.Equal(t, 48, r.Samples)
+	require.Equal(t, "g1-1-2", r.RecommendedFlavorID)
+	require.Equal(t, "100", r.PricePerMonth.String())
+	require.Equal(t, "75", r.MonthlySavings.String())
+	opts, err := r.ToChangeFlavorOpts()
+	require.NoError(t, err)
+	require.Equal(t, "g1-1-2", opts.FlavorID)
+
+	r = instances.Rightsize(instance, hourlyMetrics(48, 5, 5), available, instances.RightsizeOpts{})
+	require.Equal(t, "g1-1-1", r.RecommendedFlavorID)
+	require.Equal(t, "92.7", r.MonthlySavings.String())
+
+	r = instances.Rightsize(instance, hourlyMetrics(48, 95, 40), available, instances.RightsizeOpts{})
+	require.Equal(t, instances.RightsizeUnderProvisioned, r.Verdict)
+	require.Equal(t, "g1-8-16", r.RecommendedFlavorID)
+	require.Equal(t, "-100", r.MonthlySavings.String())
+
+	r = instances.Rightsize(instance, hourlyMetrics(48, 50, 50), available, instances.RightsizeOpts{})
+	require.Equal(t, instances.RightsizeOptimal, r.Verdict)
+	require.Empty(t, r.RecommendedFlavorID)
+	_, err = r.ToChangeFlavorOpts()
+	require.Error(t, err)
+
+	r = instances.Rightsize(instance, hourlyMetrics(12, 10, 15), available, instances.RightsizeOpts{})
+	require.Equal(t, instances.RightsizeUnknown, r.Verdict)
+	r = instances.Rightsize(instance, hourlyMetrics(12, 10, 15), available, instances.RightsizeOpts{MinSamples: 6})
+	require.Equal(t, instances.RightsizeOverProvisioned, r.Verdict)
+
+	r = instances.Rightsize(instance, hourlyMetrics(48, 95, 40), available[:3], instances.RightsizeOpts{})
+	require.Equal(t, instances.RightsizeUnderProvisioned, r.Verdict)
+	require.Empty(t, r.RecommendedFlavorID)
+
+	require.Error(t, instances.RightsizeOpts{Low: 120}.Validate())
+	require.NoError(t, instances.RightsizeOpts{}.Validate())
+}
@@ -0,0 +1,67 @@
+package testing
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/types"
+	th "github.com/G-Core/gcorelabscloud-go/testhelper"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDFromName(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc(prepareListTestURL(), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, ListResponse)
+	})
+
+	client := fake.ServiceTokenClient("instances", "v1")
+	id, err := instances.IDFromName(client, "Testing")
+	require.NoError(t, err)
+	require.Equal(t, Instance1.ID, id)
+
+	_, err = instances.IDFromName(client, "Test")
+	require.Error(t, err)
+}
+
+func TestLoginUser(t *testing.T) {
+	require.Equal(t, "centos", instances.LoginUser(Instance1))
+	require.Equal(t, "ubuntu", instances.LoginUser(instances.Instance{Metadata: map[string]interface{}{"os_distro": "Ubuntu"}}))
+	require.Equal(t, instances.DefaultLoginUser, instances.LoginUser(instances.Instance{}))
+}
+
+func TestSSHAddress(t *testing.T) {
+	ip, err := instances.SSHAddress(Instance1, nil)
+	require.NoError(t, err)
+	require.Equal(t, "92.38.157.215", ip.String())
+
+	instance := instances.Instance{ID: "instance", Addresses: map[string][]instances.InstanceAddress{
+		"b": {{Type: types.AddressTypeFixed, Address: net.ParseIP("192.168.0.2")}},
+		"a": {
+			{Type: types.AddressTypeFixed, Address: net.ParseIP("fd00::2")},
+			{Type: types.AddressTypeFixed, Address: net.ParseIP("10.0.0.2")},
+		},
+	}}
+	ip, err = instances.SSHAddress(instance, nil)
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.2", ip.String())
+
+	ip, err = instances.SSHAddress(instance, func(ip net.IP) bool { return ip.String() != "10.0.0.2" })
+	require.NoError(t, err)
+	require.Equal(t, "192.168.0.2", ip.String())
+
+	_, err = instances.SSHAddress(instance, func(net.IP) bool { return false })
+	require.Error(t, err)
+	_, err = instances.SSHAddress(instances.Instance{}, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,156 @@
+package sshconfig
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/G-Core/gcorelabscloud-go/gcore/keypair/v2/keypairs"
+)
+
+// DefaultPort is the ssh port of instances.
+const DefaultPort = 22
+
+// Host represents the ssh client configuration of an instance.
+type Host struct {
+	Alias        string `json:"alias"`
+	InstanceID   string `json:"instance_id"`
+	HostName     string `json:"hostname"`
+	User         string `json:"user"`
+	Port         int    `json:"port"`
+	IdentityFile string `json:"identity_file,omitempty"`
+}
+
+// Args returns ssh command line arguments connecting to the host.
+func (h Host) Args() []string {
+	var args []string
+	if h.IdentityFile != "" {
+		args = append(args, "-i", h.IdentityFile, "-o", "IdentitiesOnly=yes")
+	}
+	if h.Port != 0 && h.Port != DefaultPort {
+		args = append(args, "-p", strconv.Itoa(h.Port))
+	}
+	return append(args, h.User+"@"+h.HostName)
+}
+
+// Opts represents options used to resolve instance ssh configurations.
+type Opts struct {
+	// KeyDir is searched by Hosts for private keys matching the instance keypairs.
+	KeyDir string
+	// User overrides the login user inferred from the instance image.
+	User string
+	// Port is the ssh port, DefaultPort is used if it is zero.
+	Port int
+	// Reachable checks fixed addresses if the instance has no floating address.
+	Reachable func(net.IP) bool
+	// AliasPrefix is prepended to the instance names in host aliases.
+	AliasPrefix string
+}
+
+func samePublicKey(a, b string) bool {
+	fa, fb := strings.Fields(a), strings.Fields(b)
+	return len(fa) >= 2 && len(fb) >= 2 && fa[0] == fb[0] && fa[1] == fb[1]
+}
+
+// MatchPrivateKey returns the private key in dir with a .pub file holding the public key. Key comments are ignored.
+func MatchPrivateKey(publicKey, dir string) (string, error) {
+	pubs, err := filepath.Glob(filepath.Join(dir, "*.pub"))
+	if err != nil {
+		return "", err
+	}
+	for _, pub := range pubs {
+		data, err := os.ReadFile(pub)
+		if err != nil || !samePublicKey(string(data), publicKey) {
+			continue
+		}
+		private := strings.TrimSuffix(pub, ".pub")
+		if _, err := os.Stat(private); err == nil {
+			return private, nil
+		}
+	}
+	return "", fmt.Errorf("no private key in %s matches the public key", dir)
+}
+
+// NewHost resolves the ssh configuration of an instance. The identity file is set by MatchPrivateKey.
+func NewHost(instance instances.Instance, opts Opts) (Host, error) {
+	address, err := instances.SSHAddress(instance, opts.Reachable)
+	if err != nil {
+		return Host{}, err
+	}
+	host := Host{
+		Alias:      opts.AliasPrefix + strings.Join(strings.Fields(instance.Name), "-"),
+		InstanceID: instance.ID,
+		HostName:   address.String(),
+		User:       opts.User,
+		Port:       opts.Port,
+	}
+	if host.User == "" {
+		host.User = instances.LoginUser(instance)
+	}
+	if host.Port == 0 {
+		host.Port = DefaultPort
+	}
+	return host, nil
+}
+
+// Hosts resolves ssh configurations of instances with keypairs given by name.
+// Instances without an address are skipped and hosts without a matching private key have no identity file, errors of both are returned.
+// Aliases of instances sharing a name get an ID suffix.
+func Hosts(list []instances.Instance, keys map[string]keypairs.KeyPair, opts Opts) ([]Host, []error) {
+	names := make(map[string]int, len(list))
+	for _, instance := range list {
+		names[instance.Name]++
+	}
+	var (
+		hosts  []Host
+		errors []error
+	)
+	for _, instance := range list {
+		host, err := NewHost(instance, opts)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("instance %s: %w", instance.Name, err))
+			continue
+		}
+		if keypair, ok := keys[instance.KeypairName]; ok && opts.KeyDir != "" {
+			if host.IdentityFile, err = MatchPrivateKey(keypair.PublicKey, opts.KeyDir); err != nil {
+				errors = append(errors, fmt.Errorf("instance %s keypair %s: %w", instance.Name, keypair.Name, err))
+			}
+		}
+		if names[instance.Name] > 1 {
+			id := instance.ID
+			if len(id) > 8 {
+				id = id[:8]
+			}
+			host.Alias += "-" + id
+		}
+		hosts = append(hosts, host)
+	}
+	sort.SliceStable(hosts, func(i, j int) bool { return hosts[i].Alias < hosts[j].Alias })
+	return hosts, errors
+}
+
+// Write writes hosts in ssh_config format.
+func Write(out io.Writer, hosts []Host) error {
+	w := bufio.NewWriter(out)
+	for i, h := range hosts {
+		if i > 0 {
+			_, _ = w.WriteString("\n")
+		}
+		_, _ = fmt.Fprintf(w, "# instance %s\nHost %s\n    HostName %s\n    User %s\n    Port %d\n", h.InstanceID, h.Alias, h.HostName, h.User, h.Port)
+		if h.IdentityFile != "" {
+			identity := h.IdentityFile
+			if strings.ContainsAny(identity, " \t") {
+				identity = strconv.Quote(identity)
+			}
+			_, _ = fmt.Fprintf(w, "    IdentityFile %s\n    IdentitiesOnly yes\n", identity)
+		}
+	}
+	return w.Flush()
+}
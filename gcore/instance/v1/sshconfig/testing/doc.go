@@ -0,0 +1,2 @@
+// sshconfig unit tests
+package testing
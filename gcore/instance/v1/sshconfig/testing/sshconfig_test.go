@@ -0,0 +1,96 @@
+package testing
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/sshconfig"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/types"
+	"github.com/G-Core/gcorelabscloud-go/gcore/keypair/v2/keypairs"
+	"github.com/stretchr/testify/require"
+)
+
+const publicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFRkFtZXD1Yzy9ehmFdRAmN0tO0UR4rm8vH1Y7T6dQtM"
+
+func keyDir(t *testing.T) string {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.pub"), []byte("ssh-rsa AAAAB3NzaC1yc2E other\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other"), []byte("private"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "id_ed25519.pub"), []byte(publicKey+" user@laptop\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "id_ed25519"), []byte("private"), 0600))
+	return dir
+}
+
+func instance(id, name, keypair, distro, floating string) instances.Instance {
+	result := instances.Instance{ID: id, Name: name, KeypairName: keypair, Metadata: map[string]interface{}{"os_distro": distro}}
+	if floating != "" {
+		result.Addresses = map[string][]instances.InstanceAddress{
+			"net": {{Type: types.AddressTypeFloating, Address: net.ParseIP(floating)}},
+		}
+	}
+	return result
+}
+
+func TestMatchPrivateKey(t *testing.T) {
+	dir := keyDir(t)
+	path, err := sshconfig.MatchPrivateKey(publicKey+" generated", dir)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "id_ed25519"), path)
+
+	_, err = sshconfig.MatchPrivateKey("ssh-ed25519 AAAAunknown", dir)
+	require.Error(t, err)
+}
+
+func TestHostArgs(t *testing.T) {
+	host, err := sshconfig.NewHost(instance("id", "web 1", "", "ubuntu", "92.38.157.215"), sshconfig.Opts{})
+	require.NoError(t, err)
+	require.Equal(t, sshconfig.Host{Alias: "web-1", InstanceID: "id", HostName: "92.38.157.215", User: "ubuntu", Port: 22}, host)
+	require.Equal(t, []string{"ubuntu@92.38.157.215"}, host.Args())
+
+	host.IdentityFile, host.Port = "key", 2222
+	require.Equal(t, []string{"-i", "key", "-o", "IdentitiesOnly=yes", "-p", "2222", "ubuntu@92.38.157.215"}, host.Args())
+}
+
+func TestHosts(t *testing.T) {
+	dir := keyDir(t)
+	list := []instances.Instance{
+		instance("b2c3d4e5-0000-0000-0000-000000000000", "web", "laptop", "debian", "92.38.157.2"),
+		instance("a1b2c3d4-0000-0000-0000-000000000000", "web", "", "debian", "92.38.157.1"),
+		instance("c3d4e5f6-0000-0000-0000-000000000000", "db", "missing", "centos", "92.38.157.3"),
+		instance("d4e5f6a7-0000-0000-0000-000000000000", "offline", "", "centos", ""),
+	}
+	keys := map[string]keypairs.KeyPair{
+		"laptop":  {Name: "laptop", PublicKey: publicKey},
+		"missing": {Name: "missing", PublicKey: "ssh-ed25519 AAAAunknown"},
+	}
+	hosts, errs := sshconfig.Hosts(list, keys, sshconfig.Opts{KeyDir: dir, AliasPrefix: "gc-"})
+	require.Len(t, errs, 2)
+	require.Len(t, hosts, 3)
+
+	var out bytes.Buffer
+	require.NoError(t, sshconfig.Write(&out, hosts))
+	require.Equal(t, `# instance c3d4e5f6-0000-0000-0000-000000000000
+Host gc-db
+    HostName 92.38.157.3
+    User centos
+    Port 22
+
+# instance a1b2c3d4-0000-0000-0000-000000000000
+Host gc-web-a1b2c3d4
+    HostName 92.38.157.1
+    User debian
+    Port 22
+
+# instance b2c3d4e5-0000-0000-0000-000000000000
+Host gc-web-b2c3d4e5
+    HostName 92.38.157.2
+    User debian
+    Port 22
+    IdentityFile `+filepath.Join(dir, "id_ed25519")+`
+    IdentitiesOnly yes
+`, out.String())
+}
@@ -0,0 +1,261 @@
+package pools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/flavor/v1/flavors"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/G-Core/gcorelabscloud-go/gcore/quota/v2/quotas"
+	"github.com/shopspring/decimal"
+)
+
+// PlanOpts represents requirements used to plan the size of a cluster pool.
+// Either NodeCount or CPU and/or RAM must be set. When both are set,
+// the CPU and RAM requirements are spread across NodeCount nodes.
+type PlanOpts struct {
+	NodeCount int    `validate:"required_without_all=CPU RAM,omitempty,gt=0,lte=200"`
+	CPU       int    `validate:"omitempty,gt=0"`
+	RAM       int    `validate:"omitempty,gt=0"`
+	FlavorID  string `validate:"omitempty"`
+	// Headroom is the percentage of nodes added on top of NodeCount to get MaxNodeCount.
+	Headroom int `validate:"omitempty,gte=0,lte=1000"`
+}
+
+// Validate PlanOpts
+func (opts PlanOpts) Validate() error {
+	return gcorecloud.TranslateValidationError(gcorecloud.Validate.Struct(opts))
+}
+
+// QuotaExceeded represents a single regional quota that a plan would exceed.
+type QuotaExceeded struct {
+	Name      string `json:"name"`
+	Limit     int    `json:"limit"`
+	Usage     int    `json:"usage"`
+	Requested int    `json:"requested"`
+}
+
+// Plan represents a recommended pool configuration.
+type Plan struct {
+	PoolName         string           `json:"pool_name"`
+	FlavorID         string           `json:"flavor_id"`
+	FlavorName       string           `json:"flavor_name"`
+	FlavorChanged    bool             `json:"flavor_changed"`
+	VCPUS            int              `json:"vcpus"`
+	RAM              int              `json:"ram"`
+	PricePerHour     *decimal.Decimal `json:"price_per_hour,omitempty"`
+	CurrentNodeCount int              `json:"current_node_count"`
+	NodeCount        int              `json:"node_count"`
+	MinNodeCount     int              `json:"min_node_count"`
+	MaxNodeCount     int              `json:"max_node_count"`
+	RequiredCPU      int              `json:"required_cpu"`
+	RequiredRAM      int              `json:"required_ram"`
+	RequiredVM       int              `json:"required_vm"`
+	RequiredVolume   int              `json:"required_volume_size"`
+	QuotaChecked     bool             `json:"quota_checked"`
+	QuotaExceeded    []QuotaExceeded  `json:"quota_exceeded,omitempty"`
+}
+
+// Allowed reports whether the plan fits into the quota it was checked against.
+func (p Plan) Allowed() bool {
+	return p.QuotaChecked && len(p.QuotaExceeded) == 0
+}
+
+// ToUpdateOpts returns pool update options setting autoscaling bounds of the plan.
+func (p Plan) ToUpdateOpts() UpdateOpts {
+	return UpdateOpts{
+		MinNodeCount: p.MinNodeCount,
+		MaxNodeCount: p.MaxNodeCount,
+	}
+}
+
+// ToResizeOpts returns pool resize options for the planned node count.
+func (p Plan) ToResizeOpts() ResizeOpts {
+	return ResizeOpts{NodeCount: p.NodeCount}
+}
+
+// CheckQuota compares the resources required by the plan with the regional quota
+// and fills QuotaExceeded with the limits that would be exceeded.
+func (p *Plan) CheckQuota(q quotas.Quota) {
+	p.QuotaChecked = true
+	p.QuotaExceeded = nil
+	required := map[string]int{
+		"cpu_count":    p.RequiredCPU,
+		"ram":          p.RequiredRAM,
+		"vm_count":     p.RequiredVM,
+		"volume_count": p.RequiredVM,
+		"volume_size":  p.RequiredVolume,
+	}
+	names := make([]string, 0, len(required))
+	for name := range required {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		requested := required[name]
+		if requested <= 0 {
+			continue
+		}
+		limit, ok := q[name+"_limit"]
+		if !ok {
+			continue
+		}
+		usage := q[name+"_usage"]
+		if limit >= 0 && usage+requested > limit {
+			p.QuotaExceeded = append(p.QuotaExceeded, QuotaExceeded{
+				Name:      name,
+				Limit:     limit,
+				Usage:     usage,
+				Requested: requested,
+			})
+		}
+	}
+}
+
+// NewPlan recommends a flavor and node counts for the pool based on the given
+// requirements, the available flavors and the current pool instances.
+func NewPlan(pool ClusterPool, available []flavors.Flavor, nodes []instances.Instance, opts PlanOpts) (*Plan, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	flavor, nodeCount, err := chooseFlavor(pool, available, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	maxNodeCount := nodeCount + (nodeCount*opts.Headroom+99)/100
+	plan := Plan{
+		PoolName:         pool.Name,
+		FlavorID:         flavor.FlavorID,
+		FlavorName:       flavor.FlavorName,
+		FlavorChanged:    pool.FlavorID != "" && flavor.FlavorID != pool.FlavorID,
+		VCPUS:            flavor.VCPUS,
+		RAM:              flavor.RAM,
+		PricePerHour:     flavor.PricePerHour,
+		CurrentNodeCount: len(nodes),
+		NodeCount:        nodeCount,
+		MinNodeCount:     nodeCount,
+		MaxNodeCount:     maxNodeCount,
+	}
+
+	// quota is checked against the maximum size the autoscaler may reach
+	var currentCPU, currentRAM int
+	for _, node := range nodes {
+		currentCPU += node.Flavor.VCPUS
+		currentRAM += node.Flavor.RAM
+	}
+	if plan.FlavorChanged {
+		currentCPU, currentRAM = 0, 0
+		plan.RequiredVM = maxNodeCount
+		plan.RequiredVolume = maxNodeCount * pool.BootVolumeSize
+	} else {
+		plan.RequiredVM = maxNodeCount - len(nodes)
+		plan.RequiredVolume = plan.RequiredVM * pool.BootVolumeSize
+	}
+	plan.RequiredCPU = maxNodeCount*flavor.VCPUS - currentCPU
+	plan.RequiredRAM = maxNodeCount*flavor.RAM - currentRAM
+
+	return &plan, nil
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+func isCandidateFlavor(f flavors.Flavor, architecture string) bool {
+	if f.VCPUS <= 0 || f.RAM <= 0 {
+		return false
+	}
+	if strings.EqualFold(f.OsType, "windows") {
+		return false
+	}
+	return architecture == "" || f.Architecture == "" || f.Architecture == architecture
+}
+
+func chooseFlavor(pool ClusterPool, available []flavors.Flavor, opts PlanOpts) (flavors.Flavor, int, error) {
+	flavorID := opts.FlavorID
+	if flavorID == "" && opts.CPU == 0 && opts.RAM == 0 {
+		flavorID = pool.FlavorID
+	}
+
+	if flavorID != "" {
+		for _, f := range available {
+			if f.FlavorID != flavorID {
+				continue
+			}
+			nodeCount := opts.NodeCount
+			if f.VCPUS > 0 && f.RAM > 0 {
+				if n := ceilDiv(opts.CPU, f.VCPUS); n > nodeCount {
+					nodeCount = n
+				}
+				if n := ceilDiv(opts.RAM, f.RAM); n > nodeCount {
+					nodeCount = n
+				}
+			}
+			if nodeCount == 0 {
+				return f, 0, fmt.Errorf("cannot compute node count for flavor %s", flavorID)
+			}
+			return f, nodeCount, nil
+		}
+		return flavors.Flavor{}, 0, gcorecloud.ErrResourceNotFound{Name: flavorID, ResourceType: "flavors"}
+	}
+
+	var architecture string
+	for _, f := range available {
+		if f.FlavorID == pool.FlavorID {
+			architecture = f.Architecture
+			break
+		}
+	}
+
+	type candidate struct {
+		flavor    flavors.Flavor
+		nodeCount int
+	}
+	var candidates []candidate
+	for _, f := range available {
+		if !isCandidateFlavor(f, architecture) {
+			continue
+		}
+		if opts.NodeCount > 0 {
+			if f.VCPUS < ceilDiv(opts.CPU, opts.NodeCount) || f.RAM < ceilDiv(opts.RAM, opts.NodeCount) {
+				continue
+			}
+			candidates = append(candidates, candidate{flavor: f, nodeCount: opts.NodeCount})
+			continue
+		}
+		nodeCount := ceilDiv(opts.CPU, f.VCPUS)
+		if n := ceilDiv(opts.RAM, f.RAM); n > nodeCount {
+			nodeCount = n
+		}
+		candidates = append(candidates, candidate{flavor: f, nodeCount: nodeCount})
+	}
+	if len(candidates) == 0 {
+		return flavors.Flavor{}, 0, fmt.Errorf("no flavor satisfies %d vCPU and %d MiB RAM", opts.CPU, opts.RAM)
+	}
+
+	// cheapest total price first, then the least over-provisioned
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.flavor.PricePerHour != nil && b.flavor.PricePerHour != nil {
+			pa := a.flavor.PricePerHour.Mul(decimal.NewFromInt(int64(a.nodeCount)))
+			pb := b.flavor.PricePerHour.Mul(decimal.NewFromInt(int64(b.nodeCount)))
+			if !pa.Equal(pb) {
+				return pa.LessThan(pb)
+			}
+		}
+		cpuA, cpuB := a.nodeCount*a.flavor.VCPUS, b.nodeCount*b.flavor.VCPUS
+		if cpuA != cpuB {
+			return cpuA < cpuB
+		}
+		ramA, ramB := a.nodeCount*a.flavor.RAM, b.nodeCount*b.flavor.RAM
+		if ramA != ramB {
+			return ramA < ramB
+		}
+		return a.nodeCount < b.nodeCount
+	})
+	return candidates[0].flavor, candidates[0].nodeCount, nil
+}
@@ -367,11 +367,12 @@ var (
 		ServerGroupName:    "affinity",
 	}
 	Instance1 = instances.Instance{
-		ID:        "2246207d-fb9f-4ea4-acea-5b2cf77ff46b",
-		Name:      "cluster-1-pool-1-machine-deployment-56bc6958d-jz6m4",
-		CreatedAt: gcorecloud.JSONRFC3339ZZ{Time: createdTime},
-		Status:    "ACTIVE",
-		VMState:   "active",
+		ID:          "2246207d-fb9f-4ea4-acea-5b2cf77ff46b",
+		Name:        "cluster-1-pool-1-machine-deployment-56bc6958d-jz6m4",
+		CreatedAt:   gcorecloud.JSONRFC3339ZZ{Time: createdTime},
+		Status:      "ACTIVE",
+		VMState:     "active",
+		KeypairName: "73a53a48-1f94-4f5c-9990-d44c8e60d992",
 		Flavor: flavors.Flavor{
 			FlavorID:   "g0-standard-2-4",
 			FlavorName: "g0-standard-2-4",
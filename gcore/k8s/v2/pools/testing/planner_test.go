@@ -0,0 +1,103 @@
+package testing
+
+import (
+	"testing"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/flavor/v1/flavors"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/G-Core/gcorelabscloud-go/gcore/k8s/v2/pools"
+	"github.com/G-Core/gcorelabscloud-go/gcore/quota/v2/quotas"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func planFlavors() []flavors.Flavor {
+	price := func(v int64) *decimal.Decimal {
+		d := decimal.NewFromInt(v)
+		return &d
+	}
+	return []flavors.Flavor{
+		{FlavorID: "g0-standard-2-4", FlavorName: "g0-standard-2-4", VCPUS: 2, RAM: 4096, PricePerHour: price(2)},
+		{FlavorID: "g0-standard-4-8", FlavorName: "g0-standard-4-8", VCPUS: 4, RAM: 8192, PricePerHour: price(3)},
+		{FlavorID: "g0-standard-8-16", FlavorName: "g0-standard-8-16", VCPUS: 8, RAM: 16384, PricePerHour: price(7)},
+		{FlavorID: "g0-windows-8-16", FlavorName: "g0-windows-8-16", VCPUS: 8, RAM: 16384, OsType: "windows", PricePerHour: price(1)},
+	}
+}
+
+func TestPlanOpts(t *testing.T) {
+	err := pools.PlanOpts{}.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "NodeCount")
+
+	require.NoError(t, pools.PlanOpts{CPU: 4}.Validate())
+	require.NoError(t, pools.PlanOpts{NodeCount: 3}.Validate())
+}
+
+func TestNewPlanNodeCount(t *testing.T) {
+	plan, err := pools.NewPlan(Pool1, planFlavors(), []instances.Instance{Instance1}, pools.PlanOpts{NodeCount: 3, Headroom: 50})
+	require.NoError(t, err)
+	require.Equal(t, "g0-standard-2-4", plan.FlavorID)
+	require.False(t, plan.FlavorChanged)
+	require.Equal(t, 3, plan.NodeCount)
+	require.Equal(t, 3, plan.MinNodeCount)
+	require.Equal(t, 5, plan.MaxNodeCount)
+	require.Equal(t, 4, plan.RequiredVM)
+	require.Equal(t, 8, plan.RequiredCPU)
+	require.Equal(t, 16384, plan.RequiredRAM)
+	require.Equal(t, 200, plan.RequiredVolume)
+	require.Equal(t, pools.ResizeOpts{NodeCount: 3}, plan.ToResizeOpts())
+	require.Equal(t, pools.UpdateOpts{MinNodeCount: 3, MaxNodeCount: 5}, plan.ToUpdateOpts())
+}
+
+func TestNewPlanResources(t *testing.T) {
+	plan, err := pools.NewPlan(Pool1, planFlavors(), nil, pools.PlanOpts{CPU: 16, RAM: 20000})
+	require.NoError(t, err)
+	// 4 x g0-standard-4-8 costs 12, 8 x g0-standard-2-4 costs 16, 2 x g0-standard-8-16 costs 14
+	require.Equal(t, "g0-standard-4-8", plan.FlavorID)
+	require.True(t, plan.FlavorChanged)
+	require.Equal(t, 4, plan.NodeCount)
+
+	plan, err = pools.NewPlan(Pool1, planFlavors(), nil, pools.PlanOpts{NodeCount: 2, CPU: 10})
+	require.NoError(t, err)
+	require.Equal(t, "g0-standard-8-16", plan.FlavorID)
+	require.Equal(t, 2, plan.NodeCount)
+
+	_, err = pools.NewPlan(Pool1, planFlavors(), nil, pools.PlanOpts{NodeCount: 1, CPU: 64})
+	require.Error(t, err)
+
+	_, err = pools.NewPlan(Pool1, planFlavors(), nil, pools.PlanOpts{NodeCount: 1, FlavorID: "unknown"})
+	require.Error(t, err)
+}
+
+func TestPlanCheckQuota(t *testing.T) {
+	plan, err := pools.NewPlan(Pool1, planFlavors(), []instances.Instance{Instance1}, pools.PlanOpts{NodeCount: 3})
+	require.NoError(t, err)
+	require.False(t, plan.Allowed())
+
+	combined := &quotas.CombinedQuota{
+		RegionalQuotas: []quotas.Quota{
+			{
+				"region_id":       7,
+				"cpu_count_limit": 8,
+				"cpu_count_usage": 6,
+				"ram_limit":       65536,
+				"ram_usage":       4096,
+				"vm_count_limit":  10,
+				"vm_count_usage":  1,
+			},
+		},
+	}
+	_, err = combined.Regional(1)
+	require.Error(t, err)
+	quota, err := combined.Regional(7)
+	require.NoError(t, err)
+
+	plan.CheckQuota(quota)
+	require.False(t, plan.Allowed())
+	require.Equal(t, []pools.QuotaExceeded{{Name: "cpu_count", Limit: 8, Usage: 6, Requested: 4}}, plan.QuotaExceeded)
+
+	quota["cpu_count_limit"] = 10
+	plan.CheckQuota(quota)
+	require.True(t, plan.Allowed())
+}
@@ -0,0 +1,358 @@
+package lifecyclepolicy
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type SimulationEventType string
+
+const (
+	SimulationEventCreate SimulationEventType = "create"
+	SimulationEventDelete SimulationEventType = "delete"
+
+	// SimulationReasonRetention marks snapshots deleted when their retention time passes.
+	SimulationReasonRetention = "retention_time"
+	// SimulationReasonMaxQuantity marks snapshots rotated out by a newer snapshot of the schedule.
+	SimulationReasonMaxQuantity = "max_quantity"
+)
+
+// SimulateOpts represents options used to simulate a lifecycle policy.
+type SimulateOpts struct {
+	Start time.Time
+	End   time.Time
+	// VolumeSizes maps policy volume IDs to their sizes in GiB.
+	VolumeSizes map[string]int
+	// PricePerGiBHour is the snapshot storage price, the cost is not calculated if it is zero.
+	PricePerGiBHour decimal.Decimal
+}
+
+// SimulationEvent represents a snapshot created or deleted by the policy and the storage used after it.
+type SimulationEvent struct {
+	Time       time.Time           `json:"time"`
+	Type       SimulationEventType `json:"type"`
+	ScheduleID string              `json:"schedule_id"`
+	VolumeID   string              `json:"volume_id"`
+	Created    time.Time           `json:"created"`
+	Size       int                 `json:"size"`
+	Reason     string              `json:"reason,omitempty"`
+	Count      int                 `json:"count"`
+	Storage    int                 `json:"storage"`
+}
+
+// Simulation represents the snapshot timeline of a lifecycle policy. Storage is in GiB.
+type Simulation struct {
+	Start        time.Time         `json:"start"`
+	End          time.Time         `json:"end"`
+	Created      int               `json:"created"`
+	Deleted      int               `json:"deleted"`
+	PeakCount    int               `json:"peak_count"`
+	PeakStorage  int               `json:"peak_storage"`
+	PeakTime     time.Time         `json:"peak_time"`
+	FinalCount   int               `json:"final_count"`
+	FinalStorage int               `json:"final_storage"`
+	GiBHours     decimal.Decimal   `json:"gib_hours"`
+	Cost         *decimal.Decimal  `json:"cost,omitempty"`
+	Events       []SimulationEvent `json:"events"`
+}
+
+// Duration returns the retention time as a duration.
+func (t RetentionTimer) Duration() time.Duration {
+	return time.Duration(t.Weeks)*7*24*time.Hour + time.Duration(t.Days)*24*time.Hour +
+		time.Duration(t.Hours)*time.Hour + time.Duration(t.Minutes)*time.Minute
+}
+
+// Interval returns the time between snapshots of the schedule.
+func (s IntervalSchedule) Interval() time.Duration {
+	return RetentionTimer{Weeks: s.Weeks, Days: s.Days, Hours: s.Hours, Minutes: s.Minutes}.Duration()
+}
+
+// Fires returns the times the schedule takes snapshots in [start, end). The first snapshot is taken an interval after start.
+func (s IntervalSchedule) Fires(start, end time.Time) ([]time.Time, error) {
+	interval := s.Interval()
+	if interval <= 0 {
+		return nil, fmt.Errorf("schedule %s interval should be positive", s.ID)
+	}
+	var result []time.Time
+	for t := start.Add(interval); t.Before(end); t = t.Add(interval) {
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+var (
+	cronMonthNames   = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	cronWeekdayNames = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+)
+
+// parseCronValue parses a number or a name, names[i] stands for min+i.
+func parseCronValue(value string, min int, names []string) (int, error) {
+	for i, n := range names {
+		if strings.EqualFold(value, n) {
+			return min + i, nil
+		}
+	}
+	return strconv.Atoi(value)
+}
+
+func parseCronField(name, value string, min, max int, names []string) ([]bool, error) {
+	set := make([]bool, max+1)
+	value = strings.TrimSpace(value)
+	if value == "" {
+		value = "*"
+	}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		step := 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			var err error
+			if step, err = strconv.Atoi(strings.TrimSpace(part[idx+1:])); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid cron %s step %q", name, value)
+			}
+			part = strings.TrimSpace(part[:idx])
+		}
+		from, to := min, max
+		if part != "*" {
+			low, high := part, part
+			if bounds := strings.SplitN(part, "-", 2); len(bounds) == 2 {
+				low, high = strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1])
+			} else if step > 1 {
+				// a single value with a step runs to the end of the range
+				high = strconv.Itoa(max)
+			}
+			var err error
+			if from, err = parseCronValue(low, min, names); err != nil {
+				return nil, fmt.Errorf("invalid cron %s %q, only numbers, names, ranges, steps and * are supported", name, value)
+			}
+			if to, err = parseCronValue(high, min, names); err != nil {
+				return nil, fmt.Errorf("invalid cron %s %q, only numbers, names, ranges, steps and * are supported", name, value)
+			}
+		}
+		if from < min || to > max || from > to {
+			return nil, fmt.Errorf("cron %s %q should be in range %d-%d", name, value, min, max)
+		}
+		for i := from; i <= to; i += step {
+			set[i] = true
+		}
+	}
+	return set, nil
+}
+
+// Fires returns the times the schedule takes snapshots in [start, end). Fields are comma-separated lists of values,
+// ranges and "*", each optionally with a /step. Month and day_of_week also accept three letter names (jan, mon),
+// day_of_week starts with 0 for Monday and week is the ISO week.
+func (s CronSchedule) Fires(start, end time.Time) ([]time.Time, error) {
+	loc := time.UTC
+	if s.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(s.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid cron timezone %q: %w", s.Timezone, err)
+		}
+	}
+	fields := []struct {
+		name, value string
+		min, max    int
+		names       []string
+	}{
+		{"minute", s.Minute, 0, 59, nil},
+		{"hour", s.Hour, 0, 23, nil},
+		{"day", s.Day, 1, 31, nil},
+		{"month", s.Month, 1, 12, cronMonthNames},
+		{"day_of_week", s.DayOfWeek, 0, 6, cronWeekdayNames},
+		{"week", s.Week, 1, 53, nil},
+	}
+	sets := make([][]bool, len(fields))
+	for i, f := range fields {
+		set, err := parseCronField(f.name, f.value, f.min, f.max, f.names)
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = set
+	}
+	minutes, hours, days, months, weekdays, weeks := sets[0], sets[1], sets[2], sets[3], sets[4], sets[5]
+
+	var result []time.Time
+	first := start.In(loc)
+	for day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc); day.Before(end); day = day.AddDate(0, 0, 1) {
+		_, week := day.ISOWeek()
+		if !days[day.Day()] || !months[int(day.Month())] || !weekdays[(int(day.Weekday())+6)%7] || !weeks[week] {
+			continue
+		}
+		for h := 0; h < 24; h++ {
+			if !hours[h] {
+				continue
+			}
+			for m := 0; m < 60; m++ {
+				t := time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, loc)
+				if minutes[m] && t.Hour() == h && !t.Before(start) && t.Before(end) {
+					result = append(result, t)
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+type scheduleFirer interface {
+	Fires(start, end time.Time) ([]time.Time, error)
+}
+
+type simulatedSnapshot struct {
+	scheduleID string
+	volumeID   string
+	created    time.Time
+	expires    time.Time
+	size       int
+}
+
+type simulation struct {
+	Simulation
+	live    []simulatedSnapshot
+	storage int
+	last    time.Time
+	gibSecs int64
+}
+
+func (s *simulation) advance(t time.Time) {
+	s.gibSecs += int64(s.storage) * int64(t.Sub(s.last)/time.Second)
+	s.last = t
+}
+
+func (s *simulation) record(t time.Time, typ SimulationEventType, snapshot simulatedSnapshot, reason string) {
+	s.advance(t)
+	if typ == SimulationEventCreate {
+		s.Created++
+		s.storage += snapshot.size
+	} else {
+		s.Deleted++
+		s.storage -= snapshot.size
+	}
+	count := len(s.live)
+	s.Events = append(s.Events, SimulationEvent{
+		Time:       t.UTC(),
+		Type:       typ,
+		ScheduleID: snapshot.scheduleID,
+		VolumeID:   snapshot.volumeID,
+		Created:    snapshot.created.UTC(),
+		Size:       snapshot.size,
+		Reason:     reason,
+		Count:      count,
+		Storage:    s.storage,
+	})
+	if s.storage > s.PeakStorage {
+		s.PeakStorage, s.PeakCount, s.PeakTime = s.storage, count, t.UTC()
+	}
+}
+
+func (s *simulation) expire(until time.Time, inclusive bool) {
+	for {
+		idx := -1
+		for i, snapshot := range s.live {
+			if snapshot.expires.IsZero() || snapshot.expires.After(until) || (!inclusive && snapshot.expires.Equal(until)) {
+				continue
+			}
+			if idx == -1 || snapshot.expires.Before(s.live[idx].expires) {
+				idx = i
+			}
+		}
+		if idx == -1 {
+			return
+		}
+		snapshot := s.live[idx]
+		s.live = append(s.live[:idx], s.live[idx+1:]...)
+		s.record(snapshot.expires, SimulationEventDelete, snapshot, SimulationReasonRetention)
+	}
+}
+
+func (s *simulation) rotate(snapshot simulatedSnapshot, maxQuantity int) {
+	if maxQuantity <= 0 {
+		return
+	}
+	var count, oldest int
+	for i := len(s.live) - 1; i >= 0; i-- {
+		if s.live[i].scheduleID == snapshot.scheduleID && s.live[i].volumeID == snapshot.volumeID {
+			count++
+			oldest = i
+		}
+	}
+	if count <= maxQuantity {
+		return
+	}
+	rotated := s.live[oldest]
+	s.live = append(s.live[:oldest], s.live[oldest+1:]...)
+	s.record(snapshot.created, SimulationEventDelete, rotated, SimulationReasonMaxQuantity)
+}
+
+// Simulate replays the policy schedules locally over the time window for every policy volume. Every snapshot is
+// assumed to be as large as its volume. A snapshot is deleted when its retention time passes or when a newer snapshot
+// of the same schedule and volume exceeds max_quantity; the peak includes the newer snapshot before the rotation.
+func Simulate(policy LifecyclePolicy, opts SimulateOpts) (*Simulation, error) {
+	if !opts.End.After(opts.Start) {
+		return nil, fmt.Errorf("simulation end should be after start")
+	}
+	for _, v := range policy.Volumes {
+		if _, ok := opts.VolumeSizes[v.ID]; !ok {
+			return nil, fmt.Errorf("size of volume %s is unknown", v.ID)
+		}
+	}
+
+	type creation struct {
+		time     time.Time
+		schedule CommonSchedule
+		order    int
+	}
+	var creations []creation
+	for i, schedule := range policy.Schedules {
+		firer, ok := schedule.(scheduleFirer)
+		if !ok {
+			return nil, fmt.Errorf("unexpected schedule type %T", schedule)
+		}
+		fires, err := firer.Fires(opts.Start, opts.End)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range fires {
+			creations = append(creations, creation{time: t, schedule: schedule.GetCommonSchedule(), order: i})
+		}
+	}
+	sort.SliceStable(creations, func(i, j int) bool {
+		if !creations[i].time.Equal(creations[j].time) {
+			return creations[i].time.Before(creations[j].time)
+		}
+		return creations[i].order < creations[j].order
+	})
+
+	s := &simulation{Simulation: Simulation{Start: opts.Start.UTC(), End: opts.End.UTC(), PeakTime: opts.Start.UTC(), Events: []SimulationEvent{}}, last: opts.Start}
+	for _, c := range creations {
+		s.expire(c.time, true)
+		for _, v := range policy.Volumes {
+			snapshot := simulatedSnapshot{
+				scheduleID: c.schedule.ID,
+				volumeID:   v.ID,
+				created:    c.time,
+				size:       opts.VolumeSizes[v.ID],
+			}
+			if c.schedule.RetentionTime != nil && c.schedule.RetentionTime.Duration() > 0 {
+				snapshot.expires = c.time.Add(c.schedule.RetentionTime.Duration())
+			}
+			s.live = append(s.live, snapshot)
+			s.record(c.time, SimulationEventCreate, snapshot, "")
+			s.rotate(snapshot, c.schedule.MaxQuantity)
+		}
+	}
+	s.expire(opts.End, false)
+	s.advance(opts.End)
+
+	s.FinalCount, s.FinalStorage = len(s.live), s.storage
+	s.GiBHours = decimal.New(s.gibSecs, 0).Div(decimal.New(3600, 0))
+	if !opts.PricePerGiBHour.IsZero() {
+		cost := s.GiBHours.Mul(opts.PricePerGiBHour)
+		s.Cost = &cost
+	}
+	return &s.Simulation, nil
+}
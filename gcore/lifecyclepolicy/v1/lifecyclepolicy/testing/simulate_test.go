@@ -0,0 +1,121 @@
+package testing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/lifecyclepolicy/v1/lifecyclepolicy"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronScheduleFires(t *testing.T) {
+	schedule := lifecyclepolicy.CronSchedule{DayOfWeek: "0", Hour: "3", Minute: "30"}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	fires, err := schedule.Fires(start, start.AddDate(0, 0, 14))
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{
+		time.Date(2020, 1, 6, 3, 30, 0, 0, time.UTC),
+		time.Date(2020, 1, 13, 3, 30, 0, 0, time.UTC),
+	}, fires)
+
+	schedule = lifecyclepolicy.CronSchedule{Day: "1-2", Hour: "0,12", Minute: "0"}
+	fires, err = schedule.Fires(start, start.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	require.Len(t, fires, 4)
+	require.Equal(t, start, fires[0])
+
+	schedule.Hour = "24"
+	_, err = schedule.Fires(start, start.AddDate(0, 1, 0))
+	require.Error(t, err)
+
+	schedule = lifecyclepolicy.CronSchedule{Month: "jan-feb", DayOfWeek: "mon,FRI", Hour: "*/12", Minute: "10-30/20"}
+	fires, err = schedule.Fires(start, start.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{
+		time.Date(2020, 1, 3, 0, 10, 0, 0, time.UTC),
+		time.Date(2020, 1, 3, 0, 30, 0, 0, time.UTC),
+		time.Date(2020, 1, 3, 12, 10, 0, 0, time.UTC),
+		time.Date(2020, 1, 3, 12, 30, 0, 0, time.UTC),
+		time.Date(2020, 1, 6, 0, 10, 0, 0, time.UTC),
+		time.Date(2020, 1, 6, 0, 30, 0, 0, time.UTC),
+		time.Date(2020, 1, 6, 12, 10, 0, 0, time.UTC),
+		time.Date(2020, 1, 6, 12, 30, 0, 0, time.UTC),
+	}, fires)
+
+	schedule = lifecyclepolicy.CronSchedule{Hour: "5/6", Minute: "0"}
+	fires, err = schedule.Fires(start, start.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, fires, 4)
+	require.Equal(t, 5, fires[0].Hour())
+
+	for _, minute := range []string{"*/0", "L", "mar", "?"} {
+		schedule = lifecyclepolicy.CronSchedule{Minute: minute}
+		_, err = schedule.Fires(start, start.AddDate(0, 0, 1))
+		require.Error(t, err, minute)
+	}
+	schedule = lifecyclepolicy.CronSchedule{Month: "jan-foo"}
+	_, err = schedule.Fires(start, start.AddDate(0, 0, 1))
+	require.Error(t, err)
+}
+
+func TestSimulate(t *testing.T) {
+	policy := lifecyclepolicy.LifecyclePolicy{
+		Volumes: []lifecyclepolicy.Volume{{ID: "vol-a"}, {ID: "vol-b"}},
+		Schedules: []lifecyclepolicy.Schedule{
+			lifecyclepolicy.IntervalSchedule{
+				CommonSchedule: lifecyclepolicy.CommonSchedule{ID: "interval", MaxQuantity: 2},
+				Hours:          6,
+			},
+			lifecyclepolicy.CronSchedule{
+				CommonSchedule: lifecyclepolicy.CommonSchedule{
+					ID:            "cron",
+					MaxQuantity:   10,
+					RetentionTime: &lifecyclepolicy.RetentionTimer{Days: 1},
+				},
+				Hour:   "0",
+				Minute: "0",
+			},
+		},
+	}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	opts := lifecyclepolicy.SimulateOpts{
+		Start:           start,
+		End:             start.AddDate(0, 0, 2),
+		VolumeSizes:     map[string]int{"vol-a": 10, "vol-b": 20},
+		PricePerGiBHour: decimal.RequireFromString("0.01"),
+	}
+
+	simulation, err := lifecyclepolicy.Simulate(policy, opts)
+	require.NoError(t, err)
+	require.Equal(t, 18, simulation.Created)
+	require.Equal(t, 12, simulation.Deleted)
+	require.Equal(t, 6, simulation.FinalCount)
+	require.Equal(t, 90, simulation.FinalStorage)
+	require.Equal(t, 110, simulation.PeakStorage)
+	require.Equal(t, 7, simulation.PeakCount)
+	require.Equal(t, start.Add(18*time.Hour), simulation.PeakTime)
+	require.Len(t, simulation.Events, 30)
+
+	first := simulation.Events[0]
+	require.Equal(t, lifecyclepolicy.SimulationEventCreate, first.Type)
+	require.Equal(t, "cron", first.ScheduleID)
+	require.Equal(t, start, first.Time)
+
+	var expired []lifecyclepolicy.SimulationEvent
+	for _, e := range simulation.Events {
+		if e.Reason == lifecyclepolicy.SimulationReasonRetention {
+			expired = append(expired, e)
+		}
+	}
+	require.Len(t, expired, 2)
+	require.Equal(t, start.AddDate(0, 0, 1), expired[0].Time)
+	require.Equal(t, start, expired[0].Created)
+
+	require.NotNil(t, simulation.Cost)
+	require.True(t, simulation.GiBHours.Mul(decimal.RequireFromString("0.01")).Equal(*simulation.Cost))
+
+	delete(opts.VolumeSizes, "vol-b")
+	_, err = lifecyclepolicy.Simulate(policy, opts)
+	require.Error(t, err)
+}
@@ -0,0 +1,185 @@
+package limits
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/limit/v2/types"
+	"github.com/G-Core/gcorelabscloud-go/gcore/quota/v2/quotas"
+)
+
+// DefaultJustification is the template of a limit request description proposed from a quota report.
+const DefaultJustification = `Limit increase to keep {{ printf "%.0f" .HeadroomPercent }}% headroom over current usage:
+{{- range .Changes }}
+{{ if .RegionID }}region {{ .RegionID }}{{ else }}global{{ end }} {{ .Name }}: {{ .Current }} -> {{ .Proposed }} (usage {{ .Usage }})
+{{- end }}`
+
+func setSentinel(target interface{}) {
+	v := reflect.ValueOf(target).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() == reflect.Int {
+			v.Field(i).SetInt(Sentinel)
+		}
+	}
+}
+
+func setLimit(target interface{}, name string, value int) error {
+	v := reflect.ValueOf(target).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		tag := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		if tag == name && strings.HasSuffix(tag, "_limit") {
+			v.Field(i).SetInt(int64(value))
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown limit %s", name)
+}
+
+// NewRegionalLimits returns regional limits of the region with all limits unset.
+func NewRegionalLimits(regionID int) RegionalLimits {
+	var r RegionalLimits
+	setSentinel(&r)
+	r.RegionID = regionID
+	return r
+}
+
+// Set sets the global limit by its JSON name, e.g. keypair_count_limit.
+func (g *GlobalLimits) Set(name string, value int) error {
+	return setLimit(g, name, value)
+}
+
+// Set sets the regional limit by its JSON name, e.g. cpu_count_limit.
+func (r *RegionalLimits) Set(name string, value int) error {
+	return setLimit(r, name, value)
+}
+
+// Change represents a proposed limit of a quota. RegionID is zero for global limits.
+type Change struct {
+	RegionID int    `json:"region_id,omitempty"`
+	Name     string `json:"name"`
+	Usage    int    `json:"usage"`
+	Current  int    `json:"current"`
+	Proposed int    `json:"proposed"`
+}
+
+// LimitName returns the name of the limit of the change.
+func (c Change) LimitName() string {
+	return c.Name + "_limit"
+}
+
+func canSet(target interface{}, name string) bool {
+	return setLimit(target, name, 0) == nil
+}
+
+// Propose proposes limits for the quota findings keeping the usage at 1/headroom of the new limit.
+// Findings which already have the headroom are omitted, findings of quotas without a requestable limit are skipped.
+func Propose(findings []quotas.Finding, headroom float64) (changes []Change, skipped []quotas.Finding, err error) {
+	if headroom <= 1 {
+		return nil, nil, fmt.Errorf("headroom factor should be greater than 1, got %v", headroom)
+	}
+	for _, f := range findings {
+		change := Change{RegionID: f.RegionID, Name: f.Name, Usage: f.Usage, Current: f.Limit}
+		var target interface{} = &RegionalLimits{}
+		if f.RegionID == 0 {
+			target = &GlobalLimits{}
+		}
+		if f.Unlimited() || !canSet(target, change.LimitName()) {
+			skipped = append(skipped, f)
+			continue
+		}
+		change.Proposed = int(math.Ceil(float64(f.Usage) * headroom))
+		if change.Proposed <= change.Current {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].RegionID != changes[j].RegionID {
+			return changes[i].RegionID < changes[j].RegionID
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes, skipped, nil
+}
+
+// NewCreateOptsFromChanges returns options of a limit request of the proposed limits.
+func NewCreateOptsFromChanges(description string, changes []Change) (CreateOpts, error) {
+	opts := NewCreateOpts(description)
+	regional := make(map[int]*RegionalLimits)
+	var regionIDs []int
+	for _, c := range changes {
+		if c.RegionID == 0 {
+			if err := opts.RequestedQuotas.GlobalLimits.Set(c.LimitName(), c.Proposed); err != nil {
+				return opts, err
+			}
+			continue
+		}
+		r, ok := regional[c.RegionID]
+		if !ok {
+			limits := NewRegionalLimits(c.RegionID)
+			r = &limits
+			regional[c.RegionID] = r
+			regionIDs = append(regionIDs, c.RegionID)
+		}
+		if err := r.Set(c.LimitName(), c.Proposed); err != nil {
+			return opts, err
+		}
+	}
+	sort.Ints(regionIDs)
+	for _, id := range regionIDs {
+		opts.RequestedQuotas.RegionalLimits = append(opts.RequestedQuotas.RegionalLimits, *regional[id])
+	}
+	return opts, nil
+}
+
+// Justify renders the limit request description from a text/template with the changes and the headroom factor.
+func Justify(text string, changes []Change, headroom float64) (string, error) {
+	tmpl, err := template.New("justification").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	data := struct {
+		Changes         []Change
+		Headroom        float64
+		HeadroomPercent float64
+	}{
+		Changes:         changes,
+		Headroom:        headroom,
+		HeadroomPercent: (headroom - 1) * 100,
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// WaitForResolved polls the limit request every interval until it is not in progress any more or the context is done.
+func WaitForResolved(ctx context.Context, c *gcorecloud.ServiceClient, id int, interval time.Duration) (*LimitResponse, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("poll interval should be positive, got %s", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		limit, err := Get(c, id).Extract()
+		if err != nil {
+			return nil, err
+		}
+		if limit.Status != types.LimitRequestInProgress {
+			return limit, nil
+		}
+		select {
+		case <-ctx.Done():
+			return limit, fmt.Errorf("limit request %d is %s: %w", id, limit.Status, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/limit/v2/limits"
+	"github.com/G-Core/gcorelabscloud-go/gcore/limit/v2/types"
+	"github.com/G-Core/gcorelabscloud-go/gcore/quota/v2/quotas"
+	th "github.com/G-Core/gcorelabscloud-go/testhelper"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+	"github.com/stretchr/testify/require"
+)
+
+func finding(regionID int, name string, limit, usage int) quotas.Finding {
+	return quotas.Finding{RegionID: regionID, Severity: quotas.SeverityWarning, Utilization: quotas.NewUtilization(name, limit, usage)}
+}
+
+func TestPropose(t *testing.T) {
+	findings := []quotas.Finding{
+		finding(0, "keypair_count", 10, 9),
+		finding(2, "ram", 4096, 4000),
+		finding(1, "cpu_count", 10, 9),
+		finding(1, "volume_count", 10, 5),
+		finding(1, "unknown_count", 10, 9),
+	}
+	changes, skipped, err := limits.Propose(findings, 1.5)
+	require.NoError(t, err)
+	require.Equal(t, []limits.Change{
+		{Name: "keypair_count", Usage: 9, Current: 10, Proposed: 14},
+		{RegionID: 1, Name: "cpu_count", Usage: 9, Current: 10, Proposed: 14},
+		{RegionID: 2, Name: "ram", Usage: 4000, Current: 4096, Proposed: 6000},
+	}, changes)
+	require.Equal(t, []quotas.Finding{findings[4]}, skipped)
+
+	_, _, err = limits.Propose(findings, 1)
+	require.Error(t, err)
+
+	opts, err := limits.NewCreateOptsFromChanges("test", changes)
+	require.NoError(t, err)
+	m, err := opts.ToLimitCreateMap()
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"global_limits": map[string]interface{}{"keypair_count_limit": 14},
+		"regional_limits": []map[string]interface{}{
+			{"region_id": 1, "cpu_count_limit": 14},
+			{"region_id": 2, "ram_limit": 6000},
+		},
+	}, m["requested_limits"])
+
+	_, err = limits.NewCreateOptsFromChanges("test", []limits.Change{{RegionID: 1, Name: "region_id"}})
+	require.Error(t, err)
+}
+
+func TestJustify(t *testing.T) {
+	description, err := limits.Justify(limits.DefaultJustification, []limits.Change{
+		{Name: "keypair_count", Usage: 9, Current: 10, Proposed: 14},
+		{RegionID: 1, Name: "cpu_count", Usage: 9, Current: 10, Proposed: 14},
+	}, 1.5)
+	require.NoError(t, err)
+	require.Equal(t, strings.Join([]string{
+		"Limit increase to keep 50% headroom over current usage:",
+		"global keypair_count: 10 -> 14 (usage 9)",
+		"region 1 cpu_count: 10 -> 14 (usage 9)",
+	}, "\n"), description)
+
+	_, err = limits.Justify("{{ .Unknown }}", nil, 1.5)
+	require.Error(t, err)
+}
+
+func TestWaitForResolved(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	calls := 0
+	th.Mux.HandleFunc(prepareItemTestURL(limitRequestID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		calls++
+		response := GetResponse
+		if calls > 1 {
+			response = strings.Replace(response, "in progress", "done", 1)
+		}
+		_, _ = fmt.Fprint(w, response)
+	})
+	client := fake.ServiceTokenClient("limits_request", "v2")
+
+	limit, err := limits.WaitForResolved(context.Background(), client, limitRequestID, time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, types.LimitRequestDone, limit.Status)
+	require.Equal(t, 2, calls)
+
+	calls = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	limit, err = limits.WaitForResolved(ctx, client, limitRequestID, time.Hour)
+	require.Error(t, err)
+	require.Equal(t, types.LimitRequestInProgress, limit.Status)
+
+	_, err = limits.WaitForResolved(context.Background(), client, limitRequestID, 0)
+	require.Error(t, err)
+}
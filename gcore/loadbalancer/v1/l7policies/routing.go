@@ -0,0 +1,373 @@
+package l7policies
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/task/v1/tasks"
+
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/yaml.v2"
+)
+
+// RoutingConfig represents a simple routing description of a listener.
+type RoutingConfig struct {
+	Routes []Route `json:"routes" yaml:"routes"`
+}
+
+// Route maps requests matching the host and path prefix to a pool, a redirect or a reject.
+// A host starting with "*." matches any subdomain. Exactly one of Pool, RedirectURL,
+// RedirectPrefix and Reject should be set.
+type Route struct {
+	Name           string   `json:"name,omitempty" yaml:"name,omitempty"`
+	Host           string   `json:"host,omitempty" yaml:"host,omitempty"`
+	PathPrefix     string   `json:"path_prefix,omitempty" yaml:"path_prefix,omitempty"`
+	Pool           string   `json:"pool,omitempty" yaml:"pool,omitempty"`
+	RedirectURL    string   `json:"redirect_url,omitempty" yaml:"redirect_url,omitempty"`
+	RedirectPrefix string   `json:"redirect_prefix,omitempty" yaml:"redirect_prefix,omitempty"`
+	RedirectCode   int      `json:"redirect_code,omitempty" yaml:"redirect_code,omitempty"`
+	Reject         bool     `json:"reject,omitempty" yaml:"reject,omitempty"`
+	Tags           []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// RoutePolicy represents a l7 policy with its rules translated from a route.
+type RoutePolicy struct {
+	Policy CreateOpts       `json:"policy"`
+	Rules  []CreateRuleOpts `json:"rules"`
+}
+
+// RoutingResult represents names of the policies changed by ApplyRouting.
+type RoutingResult struct {
+	Created   []string `json:"created"`
+	Replaced  []string `json:"replaced"`
+	Unchanged []string `json:"unchanged"`
+	Deleted   []string `json:"deleted"`
+}
+
+// ParseRoutingConfig parses routing config in YAML or JSON format.
+func ParseRoutingConfig(data []byte) (*RoutingConfig, error) {
+	var cfg RoutingConfig
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("cannot parse routing config: %w", err)
+	}
+	if len(cfg.Routes) == 0 {
+		return nil, fmt.Errorf("routing config has no routes")
+	}
+	return &cfg, nil
+}
+
+// PolicyName returns the route name or, if not set, a name built from the host and path prefix.
+func (r Route) PolicyName() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return "route:" + r.Host + r.PathPrefix
+}
+
+func (r Route) action() (Action, error) {
+	var actions []Action
+	if r.Pool != "" {
+		actions = append(actions, ActionRedirectToPool)
+	}
+	if r.RedirectURL != "" {
+		actions = append(actions, ActionRedirectToURL)
+	}
+	if r.RedirectPrefix != "" {
+		actions = append(actions, ActionRedirectPrefix)
+	}
+	if r.Reject {
+		actions = append(actions, ActionReject)
+	}
+	if len(actions) != 1 {
+		return "", fmt.Errorf("route %s: exactly one of pool, redirect_url, redirect_prefix and reject should be set", r.PolicyName())
+	}
+	return actions[0], nil
+}
+
+// Validate checks the route.
+func (r Route) Validate() error {
+	if r.Host == "" && r.PathPrefix == "" {
+		return fmt.Errorf("route %s: host or path_prefix should be set", r.PolicyName())
+	}
+	if r.PathPrefix != "" && !strings.HasPrefix(r.PathPrefix, "/") {
+		return fmt.Errorf("route %s: path_prefix should start with /", r.PolicyName())
+	}
+	if strings.Contains(strings.TrimPrefix(r.Host, "*."), "*") {
+		return fmt.Errorf("route %s: only leading wildcard is supported in host %s", r.PolicyName(), r.Host)
+	}
+	action, err := r.action()
+	if err != nil {
+		return err
+	}
+	if r.RedirectCode != 0 {
+		if action != ActionRedirectToURL && action != ActionRedirectPrefix {
+			return fmt.Errorf("route %s: redirect_code is valid only for redirect_url and redirect_prefix", r.PolicyName())
+		}
+		switch r.RedirectCode {
+		case 301, 302, 303, 307, 308:
+		default:
+			return fmt.Errorf("route %s: invalid redirect_code %d", r.PolicyName(), r.RedirectCode)
+		}
+	}
+	return nil
+}
+
+func (r Route) rules() []CreateRuleOpts {
+	var rules []CreateRuleOpts
+	if r.Host != "" {
+		rule := CreateRuleOpts{Type: TypeHostName, CompareType: CompareTypeEqual, Value: r.Host}
+		if strings.HasPrefix(r.Host, "*.") {
+			rule.CompareType = CompareTypeEndWith
+			rule.Value = strings.TrimPrefix(r.Host, "*")
+		}
+		rules = append(rules, rule)
+	}
+	if r.PathPrefix != "" {
+		rules = append(rules, CreateRuleOpts{Type: TypePath, CompareType: CompareTypeStartWith, Value: r.PathPrefix})
+	}
+	return rules
+}
+
+// specificity orders routes so that exact hosts go before wildcards, any host before none
+// and longer path prefixes before shorter ones.
+func (r Route) specificity() (int, int) {
+	host := 0
+	switch {
+	case strings.HasPrefix(r.Host, "*."):
+		host = 1
+	case r.Host != "":
+		host = 2
+	}
+	return host, len(r.PathPrefix)
+}
+
+// Translate converts routes to l7 policies of the listener. Positions are assigned from the most
+// specific route to the least specific one, routes of equal specificity keep their order.
+// Pool is looked up in pools by name, a pool missing from pools should be a pool ID.
+func (cfg RoutingConfig) Translate(listenerID string, pools map[string]string) ([]RoutePolicy, error) {
+	routes := make([]Route, len(cfg.Routes))
+	copy(routes, cfg.Routes)
+	names := make(map[string]bool, len(routes))
+	for _, r := range routes {
+		if err := r.Validate(); err != nil {
+			return nil, err
+		}
+		if names[r.PolicyName()] {
+			return nil, fmt.Errorf("duplicate route %s", r.PolicyName())
+		}
+		names[r.PolicyName()] = true
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		hi, pi := routes[i].specificity()
+		hj, pj := routes[j].specificity()
+		if hi != hj {
+			return hi > hj
+		}
+		return pi > pj
+	})
+
+	result := make([]RoutePolicy, 0, len(routes))
+	for idx, r := range routes {
+		action, _ := r.action()
+		opts := CreateOpts{
+			Name:             r.PolicyName(),
+			ListenerID:       listenerID,
+			Action:           action,
+			Position:         int32(idx + 1),
+			RedirectHTTPCode: r.RedirectCode,
+			RedirectPrefix:   r.RedirectPrefix,
+			RedirectURL:      r.RedirectURL,
+			Tags:             r.Tags,
+		}
+		if r.Pool != "" {
+			id, ok := pools[r.Pool]
+			if !ok {
+				if _, err := uuid.FromString(r.Pool); err != nil {
+					return nil, fmt.Errorf("route %s: unknown pool %s", r.PolicyName(), r.Pool)
+				}
+				id = r.Pool
+			}
+			opts.RedirectPoolID = id
+		}
+		result = append(result, RoutePolicy{Policy: opts, Rules: r.rules()})
+	}
+	return result, nil
+}
+
+// ToReplaceOpts converts policy create options to replace options.
+func (opts CreateOpts) ToReplaceOpts() ReplaceOpts {
+	return ReplaceOpts{
+		Name:             opts.Name,
+		Action:           opts.Action,
+		Position:         opts.Position,
+		RedirectHTTPCode: opts.RedirectHTTPCode,
+		RedirectPoolID:   opts.RedirectPoolID,
+		RedirectPrefix:   opts.RedirectPrefix,
+		RedirectURL:      opts.RedirectURL,
+		Tags:             opts.Tags,
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func policyChanged(p L7Policy, opts CreateOpts) bool {
+	if p.Action != opts.Action || p.Position != opts.Position || p.RedirectPoolID != opts.RedirectPoolID {
+		return true
+	}
+	if stringValue(p.RedirectURL) != opts.RedirectURL || stringValue(p.RedirectPrefix) != opts.RedirectPrefix {
+		return true
+	}
+	if opts.RedirectHTTPCode != 0 && (p.RedirectHttpCode == nil || *p.RedirectHttpCode != opts.RedirectHTTPCode) {
+		return true
+	}
+	return len(opts.Tags) > 0 && !reflect.DeepEqual(p.Tags, opts.Tags)
+}
+
+func ruleChanged(r L7Rule, opts CreateRuleOpts) bool {
+	return r.CompareType != opts.CompareType || r.Value != opts.Value || r.Invert != opts.Invert ||
+		stringValue(r.Key) != opts.Key
+}
+
+func waitForTask(c *gcorecloud.ServiceClient, r tasks.Result, waitSeconds int) (*tasks.Task, error) {
+	results, err := r.Extract()
+	if err != nil {
+		return nil, err
+	}
+	return tasks.WaitForTaskResults(c, results, waitSeconds)
+}
+
+// matchRules pairs desired rules with current ones of the same type, key and value and then
+// the rest with unpaired current rules of the same type, which are replaced.
+func matchRules(current []L7Rule, desired []CreateRuleOpts) map[int]L7Rule {
+	result := make(map[int]L7Rule, len(desired))
+	used := make(map[string]bool, len(current))
+	match := func(same func(L7Rule, CreateRuleOpts) bool) {
+		for i, opts := range desired {
+			if _, ok := result[i]; ok {
+				continue
+			}
+			for _, r := range current {
+				if !used[r.ID] && same(r, opts) {
+					result[i] = r
+					used[r.ID] = true
+					break
+				}
+			}
+		}
+	}
+	match(func(r L7Rule, opts CreateRuleOpts) bool {
+		return r.Type == opts.Type && stringValue(r.Key) == opts.Key && r.Value == opts.Value
+	})
+	match(func(r L7Rule, opts CreateRuleOpts) bool { return r.Type == opts.Type })
+	return result
+}
+
+func applyRules(c *gcorecloud.ServiceClient, policyID string, current []L7Rule, desired []CreateRuleOpts, waitSeconds int) (bool, error) {
+	existing := matchRules(current, desired)
+	matched := make(map[string]bool, len(current))
+	changed := false
+	for i, opts := range desired {
+		r, ok := existing[i]
+		switch {
+		case !ok:
+			if _, err := waitForTask(c, CreateRule(c, policyID, opts), waitSeconds); err != nil {
+				return changed, fmt.Errorf("cannot create %s rule of policy %s: %w", opts.Type, policyID, err)
+			}
+		case ruleChanged(r, opts):
+			matched[r.ID] = true
+			if _, err := waitForTask(c, ReplaceRule(c, policyID, r.ID, opts), waitSeconds); err != nil {
+				return changed, fmt.Errorf("cannot replace rule %s of policy %s: %w", r.ID, policyID, err)
+			}
+		default:
+			matched[r.ID] = true
+			continue
+		}
+		changed = true
+	}
+	for _, r := range current {
+		if matched[r.ID] {
+			continue
+		}
+		if _, err := waitForTask(c, DeleteRule(c, policyID, r.ID), waitSeconds); err != nil {
+			return changed, fmt.Errorf("cannot delete rule %s of policy %s: %w", r.ID, policyID, err)
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// ApplyRouting creates or replaces the listener policies so that they match the desired ones.
+// Policies are matched by name, unchanged policies and rules are left intact.
+// Listener policies missing from the desired list are deleted only if prune is set.
+func ApplyRouting(c *gcorecloud.ServiceClient, listenerID string, desired []RoutePolicy, prune bool, waitSeconds int) (*RoutingResult, error) {
+	all, err := ListAll(c)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]L7Policy)
+	for _, p := range all {
+		if p.ListenerID == listenerID {
+			existing[p.Name] = p
+		}
+	}
+
+	var result RoutingResult
+	for _, rp := range desired {
+		name := rp.Policy.Name
+		p, ok := existing[name]
+		delete(existing, name)
+		if !ok {
+			task, err := waitForTask(c, Create(c, rp.Policy), waitSeconds)
+			if err != nil {
+				return &result, fmt.Errorf("cannot create policy %s: %w", name, err)
+			}
+			policyID, err := ExtractL7PolicyIDFromTask(task)
+			if err != nil {
+				return &result, err
+			}
+			if _, err := applyRules(c, policyID, nil, rp.Rules, waitSeconds); err != nil {
+				return &result, err
+			}
+			result.Created = append(result.Created, name)
+			continue
+		}
+
+		replaced := policyChanged(p, rp.Policy)
+		if replaced {
+			if _, err := waitForTask(c, Replace(c, p.ID, rp.Policy.ToReplaceOpts()), waitSeconds); err != nil {
+				return &result, fmt.Errorf("cannot replace policy %s: %w", name, err)
+			}
+		}
+		rulesChanged, err := applyRules(c, p.ID, p.Rules, rp.Rules, waitSeconds)
+		if err != nil {
+			return &result, err
+		}
+		if replaced || rulesChanged {
+			result.Replaced = append(result.Replaced, name)
+		} else {
+			result.Unchanged = append(result.Unchanged, name)
+		}
+	}
+
+	if !prune {
+		return &result, nil
+	}
+	for _, p := range all {
+		if _, ok := existing[p.Name]; !ok || p.ListenerID != listenerID {
+			continue
+		}
+		if _, err := waitForTask(c, Delete(c, p.ID), waitSeconds); err != nil {
+			return &result, fmt.Errorf("cannot delete policy %s: %w", p.Name, err)
+		}
+		result.Deleted = append(result.Deleted, p.Name)
+	}
+	return &result, nil
+}
@@ -0,0 +1,208 @@
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/l7policies"
+	th "github.com/G-Core/gcorelabscloud-go/testhelper"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+)
+
+const RoutingConfig = `
+routes:
+  - name: redirect-example.com
+    path_prefix: /images
+    redirect_url: http://www.example.com
+    redirect_code: 301
+  - host: "*.example.com"
+    pool: web
+  - host: api.example.com
+    path_prefix: /v1
+    pool: 2f8c4d1e-8f0b-4d6a-9f5e-3c1b7a9d0e11
+`
+
+const CreatedPolicyTaskResponse = `
+{
+  "id": "50f53a35-42ed-40c4-82b2-5a37fb3e00bc",
+  "state": "FINISHED",
+  "task_type": "create_l7policy",
+  "client_id": 1,
+  "user_id": 1,
+  "user_client_id": 1,
+  "created_on": "2020-09-14T14:45:30",
+  "created_resources": {
+    "l7polices": ["8f0d4c6a-2b1e-4e5f-9a3d-7c6b5a4e3d21"]
+  },
+  "error": null
+}
+`
+
+const listenerID = "0388b5e5-3393-4aa8-a88a-dbcdcedf9970"
+
+func TestTranslateRouting(t *testing.T) {
+	cfg, err := l7policies.ParseRoutingConfig([]byte(RoutingConfig))
+	require.NoError(t, err)
+
+	policies, err := cfg.Translate(listenerID, map[string]string{"web": "1a2b3c4d-0000-4000-8000-000000000001"})
+	require.NoError(t, err)
+	require.Len(t, policies, 3)
+
+	require.Equal(t, "route:api.example.com/v1", policies[0].Policy.Name)
+	require.Equal(t, int32(1), policies[0].Policy.Position)
+	require.Equal(t, l7policies.ActionRedirectToPool, policies[0].Policy.Action)
+	require.Equal(t, "2f8c4d1e-8f0b-4d6a-9f5e-3c1b7a9d0e11", policies[0].Policy.RedirectPoolID)
+	require.Equal(t, []l7policies.CreateRuleOpts{
+		{Type: l7policies.TypeHostName, CompareType: l7policies.CompareTypeEqual, Value: "api.example.com"},
+		{Type: l7policies.TypePath, CompareType: l7policies.CompareTypeStartWith, Value: "/v1"},
+	}, policies[0].Rules)
+
+	require.Equal(t, "route:*.example.com", policies[1].Policy.Name)
+	require.Equal(t, "1a2b3c4d-0000-4000-8000-000000000001", policies[1].Policy.RedirectPoolID)
+	require.Equal(t, []l7policies.CreateRuleOpts{
+		{Type: l7policies.TypeHostName, CompareType: l7policies.CompareTypeEndWith, Value: ".example.com"},
+	}, policies[1].Rules)
+
+	require.Equal(t, "redirect-example.com", policies[2].Policy.Name)
+	require.Equal(t, int32(3), policies[2].Policy.Position)
+	require.Equal(t, l7policies.ActionRedirectToURL, policies[2].Policy.Action)
+	require.Equal(t, 301, policies[2].Policy.RedirectHTTPCode)
+
+	_, err = l7policies.RoutingConfig{Routes: []l7policies.Route{{Host: "a.com", Pool: "web", Reject: true}}}.Translate(listenerID, nil)
+	require.Error(t, err)
+	_, err = l7policies.RoutingConfig{Routes: []l7policies.Route{{Host: "a.com", Pool: "web", RedirectCode: 301}}}.Translate(listenerID, nil)
+	require.Error(t, err)
+	_, err = l7policies.RoutingConfig{Routes: []l7policies.Route{{Host: "a.com", Reject: true}, {Host: "a.com", Reject: true}}}.Translate(listenerID, nil)
+	require.Error(t, err)
+	_, err = l7policies.RoutingConfig{Routes: []l7policies.Route{{Host: "a.com", Pool: "wbe"}}}.Translate(listenerID, map[string]string{"web": "1a2b3c4d-0000-4000-8000-000000000001"})
+	require.Error(t, err)
+	_, err = l7policies.ParseRoutingConfig([]byte("routes:\n  - hostname: a.com\n"))
+	require.Error(t, err)
+}
+
+func TestApplyRouting(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	newPolicyID := "8f0d4c6a-2b1e-4e5f-9a3d-7c6b5a4e3d21"
+	calls := make(map[string]int)
+	respond := func(w http.ResponseWriter, status int, body string) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = fmt.Fprint(w, body)
+	}
+
+	th.Mux.HandleFunc(prepareListTestURL(), func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+		calls[r.Method+" policies"]++
+		switch r.Method {
+		case "GET":
+			respond(w, http.StatusOK, ListPolicyResponse)
+		case "POST":
+			th.TestJSONRequest(t, r, `{"listener_id": "0388b5e5-3393-4aa8-a88a-dbcdcedf9970", "name": "route:api.example.com", "action": "REJECT", "position": 1}`)
+			respond(w, http.StatusCreated, TaskResponse)
+		}
+	})
+	th.Mux.HandleFunc(prepareGetPolicyTestURL(pid), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestJSONRequest(t, r, `{"name": "redirect-example.com", "action": "REDIRECT_TO_URL", "position": 2, "redirect_http_code": 301, "redirect_url": "http://www.example.com"}`)
+		calls["PUT policy"]++
+		respond(w, http.StatusOK, TaskResponse)
+	})
+	th.Mux.HandleFunc(prepareGetRuleTestURL(pid, rid), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "PUT")
+		th.TestJSONRequest(t, r, `{"compare_type": "STARTS_WITH", "invert": false, "type": "PATH", "value": "/images"}`)
+		calls["PUT rule"]++
+		respond(w, http.StatusOK, TaskResponse)
+	})
+	th.Mux.HandleFunc(prepareListRuleTestURL(newPolicyID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestJSONRequest(t, r, `{"compare_type": "EQUAL_TO", "invert": false, "type": "HOST_NAME", "value": "api.example.com"}`)
+		calls["POST rule"]++
+		respond(w, http.StatusCreated, TaskResponse)
+	})
+	th.Mux.HandleFunc(fmt.Sprintf("/v1/tasks/%s", Tasks1.Tasks[0]), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		respond(w, http.StatusOK, CreatedPolicyTaskResponse)
+	})
+
+	cfg := l7policies.RoutingConfig{Routes: []l7policies.Route{
+		{Name: "redirect-example.com", PathPrefix: "/images", RedirectURL: redirectURL, RedirectCode: redirectHttpCode},
+		{Host: "api.example.com", Reject: true},
+	}}
+	policies, err := cfg.Translate(listenerID, nil)
+	require.NoError(t, err)
+
+	client := fake.ServiceTokenClient("l7policies", "v1")
+	result, err := l7policies.ApplyRouting(client, listenerID, policies, false, 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"route:api.example.com"}, result.Created)
+	require.Equal(t, []string{"redirect-example.com"}, result.Replaced)
+	require.Len(t, result.Unchanged, 0)
+	require.Equal(t, map[string]int{
+		"GET policies":  1,
+		"POST policies": 1,
+		"POST rule":     1,
+		"PUT policy":    1,
+		"PUT rule":      1,
+	}, calls)
+}
+
+const HeaderRulesPolicyResponse = `
+{
+  "count": 1,
+  "results": [
+    {
+      "id": "9b4b9a23-ccac-4945-bcdd-b0e793c12cd9",
+      "name": "route:api.example.com",
+      "listener_id": "0388b5e5-3393-4aa8-a88a-dbcdcedf9970",
+      "action": "REJECT",
+      "position": 1,
+      "rules": [
+        {"id": "11111111-0000-4000-8000-000000000001", "type": "HOST_NAME", "compare_type": "EQUAL_TO", "value": "api.example.com"},
+        {"id": "11111111-0000-4000-8000-000000000002", "type": "HEADER", "compare_type": "EQUAL_TO", "key": "X-A", "value": "a"},
+        {"id": "11111111-0000-4000-8000-000000000003", "type": "HOST_NAME", "compare_type": "EQUAL_TO", "value": "other.example.com"}
+      ]
+    }
+  ]
+}
+`
+
+func TestApplyRoutingRulesOfSameType(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	var deleted []string
+	respond := func(w http.ResponseWriter, status int, body string) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = fmt.Fprint(w, body)
+	}
+	th.Mux.HandleFunc(prepareListTestURL(), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		respond(w, http.StatusOK, HeaderRulesPolicyResponse)
+	})
+	// the matching host rule is kept, a replace of it would fail with not found
+	for _, id := range []string{"11111111-0000-4000-8000-000000000002", "11111111-0000-4000-8000-000000000003"} {
+		id := id
+		th.Mux.HandleFunc(prepareGetRuleTestURL(pid, id), func(w http.ResponseWriter, r *http.Request) {
+			th.TestMethod(t, r, "DELETE")
+			deleted = append(deleted, id)
+			respond(w, http.StatusOK, TaskResponse)
+		})
+	}
+	th.Mux.HandleFunc(fmt.Sprintf("/v1/tasks/%s", Tasks1.Tasks[0]), func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, CreatedPolicyTaskResponse)
+	})
+
+	policies, err := l7policies.RoutingConfig{Routes: []l7policies.Route{{Host: "api.example.com", Reject: true}}}.Translate(listenerID, nil)
+	require.NoError(t, err)
+	client := fake.ServiceTokenClient("l7policies", "v1")
+	result, err := l7policies.ApplyRouting(client, listenerID, policies, false, 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"route:api.example.com"}, result.Replaced)
+	require.Equal(t, []string{"11111111-0000-4000-8000-000000000002", "11111111-0000-4000-8000-000000000003"}, deleted)
+}
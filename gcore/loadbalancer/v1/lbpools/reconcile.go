@@ -0,0 +1,161 @@
+package lbpools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/task/v1/tasks"
+)
+
+// MemberUpdate represents an existing pool member and the options it should be updated with.
+type MemberUpdate struct {
+	Member PoolMember           `json:"member"`
+	Opts   CreatePoolMemberOpts `json:"opts"`
+}
+
+// MembersDiff represents the changes required to bring pool members to the desired state.
+type MembersDiff struct {
+	Create []CreatePoolMemberOpts `json:"create"`
+	Update []MemberUpdate         `json:"update"`
+	Delete []PoolMember           `json:"delete"`
+}
+
+// IsEmpty checks whether pool members are already in the desired state.
+func (d MembersDiff) IsEmpty() bool {
+	return len(d.Create) == 0 && len(d.Update) == 0 && len(d.Delete) == 0
+}
+
+// MemberKey returns the key identifying a pool member: its address and protocol port.
+func MemberKey(address net.IP, protocolPort int) string {
+	return net.JoinHostPort(address.String(), strconv.Itoa(protocolPort))
+}
+
+func memberAdminStateUp(m PoolMember) bool {
+	return m.AdminStateUp == nil || *m.AdminStateUp
+}
+
+func memberChanged(m PoolMember, opts CreatePoolMemberOpts) bool {
+	if opts.Weight != 0 && opts.Weight != m.Weight {
+		return true
+	}
+	if opts.AdminStateUp != nil && *opts.AdminStateUp != memberAdminStateUp(m) {
+		return true
+	}
+	if opts.MonitorAddress != nil && !opts.MonitorAddress.Equal(m.MonitorAddress) {
+		return true
+	}
+	if opts.MonitorPort != nil && (m.MonitorPort == nil || *m.MonitorPort != *opts.MonitorPort) {
+		return true
+	}
+	return false
+}
+
+// DiffMembers compares current pool members with the desired ones. Members are matched by address
+// and protocol port; zero weight and nil admin state, monitor address and monitor port are not compared.
+func DiffMembers(current []PoolMember, desired []CreatePoolMemberOpts) (*MembersDiff, error) {
+	existing := make(map[string]PoolMember, len(current))
+	for _, m := range current {
+		if m.Address == nil {
+			continue
+		}
+		existing[MemberKey(*m.Address, m.ProtocolPort)] = m
+	}
+
+	var diff MembersDiff
+	seen := make(map[string]bool, len(desired))
+	for _, opts := range desired {
+		key := MemberKey(opts.Address, opts.ProtocolPort)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate pool member %s", key)
+		}
+		seen[key] = true
+		m, ok := existing[key]
+		switch {
+		case !ok:
+			diff.Create = append(diff.Create, opts)
+		case memberChanged(m, opts):
+			diff.Update = append(diff.Update, MemberUpdate{Member: m, Opts: opts})
+		}
+	}
+	for _, m := range current {
+		if m.Address == nil || !seen[MemberKey(*m.Address, m.ProtocolPort)] {
+			diff.Delete = append(diff.Delete, m)
+		}
+	}
+	return &diff, nil
+}
+
+func waitForTasks(c *gcorecloud.ServiceClient, r tasks.Result, waitSeconds int) error {
+	results, err := r.Extract()
+	if err != nil {
+		return err
+	}
+	_, err = tasks.WaitForTaskResults(c, results, waitSeconds)
+	return err
+}
+
+// ReconcileMembers brings the pool members to the desired state issuing only the required calls.
+// New members are created first and stale ones deleted afterwards to avoid traffic loss.
+// As there is no member update call, changed members are updated in place with a pool update
+// carrying the full member list. Every task is waited for up to waitSeconds, no further call is made
+// once the context is done.
+func ReconcileMembers(ctx context.Context, c *gcorecloud.ServiceClient, poolID string, desired []CreatePoolMemberOpts, waitSeconds int) (*MembersDiff, error) {
+	pool, err := Get(c, poolID).Extract()
+	if err != nil {
+		return nil, err
+	}
+	diff, err := DiffMembers(pool.Members, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, opts := range diff.Create {
+		if err := ctx.Err(); err != nil {
+			return diff, err
+		}
+		if err := waitForTasks(c, CreateMember(c, poolID, opts, nil), waitSeconds); err != nil {
+			return diff, fmt.Errorf("cannot create pool %s member %s: %w", poolID, MemberKey(opts.Address, opts.ProtocolPort), err)
+		}
+	}
+	for _, m := range diff.Delete {
+		if err := ctx.Err(); err != nil {
+			return diff, err
+		}
+		if err := waitForTasks(c, DeleteMember(c, poolID, m.ID, nil), waitSeconds); err != nil {
+			return diff, fmt.Errorf("cannot delete pool %s member %s: %w", poolID, m.ID, err)
+		}
+	}
+	if len(diff.Update) == 0 {
+		return diff, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return diff, err
+	}
+	if len(diff.Create) > 0 {
+		// the pool update replaces the member list, created members have to keep their IDs
+		if pool, err = Get(c, poolID).Extract(); err != nil {
+			return diff, err
+		}
+	}
+
+	ids := make(map[string]string, len(pool.Members))
+	for _, m := range pool.Members {
+		if m.Address != nil {
+			ids[MemberKey(*m.Address, m.ProtocolPort)] = m.ID
+		}
+	}
+	members := make([]CreatePoolMemberOpts, 0, len(desired))
+	for _, opts := range desired {
+		if opts.ID == "" {
+			opts.ID = ids[MemberKey(opts.Address, opts.ProtocolPort)]
+		}
+		members = append(members, opts)
+	}
+	if err := waitForTasks(c, Update(c, poolID, UpdateOpts{Members: members}, nil), waitSeconds); err != nil {
+		return diff, fmt.Errorf("cannot update pool %s members: %w", poolID, err)
+	}
+	return diff, nil
+}
@@ -99,6 +99,7 @@ type CreatePoolMemberOpts struct {
 	InstanceID     string `json:"instance_id,omitempty"`
 	MonitorAddress net.IP `json:"monitor_address,omitempty"`
 	MonitorPort    *int   `json:"monitor_port,omitempty"`
+	AdminStateUp   *bool  `json:"admin_state_up,omitempty"`
 }
 
 // CreateOpts represents options used to create a lbpool.
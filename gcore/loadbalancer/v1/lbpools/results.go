@@ -66,6 +66,7 @@ type PoolMember struct {
 	OperatingStatus    types.OperatingStatus    `json:"operating_status,omitempty"`
 	MonitorAddress     net.IP                   `json:"monitor_address,omitempty"`
 	MonitorPort        *int                     `json:"monitor_port,omitempty"`
+	AdminStateUp       *bool                    `json:"admin_state_up,omitempty"`
 }
 
 // Pool represents a pool structure.
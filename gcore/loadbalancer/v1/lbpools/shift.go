@@ -0,0 +1,227 @@
+package lbpools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/types"
+)
+
+// ShiftTotalWeight is the weight split between the source and the target group on every shift step.
+const ShiftTotalWeight = 100
+
+// MemberGroup selects pool members by member ID, instance ID or address.
+type MemberGroup []string
+
+// Contains checks whether the pool member is selected by the group.
+func (g MemberGroup) Contains(m PoolMember) bool {
+	for _, s := range g {
+		if s == m.ID || (m.InstanceID != "" && s == m.InstanceID) {
+			return true
+		}
+		if m.Address != nil && (s == m.Address.String() || s == MemberKey(*m.Address, m.ProtocolPort)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ShiftOpts represents options used to shift traffic between two groups of pool members.
+type ShiftOpts struct {
+	From        MemberGroup
+	To          MemberGroup
+	Steps       int
+	Interval    time.Duration
+	WaitSeconds int
+}
+
+// Validate checks shift options.
+func (opts ShiftOpts) Validate() error {
+	if len(opts.From) == 0 || len(opts.To) == 0 {
+		return fmt.Errorf("both source and target member groups should be set")
+	}
+	if opts.Steps <= 0 {
+		return fmt.Errorf("steps should be positive, got %d", opts.Steps)
+	}
+	if opts.Interval < 0 {
+		return fmt.Errorf("interval should not be negative, got %s", opts.Interval)
+	}
+	return nil
+}
+
+// MembersToOpts converts pool members to the options reproducing their current state.
+func MembersToOpts(members []PoolMember) []CreatePoolMemberOpts {
+	result := make([]CreatePoolMemberOpts, 0, len(members))
+	for _, m := range members {
+		if m.Address == nil {
+			continue
+		}
+		adminStateUp := memberAdminStateUp(m)
+		result = append(result, CreatePoolMemberOpts{
+			ID:             m.ID,
+			Address:        *m.Address,
+			ProtocolPort:   m.ProtocolPort,
+			Weight:         m.Weight,
+			SubnetID:       m.SubnetID,
+			InstanceID:     m.InstanceID,
+			MonitorAddress: m.MonitorAddress,
+			MonitorPort:    m.MonitorPort,
+			AdminStateUp:   &adminStateUp,
+		})
+	}
+	return result
+}
+
+func groupMemberOpts(m PoolMember, weight int) CreatePoolMemberOpts {
+	opts := MembersToOpts([]PoolMember{m})[0]
+	// zero weight can not be sent, so the member is disabled instead
+	adminStateUp := weight > 0
+	if !adminStateUp {
+		weight = 1
+	}
+	opts.Weight = weight
+	opts.AdminStateUp = &adminStateUp
+	return opts
+}
+
+// ShiftStep returns the desired pool members for the given step. On step i of n the source group gets
+// ShiftTotalWeight*(n-i)/n and the target group ShiftTotalWeight*i/n, a group with zero weight is disabled.
+// Members not selected by any group are kept as is.
+func ShiftStep(members []PoolMember, opts ShiftOpts, step int) ([]CreatePoolMemberOpts, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if step < 0 || step > opts.Steps {
+		return nil, fmt.Errorf("step %d is out of range [0, %d]", step, opts.Steps)
+	}
+	toWeight := ShiftTotalWeight * step / opts.Steps
+	fromWeight := ShiftTotalWeight - toWeight
+
+	var fromCount, toCount int
+	result := make([]CreatePoolMemberOpts, 0, len(members))
+	for _, m := range members {
+		if m.Address == nil {
+			continue
+		}
+		inFrom, inTo := opts.From.Contains(m), opts.To.Contains(m)
+		switch {
+		case inFrom && inTo:
+			return nil, fmt.Errorf("pool member %s is selected by both groups", m.ID)
+		case inFrom:
+			fromCount++
+			result = append(result, groupMemberOpts(m, fromWeight))
+		case inTo:
+			toCount++
+			result = append(result, groupMemberOpts(m, toWeight))
+		default:
+			result = append(result, MembersToOpts([]PoolMember{m})...)
+		}
+	}
+	if fromCount == 0 {
+		return nil, fmt.Errorf("no pool members match source group %s", strings.Join(opts.From, ", "))
+	}
+	if toCount == 0 {
+		return nil, fmt.Errorf("no pool members match target group %s", strings.Join(opts.To, ", "))
+	}
+	return result, nil
+}
+
+// UnhealthyMembers returns enabled members of the group which are neither online nor unmonitored.
+func UnhealthyMembers(members []PoolMember, group MemberGroup) []PoolMember {
+	var result []PoolMember
+	for _, m := range members {
+		if !group.Contains(m) || !memberAdminStateUp(m) {
+			continue
+		}
+		switch m.OperatingStatus {
+		case types.OperatingStatusOnline, types.OperatingStatusNoMonitor:
+		default:
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// ShiftRevertedError is returned when the shift was stopped because target members became unhealthy.
+type ShiftRevertedError struct {
+	Step      int
+	Unhealthy []PoolMember
+	RevertErr error
+}
+
+func (e *ShiftRevertedError) Error() string {
+	ids := make([]string, 0, len(e.Unhealthy))
+	for _, m := range e.Unhealthy {
+		ids = append(ids, fmt.Sprintf("%s (%s)", m.ID, m.OperatingStatus))
+	}
+	msg := fmt.Sprintf("unhealthy members on step %d: %s", e.Step, strings.Join(ids, ", "))
+	if e.RevertErr != nil {
+		return fmt.Sprintf("%s; revert failed: %s", msg, e.RevertErr)
+	}
+	return msg + "; members reverted"
+}
+
+func (e *ShiftRevertedError) Unwrap() error {
+	return e.RevertErr
+}
+
+// Shift gradually moves traffic from one group of pool members to another. After every step it waits
+// for opts.Interval and checks the operating status of the target group. When a target member turns unhealthy
+// the members are reverted to their original state and ShiftRevertedError is returned. When ctx is done
+// the members are reverted as well, the revert does not depend on ctx so the pool is not left half-shifted.
+func Shift(ctx context.Context, c *gcorecloud.ServiceClient, poolID string, opts ShiftOpts) (*Pool, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	pool, err := Get(c, poolID).Extract()
+	if err != nil {
+		return nil, err
+	}
+	original := pool.Members
+	// check both groups before changing anything
+	if _, err := ShiftStep(original, opts, 0); err != nil {
+		return nil, err
+	}
+
+	// the revert runs on a fresh context as ctx may be already done, every task is still bounded by opts.WaitSeconds
+	revert := func() error {
+		_, err := ReconcileMembers(context.Background(), c, poolID, MembersToOpts(original), opts.WaitSeconds)
+		return err
+	}
+	stopped := func(step int) error {
+		err := fmt.Errorf("shift stopped on step %d: %w", step, ctx.Err())
+		if revertErr := revert(); revertErr != nil {
+			return fmt.Errorf("%w; revert failed: %w", err, revertErr)
+		}
+		return fmt.Errorf("%w; members reverted", err)
+	}
+
+	for step := 1; step <= opts.Steps; step++ {
+		desired, err := ShiftStep(original, opts, step)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := ReconcileMembers(ctx, c, poolID, desired, opts.WaitSeconds); err != nil {
+			if ctx.Err() != nil {
+				return nil, stopped(step)
+			}
+			return nil, fmt.Errorf("cannot apply shift step %d: %w", step, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, stopped(step)
+		case <-time.After(opts.Interval):
+		}
+		pool, err = Get(c, poolID).Extract()
+		if err != nil {
+			return nil, err
+		}
+		if unhealthy := UnhealthyMembers(pool.Members, opts.To); len(unhealthy) > 0 {
+			return nil, &ShiftRevertedError{Step: step, Unhealthy: unhealthy, RevertErr: revert()}
+		}
+	}
+	return pool, nil
+}
@@ -0,0 +1,162 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/lbpools"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+
+	"github.com/stretchr/testify/require"
+
+	log "github.com/sirupsen/logrus"
+
+	th "github.com/G-Core/gcorelabscloud-go/testhelper"
+)
+
+const FinishedTaskResponse = `
+{
+  "id": "50f53a35-42ed-40c4-82b2-5a37fb3e00bc",
+  "state": "FINISHED",
+  "task_type": "update_lbpool",
+  "client_id": 1,
+  "user_id": 1,
+  "user_client_id": 1,
+  "created_on": "2020-02-03T13:10:22",
+  "created_resources": null,
+  "error": null
+}
+`
+
+const ReconcileUpdateRequest = `
+{
+  "members": [
+    {
+      "id": "65f4e0eb-7846-490e-b44d-726c8baf3c25",
+      "address": "192.168.13.9",
+      "protocol_port": 80,
+      "weight": 5
+    },
+    {
+      "id": "d1c6f3a4-5b7e-4a0f-9c2d-8e3b1f6a7c90",
+      "address": "192.168.13.10",
+      "protocol_port": 80
+    }
+  ]
+}
+`
+
+// reconciledGetResponse is the pool after 192.168.13.10 was created and 192.168.13.8 deleted.
+var reconciledGetResponse = strings.Replace(
+	strings.Replace(GetResponse, `"address": "192.168.13.8"`, `"address": "192.168.13.10"`, 1),
+	Member2.ID, "d1c6f3a4-5b7e-4a0f-9c2d-8e3b1f6a7c90", 1,
+)
+
+func TestDiffMembers(t *testing.T) {
+	up := false
+	desired := []lbpools.CreatePoolMemberOpts{
+		{Address: ip1, ProtocolPort: protocolPort},
+		{Address: ip2, ProtocolPort: protocolPort, AdminStateUp: &up},
+		{Address: net.ParseIP("192.168.13.10"), ProtocolPort: protocolPort},
+	}
+	diff, err := lbpools.DiffMembers(LBPool1.Members, desired)
+	require.NoError(t, err)
+	require.Equal(t, []lbpools.CreatePoolMemberOpts{desired[2]}, diff.Create)
+	require.Equal(t, []lbpools.MemberUpdate{{Member: Member2, Opts: desired[1]}}, diff.Update)
+	require.Len(t, diff.Delete, 0)
+	require.False(t, diff.IsEmpty())
+
+	diff, err = lbpools.DiffMembers(LBPool1.Members, desired[:1])
+	require.NoError(t, err)
+	require.Len(t, diff.Create, 0)
+	require.Len(t, diff.Update, 0)
+	require.Equal(t, []lbpools.PoolMember{Member2}, diff.Delete)
+
+	diff, err = lbpools.DiffMembers(LBPool1.Members, []lbpools.CreatePoolMemberOpts{
+		{Address: ip1, ProtocolPort: protocolPort, Weight: width},
+		{Address: ip2, ProtocolPort: protocolPort},
+	})
+	require.NoError(t, err)
+	require.True(t, diff.IsEmpty())
+
+	_, err = lbpools.DiffMembers(LBPool1.Members, []lbpools.CreatePoolMemberOpts{desired[0], desired[0]})
+	require.Error(t, err)
+}
+
+func TestReconcileMembers(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	calls := make(map[string]int)
+	th.Mux.HandleFunc(prepareGetTestURL(LBPool1.ID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+		calls[r.Method]++
+		w.Header().Add("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			w.WriteHeader(http.StatusOK)
+			response := GetResponse
+			if calls["member POST"] > 0 {
+				response = reconciledGetResponse
+			}
+			_, _ = fmt.Fprint(w, response)
+		case "PATCH":
+			th.TestJSONRequest(t, r, ReconcileUpdateRequest)
+			w.WriteHeader(http.StatusOK)
+			_, _ = fmt.Fprint(w, UpdateResponse)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+	th.Mux.HandleFunc(prepareCreateMemberURL(LBPool1.ID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		body, _ := io.ReadAll(r.Body)
+		require.Contains(t, string(body), "192.168.13.10")
+		calls["member POST"]++
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, CreateMemberResponse)
+	})
+	th.Mux.HandleFunc(prepareDeleteMemberURL(LBPool1.ID, Member2.ID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "DELETE")
+		calls["member DELETE"]++
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, DeleteMemberResponse)
+	})
+	th.Mux.HandleFunc(fmt.Sprintf("/v1/tasks/%s", Tasks1.Tasks[0]), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err := fmt.Fprint(w, FinishedTaskResponse)
+		if err != nil {
+			log.Error(err)
+		}
+	})
+
+	desired := []lbpools.CreatePoolMemberOpts{
+		{Address: ip1, ProtocolPort: protocolPort, Weight: 5},
+		{Address: net.ParseIP("192.168.13.10"), ProtocolPort: protocolPort},
+	}
+
+	client := fake.ServiceTokenClient("lbpools", "v1")
+	diff, err := lbpools.ReconcileMembers(context.Background(), client, LBPool1.ID, desired, 10)
+	require.NoError(t, err)
+	require.Len(t, diff.Create, 1)
+	require.Len(t, diff.Update, 1)
+	require.Equal(t, []lbpools.PoolMember{Member2}, diff.Delete)
+	require.Equal(t, map[string]int{"GET": 2, "PATCH": 1, "member POST": 1, "member DELETE": 1}, calls)
+
+	calls = make(map[string]int)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = lbpools.ReconcileMembers(ctx, client, LBPool1.ID, desired, 10)
+	require.True(t, errors.Is(err, context.Canceled))
+	require.Equal(t, map[string]int{"GET": 1}, calls)
+}
@@ -0,0 +1,159 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/lbpools"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/types"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+
+	"github.com/stretchr/testify/require"
+
+	th "github.com/G-Core/gcorelabscloud-go/testhelper"
+)
+
+var shiftOpts = lbpools.ShiftOpts{
+	From:        lbpools.MemberGroup{ip1.String()},
+	To:          lbpools.MemberGroup{Member2.ID},
+	Steps:       4,
+	WaitSeconds: 10,
+}
+
+func TestShiftStep(t *testing.T) {
+	up, down := true, false
+
+	members, err := lbpools.ShiftStep(LBPool1.Members, shiftOpts, 1)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	require.Equal(t, 75, members[0].Weight)
+	require.Equal(t, &up, members[0].AdminStateUp)
+	require.Equal(t, Member1.ID, members[0].ID)
+	require.Equal(t, 25, members[1].Weight)
+	require.Equal(t, &up, members[1].AdminStateUp)
+
+	members, err = lbpools.ShiftStep(LBPool1.Members, shiftOpts, 4)
+	require.NoError(t, err)
+	require.Equal(t, 1, members[0].Weight)
+	require.Equal(t, &down, members[0].AdminStateUp)
+	require.Equal(t, 100, members[1].Weight)
+
+	_, err = lbpools.ShiftStep(LBPool1.Members, shiftOpts, 5)
+	require.Error(t, err)
+
+	opts := shiftOpts
+	opts.To = lbpools.MemberGroup{"10.0.0.1"}
+	_, err = lbpools.ShiftStep(LBPool1.Members, opts, 1)
+	require.Error(t, err)
+
+	opts.To = lbpools.MemberGroup{lbpools.MemberKey(ip1, protocolPort)}
+	_, err = lbpools.ShiftStep(LBPool1.Members, opts, 1)
+	require.Error(t, err)
+}
+
+func TestUnhealthyMembers(t *testing.T) {
+	down := false
+	online, failed, disabled := Member1, Member2, Member2
+	online.OperatingStatus = types.OperatingStatusOnline
+	failed.OperatingStatus = types.OperatingStatusOperatingError
+	disabled.OperatingStatus = types.OperatingStatusOffline
+	disabled.AdminStateUp = &down
+
+	group := lbpools.MemberGroup{Member1.ID, Member2.ID}
+	require.Len(t, lbpools.UnhealthyMembers([]lbpools.PoolMember{online, disabled}, group), 0)
+	require.Equal(t, []lbpools.PoolMember{failed}, lbpools.UnhealthyMembers([]lbpools.PoolMember{online, failed}, group))
+	require.Len(t, lbpools.UnhealthyMembers([]lbpools.PoolMember{failed}, lbpools.MemberGroup{Member1.ID}), 0)
+}
+
+func setupShift(t *testing.T, getResponse, shiftedResponse string, patched func()) map[string]int {
+	calls := make(map[string]int)
+	th.Mux.HandleFunc(prepareGetTestURL(LBPool1.ID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+		calls[r.Method]++
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		switch r.Method {
+		case "GET":
+			if calls["PATCH"] > 0 {
+				_, _ = fmt.Fprint(w, shiftedResponse)
+				return
+			}
+			_, _ = fmt.Fprint(w, getResponse)
+		case "PATCH":
+			if patched != nil {
+				patched()
+			}
+			_, _ = fmt.Fprint(w, UpdateResponse)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	})
+	th.Mux.HandleFunc(fmt.Sprintf("/v1/tasks/%s", Tasks1.Tasks[0]), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, FinishedTaskResponse)
+	})
+	return calls
+}
+
+func TestShift(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	healthy := strings.ReplaceAll(GetResponse, `"protocol_port": 80`, `"protocol_port": 80, "operating_status": "ONLINE"`)
+	calls := setupShift(t, healthy, healthy, nil)
+
+	client := fake.ServiceTokenClient("lbpools", "v1")
+	pool, err := lbpools.Shift(context.Background(), client, LBPool1.ID, shiftOpts)
+	require.NoError(t, err)
+	require.Equal(t, LBPool1.ID, pool.ID)
+	// initial state plus one check per step, every step reconciles members with a single update
+	require.Equal(t, map[string]int{"GET": 9, "PATCH": 4}, calls)
+}
+
+func TestShiftRevert(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	// members are left unhealthy with shifted weights after the first step
+	shifted := strings.ReplaceAll(GetResponse, `"weight": 1`, `"weight": 50`)
+	calls := setupShift(t, GetResponse, shifted, nil)
+
+	client := fake.ServiceTokenClient("lbpools", "v1")
+	_, err := lbpools.Shift(context.Background(), client, LBPool1.ID, shiftOpts)
+	require.Error(t, err)
+	var shiftErr *lbpools.ShiftRevertedError
+	require.True(t, errors.As(err, &shiftErr))
+	require.Equal(t, 1, shiftErr.Step)
+	require.Len(t, shiftErr.Unhealthy, 1)
+	require.Equal(t, Member2.ID, shiftErr.Unhealthy[0].ID)
+	require.NoError(t, shiftErr.RevertErr)
+	require.Equal(t, map[string]int{"GET": 4, "PATCH": 2}, calls)
+}
+
+func TestShiftCancelled(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	// the context is cancelled once the first step is applied
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	shifted := strings.ReplaceAll(GetResponse, `"weight": 1`, `"weight": 50`)
+	calls := setupShift(t, GetResponse, shifted, cancel)
+
+	opts := shiftOpts
+	opts.Interval = time.Hour
+	client := fake.ServiceTokenClient("lbpools", "v1")
+	_, err := lbpools.Shift(ctx, client, LBPool1.ID, opts)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, context.Canceled))
+	require.Contains(t, err.Error(), "members reverted")
+	// the revert is applied with its own update even though the context is done
+	require.Equal(t, map[string]int{"GET": 3, "PATCH": 2}, calls)
+}
@@ -37,5 +37,32 @@ Example to Delete a LoadBalancer
 	if err != nil {
 		panic(err)
 	}
+
+Example to Provision a LoadBalancer with a listener, pool and members
+
+	clients := loadbalancers.ProvisionClients{
+		LoadBalancers: loadbalancerClient,
+		Listeners:     listenerClient,
+		Pools:         poolClient,
+	}
+
+	provisioned, err := loadbalancers.NewBuilder(clients, loadbalancers.CreateOpts{Name: "lb"}).
+		WithListener(loadbalancers.ProvisionListenerOpts{
+			Listener: listeners.CreateOpts{Name: "http", Protocol: types.ProtocolTypeHTTP, ProtocolPort: 80},
+			Pools: []loadbalancers.ProvisionPoolOpts{{
+				Pool: lbpools.CreateOpts{
+					Name:            "web",
+					Protocol:        types.ProtocolTypeHTTP,
+					LBPoolAlgorithm: types.LoadBalancerAlgorithmRoundRobin,
+				},
+				Members: []lbpools.CreatePoolMemberOpts{
+					{Address: net.ParseIP("10.0.0.10"), ProtocolPort: 8080},
+				},
+			}},
+		}).
+		Provision()
+	if err != nil {
+		panic(err)
+	}
 */
 package loadbalancers
@@ -0,0 +1,410 @@
+package loadbalancers
+
+import (
+	"errors"
+	"fmt"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/l7policies"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/lbpools"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/listeners"
+	"github.com/G-Core/gcorelabscloud-go/gcore/task/v1/tasks"
+)
+
+// ProvisionWaitSeconds is the default time to wait for every provisioning task.
+const ProvisionWaitSeconds = 1200
+
+// ProvisionClients represents the service clients used to provision a loadbalancer with its children.
+// L7Policies is only required when l7 policies are provisioned.
+type ProvisionClients struct {
+	LoadBalancers *gcorecloud.ServiceClient
+	Listeners     *gcorecloud.ServiceClient
+	Pools         *gcorecloud.ServiceClient
+	L7Policies    *gcorecloud.ServiceClient
+}
+
+// ProvisionPoolOpts represents options used to provision a listener pool.
+// LoadBalancerID and ListenerID of Pool are filled in by the provisioner,
+// members and health monitor are created after the pool.
+type ProvisionPoolOpts struct {
+	Pool          lbpools.CreateOpts
+	Members       []lbpools.CreatePoolMemberOpts
+	HealthMonitor *lbpools.CreateHealthMonitorOpts
+}
+
+// ProvisionL7PolicyOpts represents options used to provision a listener l7 policy with its rules.
+// RedirectPoolName references a pool of the same listener by name and takes precedence over Policy.RedirectPoolID.
+type ProvisionL7PolicyOpts struct {
+	Policy           l7policies.CreateOpts
+	RedirectPoolName string
+	Rules            []l7policies.CreateRuleOpts
+}
+
+// ProvisionListenerOpts represents options used to provision a loadbalancer listener.
+// LoadBalancerID of Listener is filled in by the provisioner.
+type ProvisionListenerOpts struct {
+	Listener   listeners.CreateOpts
+	Pools      []ProvisionPoolOpts
+	L7Policies []ProvisionL7PolicyOpts
+}
+
+// ProvisionOpts represents options used to provision a loadbalancer with its listeners, pools,
+// members, health monitors and l7 policies.
+type ProvisionOpts struct {
+	LoadBalancer CreateOpts
+	Listeners    []ProvisionListenerOpts
+	WaitSeconds  int
+}
+
+// placeholderID stands in for IDs that are only known during provisioning.
+const placeholderID = "00000000-0000-0000-0000-000000000000"
+
+// Validate ProvisionOpts
+func (opts ProvisionOpts) Validate() error {
+	if len(opts.LoadBalancer.Listeners) > 0 {
+		return fmt.Errorf("listeners should be set in ProvisionOpts.Listeners")
+	}
+	if opts.WaitSeconds < 0 {
+		return fmt.Errorf("wait seconds should not be negative")
+	}
+	if err := gcorecloud.ValidateStruct(opts.LoadBalancer); err != nil {
+		return err
+	}
+	for _, l := range opts.Listeners {
+		lOpts := l.Listener
+		lOpts.LoadBalancerID = placeholderID
+		if err := gcorecloud.ValidateStruct(lOpts); err != nil {
+			return fmt.Errorf("listener %s: %w", l.Listener.Name, err)
+		}
+		poolNames := make(map[string]bool, len(l.Pools))
+		for _, p := range l.Pools {
+			if poolNames[p.Pool.Name] {
+				return fmt.Errorf("listener %s: duplicate pool name %s", l.Listener.Name, p.Pool.Name)
+			}
+			poolNames[p.Pool.Name] = true
+			if err := gcorecloud.ValidateStruct(p.Pool); err != nil {
+				return fmt.Errorf("pool %s: %w", p.Pool.Name, err)
+			}
+			for _, m := range p.Members {
+				if err := gcorecloud.ValidateStruct(m); err != nil {
+					return fmt.Errorf("pool %s member: %w", p.Pool.Name, err)
+				}
+			}
+			if p.HealthMonitor != nil {
+				if err := gcorecloud.ValidateStruct(*p.HealthMonitor); err != nil {
+					return fmt.Errorf("pool %s healthmonitor: %w", p.Pool.Name, err)
+				}
+			}
+		}
+		for _, p := range l.L7Policies {
+			pOpts := p.Policy
+			pOpts.ListenerID = placeholderID
+			if p.RedirectPoolName != "" {
+				if !poolNames[p.RedirectPoolName] {
+					return fmt.Errorf("listener %s: l7 policy %s references unknown pool %s", l.Listener.Name, p.Policy.Name, p.RedirectPoolName)
+				}
+				pOpts.RedirectPoolID = placeholderID
+			}
+			if err := gcorecloud.ValidateStruct(pOpts); err != nil {
+				return fmt.Errorf("l7 policy %s: %w", p.Policy.Name, err)
+			}
+			for _, r := range p.Rules {
+				if err := gcorecloud.ValidateStruct(r); err != nil {
+					return fmt.Errorf("l7 policy %s rule: %w", p.Policy.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ProvisionedPool represents a provisioned listener pool.
+type ProvisionedPool struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	MemberIDs       []string `json:"member_ids"`
+	HealthMonitorID string   `json:"healthmonitor_id,omitempty"`
+}
+
+// ProvisionedL7Policy represents a provisioned l7 policy.
+type ProvisionedL7Policy struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	RuleIDs []string `json:"rule_ids"`
+}
+
+// ProvisionedListener represents a provisioned loadbalancer listener.
+type ProvisionedListener struct {
+	ID         string                `json:"id"`
+	Name       string                `json:"name"`
+	Pools      []ProvisionedPool     `json:"pools"`
+	L7Policies []ProvisionedL7Policy `json:"l7policies"`
+}
+
+// Provisioned represents the resources created by a provisioner.
+type Provisioned struct {
+	LoadBalancerID string                `json:"loadbalancer_id"`
+	Listeners      []ProvisionedListener `json:"listeners"`
+}
+
+// RollbackError is returned by Provision when provisioning fails.
+// Err is the provisioning error and RollbackErrs hold errors occurred while deleting created resources.
+type RollbackError struct {
+	Err          error
+	RollbackErrs []error
+}
+
+func (e *RollbackError) Error() string {
+	if len(e.RollbackErrs) == 0 {
+		return fmt.Sprintf("provisioning failed, created resources were deleted: %s", e.Err)
+	}
+	return fmt.Sprintf("provisioning failed: %s. Rollback failed: %s", e.Err, errors.Join(e.RollbackErrs...))
+}
+
+func (e *RollbackError) Unwrap() error {
+	return e.Err
+}
+
+type rollbackStep struct {
+	name string
+	run  func() error
+}
+
+// Builder provisions a loadbalancer with its children in dependency order,
+// waiting on every task and deleting created resources on failure.
+type Builder struct {
+	clients  ProvisionClients
+	opts     ProvisionOpts
+	rollback []rollbackStep
+}
+
+// NewBuilder returns a Builder for the given loadbalancer.
+func NewBuilder(clients ProvisionClients, lb CreateOpts) *Builder {
+	return &Builder{
+		clients: clients,
+		opts:    ProvisionOpts{LoadBalancer: lb},
+	}
+}
+
+// WithListener adds a listener with its pools and l7 policies to the provisioning spec.
+func (b *Builder) WithListener(opts ProvisionListenerOpts) *Builder {
+	b.opts.Listeners = append(b.opts.Listeners, opts)
+	return b
+}
+
+// WithWaitSeconds sets the time to wait for every provisioning task.
+func (b *Builder) WithWaitSeconds(secs int) *Builder {
+	b.opts.WaitSeconds = secs
+	return b
+}
+
+// ProvisionFull creates a loadbalancer, its listeners, pools, members, health monitors,
+// l7 policies and rules described by opts. If any step fails, every created resource is deleted
+// in reverse order, children before their parents.
+func ProvisionFull(clients ProvisionClients, opts ProvisionOpts) (*Provisioned, error) {
+	b := &Builder{clients: clients, opts: opts}
+	return b.Provision()
+}
+
+// Provision executes the provisioning spec.
+func (b *Builder) Provision() (*Provisioned, error) {
+	if err := b.opts.Validate(); err != nil {
+		return nil, err
+	}
+	if b.opts.WaitSeconds == 0 {
+		b.opts.WaitSeconds = ProvisionWaitSeconds
+	}
+	if err := b.validateClients(); err != nil {
+		return nil, err
+	}
+	b.rollback = nil
+
+	result, err := b.provision()
+	if err != nil {
+		return nil, &RollbackError{Err: err, RollbackErrs: b.doRollback()}
+	}
+	return result, nil
+}
+
+func (b *Builder) validateClients() error {
+	if b.clients.LoadBalancers == nil || b.clients.Listeners == nil || b.clients.Pools == nil {
+		return fmt.Errorf("loadbalancers, listeners and pools clients are required")
+	}
+	for _, l := range b.opts.Listeners {
+		if len(l.L7Policies) > 0 && b.clients.L7Policies == nil {
+			return fmt.Errorf("l7policies client is required")
+		}
+	}
+	return nil
+}
+
+func (b *Builder) wait(client *gcorecloud.ServiceClient, r tasks.Result, extract func(*tasks.Task) (string, error)) (string, error) {
+	results, err := r.Extract()
+	if err != nil {
+		return "", err
+	}
+	task, err := tasks.WaitForTaskResults(client, results, b.opts.WaitSeconds)
+	if err != nil {
+		return "", err
+	}
+	if extract == nil {
+		return "", nil
+	}
+	return extract(task)
+}
+
+// addRollback registers the deletion of a created resource. Already deleted resources are skipped.
+func (b *Builder) addRollback(name string, del func() error) {
+	b.rollback = append(b.rollback, rollbackStep{
+		name: name,
+		run: func() error {
+			err := del()
+			var notFound gcorecloud.ErrDefault404
+			if errors.As(err, &notFound) {
+				return nil
+			}
+			return err
+		},
+	})
+}
+
+// addTaskRollback registers the deletion of a created resource waiting for the deletion task.
+func (b *Builder) addTaskRollback(name string, client *gcorecloud.ServiceClient, del func() tasks.Result) {
+	b.addRollback(name, func() error {
+		_, err := b.wait(client, del(), nil)
+		return err
+	})
+}
+
+func (b *Builder) doRollback() []error {
+	var errs []error
+	for i := len(b.rollback) - 1; i >= 0; i-- {
+		step := b.rollback[i]
+		if err := step.run(); err != nil {
+			errs = append(errs, fmt.Errorf("cannot delete %s: %w", step.name, err))
+		}
+	}
+	b.rollback = nil
+	return errs
+}
+
+func (b *Builder) provision() (*Provisioned, error) {
+	c := b.clients
+	lbID, err := b.wait(c.LoadBalancers, Create(c.LoadBalancers, b.opts.LoadBalancer, nil), ExtractLoadBalancerIDFromTask)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create loadbalancer %s: %w", b.opts.LoadBalancer.Name, err)
+	}
+	b.addTaskRollback("loadbalancer "+lbID, c.LoadBalancers, func() tasks.Result {
+		return Delete(c.LoadBalancers, lbID, nil)
+	})
+
+	result := Provisioned{LoadBalancerID: lbID}
+	for _, lOpts := range b.opts.Listeners {
+		listener, err := b.provisionListener(lbID, lOpts)
+		if err != nil {
+			return nil, err
+		}
+		result.Listeners = append(result.Listeners, *listener)
+	}
+	return &result, nil
+}
+
+func (b *Builder) provisionListener(lbID string, opts ProvisionListenerOpts) (*ProvisionedListener, error) {
+	c := b.clients
+	lOpts := opts.Listener
+	lOpts.LoadBalancerID = lbID
+	listenerID, err := b.wait(c.Listeners, listeners.Create(c.Listeners, lOpts, nil), listeners.ExtractListenerIDFromTask)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create listener %s: %w", lOpts.Name, err)
+	}
+	b.addTaskRollback("listener "+listenerID, c.Listeners, func() tasks.Result {
+		return listeners.Delete(c.Listeners, listenerID, nil)
+	})
+
+	result := ProvisionedListener{ID: listenerID, Name: lOpts.Name}
+	poolIDs := make(map[string]string, len(opts.Pools))
+	for _, pOpts := range opts.Pools {
+		pool, err := b.provisionPool(lbID, listenerID, pOpts)
+		if err != nil {
+			return nil, err
+		}
+		poolIDs[pool.Name] = pool.ID
+		result.Pools = append(result.Pools, *pool)
+	}
+	for _, policyOpts := range opts.L7Policies {
+		policy, err := b.provisionL7Policy(listenerID, poolIDs, policyOpts)
+		if err != nil {
+			return nil, err
+		}
+		result.L7Policies = append(result.L7Policies, *policy)
+	}
+	return &result, nil
+}
+
+func (b *Builder) provisionPool(lbID, listenerID string, opts ProvisionPoolOpts) (*ProvisionedPool, error) {
+	c := b.clients
+	pOpts := opts.Pool
+	pOpts.LoadBalancerID = lbID
+	pOpts.ListenerID = listenerID
+	pOpts.Members = nil
+	pOpts.HealthMonitor = nil
+	poolID, err := b.wait(c.Pools, lbpools.Create(c.Pools, pOpts, nil), lbpools.ExtractPoolIDFromTask)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create pool %s: %w", pOpts.Name, err)
+	}
+	b.addTaskRollback("pool "+poolID, c.Pools, func() tasks.Result {
+		return lbpools.Delete(c.Pools, poolID, nil)
+	})
+
+	result := ProvisionedPool{ID: poolID, Name: pOpts.Name}
+	for _, mOpts := range opts.Members {
+		memberID, err := b.wait(c.Pools, lbpools.CreateMember(c.Pools, poolID, mOpts, nil), lbpools.ExtractPoolMemberIDFromTask)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create pool %s member %s: %w", pOpts.Name, mOpts.Address, err)
+		}
+		b.addTaskRollback("pool "+poolID+" member "+memberID, c.Pools, func() tasks.Result {
+			return lbpools.DeleteMember(c.Pools, poolID, memberID, nil)
+		})
+		result.MemberIDs = append(result.MemberIDs, memberID)
+	}
+	if opts.HealthMonitor != nil {
+		hmID, err := b.wait(c.Pools, lbpools.CreateHealthMonitor(c.Pools, poolID, *opts.HealthMonitor, nil), lbpools.ExtractHealthMonitorIDFromTask)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create pool %s healthmonitor: %w", pOpts.Name, err)
+		}
+		b.addRollback("pool "+poolID+" healthmonitor "+hmID, func() error {
+			return lbpools.DeleteHealthMonitor(c.Pools, poolID, nil).ExtractErr()
+		})
+		result.HealthMonitorID = hmID
+	}
+	return &result, nil
+}
+
+func (b *Builder) provisionL7Policy(listenerID string, poolIDs map[string]string, opts ProvisionL7PolicyOpts) (*ProvisionedL7Policy, error) {
+	c := b.clients
+	pOpts := opts.Policy
+	pOpts.ListenerID = listenerID
+	if opts.RedirectPoolName != "" {
+		pOpts.RedirectPoolID = poolIDs[opts.RedirectPoolName]
+	}
+	policyID, err := b.wait(c.L7Policies, l7policies.Create(c.L7Policies, pOpts), l7policies.ExtractL7PolicyIDFromTask)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create l7 policy %s: %w", pOpts.Name, err)
+	}
+	b.addTaskRollback("l7 policy "+policyID, c.L7Policies, func() tasks.Result {
+		return l7policies.Delete(c.L7Policies, policyID)
+	})
+
+	result := ProvisionedL7Policy{ID: policyID, Name: pOpts.Name}
+	for _, rOpts := range opts.Rules {
+		ruleID, err := b.wait(c.L7Policies, l7policies.CreateRule(c.L7Policies, policyID, rOpts), l7policies.ExtractRuleIDFromTask)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create l7 policy %s rule: %w", pOpts.Name, err)
+		}
+		b.addTaskRollback("l7 policy "+policyID+" rule "+ruleID, c.L7Policies, func() tasks.Result {
+			return l7policies.DeleteRule(c.L7Policies, policyID, ruleID)
+		})
+		result.RuleIDs = append(result.RuleIDs, ruleID)
+	}
+	return &result, nil
+}
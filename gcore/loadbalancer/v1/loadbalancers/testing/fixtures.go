@@ -190,6 +190,7 @@ var (
 	createdTime          = gcorecloud.JSONRFC3339Z{Time: createdTimeParsed}
 	updatedTimeParsed, _ = time.Parse(gcorecloud.RFC3339Z, updatedTimeString)
 	updatedTime          = gcorecloud.JSONRFC3339Z{Time: updatedTimeParsed}
+	dualStackIPFamily    = types.DualStackIPFamilyType
 	creatorTaskID        = "9f3ec11e-bcd4-4fe6-924a-a4439a56ad22"
 
 	LoadBalancer1 = loadbalancers.LoadBalancer{
@@ -215,7 +216,7 @@ var (
 			{IpAddress: net.ParseIP("10.94.76.179"), SubnetID: "db5ebada-a86a-4702-8a19-00b23a1acb05"},
 			{IpAddress: net.ParseIP("aa:bb:cc:dd::2b5"), SubnetID: "abd99b68-e139-4715-b8c2-37ca324285b8"},
 		},
-		VipIPFamilyType: types.DualStackIPFamilyType,
+		VipIPFamilyType: &dualStackIPFamily,
 		AdditionalVips: []loadbalancers.NetworkPortFixedIP{
 			{IpAddress: net.ParseIP("aa:bb:cc:dd::29d"), SubnetID: "abd99b68-e139-4715-b8c2-37ca324285b8"},
 		},
@@ -0,0 +1,250 @@
+package testing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/l7policies"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/lbpools"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/listeners"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/loadbalancers"
+	"github.com/G-Core/gcorelabscloud-go/gcore/loadbalancer/v1/types"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	provisionLoadBalancerID  = "e8ab1be4-1521-4266-be69-28dad4148a30"
+	provisionListenerID      = "43658ea9-54bd-4807-90b1-925921c9a0d1"
+	provisionPoolID          = "9fccf0a3-c0de-441d-9afd-2b9b58b08b9f"
+	provisionMemberID        = "65f4e0eb-7846-490e-b44d-726c8baf3c25"
+	provisionHealthMonitorID = "8ba2cfc7-1ab4-4d61-8df7-d4c3e1a9c3f4"
+	provisionL7PolicyID      = "94b5b7a2-1ff4-4a2e-9f6f-4c1e0b2a6d11"
+	provisionRuleID          = "5c5a7d0e-3f0f-4e3b-8a3e-7b0f9b6f1e22"
+)
+
+// provisionServer fakes the loadbalancer API for a single provisioning. Creating the failOn resource fails,
+// created and deleted resources are recorded in call order.
+type provisionServer struct {
+	t       *testing.T
+	failOn  string
+	mu      sync.Mutex
+	created []string
+	deleted []string
+	tasks   map[string]string
+	policy  map[string]interface{}
+}
+
+func newProvisionServer(t *testing.T, failOn string) (*provisionServer, loadbalancers.ProvisionClients) {
+	s := &provisionServer{t: t, failOn: failOn, tasks: map[string]string{}}
+	mux := http.NewServeMux()
+	base := fmt.Sprintf("%d/%d", fake.ProjectID, fake.RegionID)
+	mux.HandleFunc("/v1/loadbalancers/"+base, s.create("loadbalancers", provisionLoadBalancerID))
+	mux.HandleFunc("/v1/loadbalancers/"+base+"/"+provisionLoadBalancerID, s.delete("loadbalancers"))
+	mux.HandleFunc("/v1/listeners/"+base, s.create("listeners", provisionListenerID))
+	mux.HandleFunc("/v1/listeners/"+base+"/"+provisionListenerID, s.delete("listeners"))
+	mux.HandleFunc("/v1/lbpools/"+base, s.create("pools", provisionPoolID))
+	mux.HandleFunc("/v1/lbpools/"+base+"/"+provisionPoolID, s.delete("pools"))
+	mux.HandleFunc("/v1/lbpools/"+base+"/"+provisionPoolID+"/member", s.create("members", provisionMemberID))
+	mux.HandleFunc("/v1/lbpools/"+base+"/"+provisionPoolID+"/member/"+provisionMemberID, s.delete("members"))
+	mux.HandleFunc("/v1/lbpools/"+base+"/"+provisionPoolID+"/healthmonitor", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			s.record(&s.deleted, "healthmonitors")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		s.create("healthmonitors", provisionHealthMonitorID)(w, r)
+	})
+	mux.HandleFunc("/v1/l7policies/"+base, s.create("l7polices", provisionL7PolicyID))
+	mux.HandleFunc("/v1/l7policies/"+base+"/"+provisionL7PolicyID, s.delete("l7polices"))
+	mux.HandleFunc("/v1/l7policies/"+base+"/"+provisionL7PolicyID+"/rules", s.create("l7rules", provisionRuleID))
+	mux.HandleFunc("/v1/l7policies/"+base+"/"+provisionL7PolicyID+"/rules/"+provisionRuleID, s.delete("l7rules"))
+	mux.HandleFunc("/v1/tasks/", s.task)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := func(name string) *gcorecloud.ServiceClient {
+		c, err := gcore.TokenClientService(gcorecloud.TokenOptions{
+			APIURL:       server.URL + "/",
+			AccessToken:  fake.AccessToken,
+			RefreshToken: fake.RefreshToken,
+		}, gcorecloud.EndpointOpts{Name: name, Region: fake.RegionID, Project: fake.ProjectID, Version: "v1"})
+		require.NoError(t, err)
+		return c
+	}
+	return s, loadbalancers.ProvisionClients{
+		LoadBalancers: client("loadbalancers"),
+		Listeners:     client("listeners"),
+		Pools:         client("lbpools"),
+		L7Policies:    client("l7policies"),
+	}
+}
+
+func (s *provisionServer) record(list *[]string, resource string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*list = append(*list, resource)
+}
+
+func (s *provisionServer) respondTask(w http.ResponseWriter, status int, taskID, createdResources string) {
+	s.mu.Lock()
+	s.tasks[taskID] = createdResources
+	s.mu.Unlock()
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, `{"tasks": ["%s"]}`, taskID)
+}
+
+func (s *provisionServer) create(resource, id string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			s.t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			return
+		}
+		if resource == s.failOn {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if resource == "l7polices" {
+			s.mu.Lock()
+			require.NoError(s.t, json.NewDecoder(r.Body).Decode(&s.policy))
+			s.mu.Unlock()
+		}
+		s.record(&s.created, resource)
+		s.respondTask(w, http.StatusCreated, "create-"+resource, fmt.Sprintf(`{"%s": ["%s"]}`, resource, id))
+	}
+}
+
+func (s *provisionServer) delete(resource string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			s.t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			return
+		}
+		s.record(&s.deleted, resource)
+		s.respondTask(w, http.StatusOK, "delete-"+resource, "null")
+	}
+}
+
+func (s *provisionServer) task(w http.ResponseWriter, r *http.Request) {
+	taskID := strings.TrimPrefix(r.URL.Path, "/v1/tasks/")
+	s.mu.Lock()
+	createdResources, ok := s.tasks[taskID]
+	s.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprintf(w, `
+{
+  "id": "%s",
+  "state": "FINISHED",
+  "task_type": "%s",
+  "created_on": "2020-02-03T13:10:22",
+  "created_resources": %s,
+  "error": null
+}
+`, taskID, taskID, createdResources)
+}
+
+func provisionOpts() loadbalancers.ProvisionOpts {
+	return loadbalancers.ProvisionOpts{
+		LoadBalancer: loadbalancers.CreateOpts{Name: "test-lb"},
+		Listeners: []loadbalancers.ProvisionListenerOpts{{
+			Listener: listeners.CreateOpts{Name: "http", Protocol: types.ProtocolTypeHTTP, ProtocolPort: 80},
+			Pools: []loadbalancers.ProvisionPoolOpts{{
+				Pool: lbpools.CreateOpts{
+					Name:            "web",
+					Protocol:        types.ProtocolTypeHTTP,
+					LBPoolAlgorithm: types.LoadBalancerAlgorithmRoundRobin,
+				},
+				Members: []lbpools.CreatePoolMemberOpts{{Address: net.ParseIP("192.168.13.9"), ProtocolPort: 80}},
+				HealthMonitor: &lbpools.CreateHealthMonitorOpts{
+					Type:       types.HealthMonitorTypeTCP,
+					Delay:      10,
+					MaxRetries: 3,
+					Timeout:    5,
+				},
+			}},
+			L7Policies: []loadbalancers.ProvisionL7PolicyOpts{{
+				Policy:           l7policies.CreateOpts{Name: "api", Action: l7policies.ActionRedirectToPool},
+				RedirectPoolName: "web",
+				Rules: []l7policies.CreateRuleOpts{{
+					CompareType: l7policies.CompareTypeStartWith,
+					Type:        l7policies.TypePath,
+					Value:       "/api",
+				}},
+			}},
+		}},
+		WaitSeconds: 10,
+	}
+}
+
+func TestProvisionFull(t *testing.T) {
+	t.Parallel()
+	server, clients := newProvisionServer(t, "")
+	provisioned, err := loadbalancers.ProvisionFull(clients, provisionOpts())
+	require.NoError(t, err)
+	require.Equal(t, &loadbalancers.Provisioned{
+		LoadBalancerID: provisionLoadBalancerID,
+		Listeners: []loadbalancers.ProvisionedListener{{
+			ID:   provisionListenerID,
+			Name: "http",
+			Pools: []loadbalancers.ProvisionedPool{{
+				ID:              provisionPoolID,
+				Name:            "web",
+				MemberIDs:       []string{provisionMemberID},
+				HealthMonitorID: provisionHealthMonitorID,
+			}},
+			L7Policies: []loadbalancers.ProvisionedL7Policy{{
+				ID:      provisionL7PolicyID,
+				Name:    "api",
+				RuleIDs: []string{provisionRuleID},
+			}},
+		}},
+	}, provisioned)
+	require.Equal(t, []string{"loadbalancers", "listeners", "pools", "members", "healthmonitors", "l7polices", "l7rules"}, server.created)
+	require.Empty(t, server.deleted)
+	require.Equal(t, provisionListenerID, server.policy["listener_id"])
+	require.Equal(t, provisionPoolID, server.policy["redirect_pool_id"])
+}
+
+func TestProvisionRollback(t *testing.T) {
+	t.Parallel()
+	steps := []string{"loadbalancers", "listeners", "pools", "members", "healthmonitors", "l7polices", "l7rules"}
+	for i, failOn := range steps {
+		i, failOn := i, failOn
+		t.Run(failOn, func(t *testing.T) {
+			t.Parallel()
+			server, clients := newProvisionServer(t, failOn)
+			_, err := loadbalancers.NewBuilder(clients, provisionOpts().LoadBalancer).
+				WithListener(provisionOpts().Listeners[0]).
+				WithWaitSeconds(10).
+				Provision()
+			require.Error(t, err)
+			var rollbackErr *loadbalancers.RollbackError
+			require.True(t, errors.As(err, &rollbackErr))
+			require.Empty(t, rollbackErr.RollbackErrs)
+
+			// every created resource is deleted in reverse order
+			created := steps[:i]
+			require.Len(t, server.created, len(created))
+			require.Len(t, server.deleted, len(created))
+			for j, resource := range created {
+				require.Equal(t, resource, server.created[j])
+				require.Equal(t, resource, server.deleted[len(created)-1-j])
+			}
+		})
+	}
+}
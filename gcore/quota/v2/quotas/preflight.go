@@ -0,0 +1,200 @@
+package quotas
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/flavor/v1/flavors"
+)
+
+// InstancePlan represents instances of a flavor to create.
+// Baremetal servers count against the baremetal_<BaremetalClass>_count quota only.
+type InstancePlan struct {
+	Count          int    `json:"count" validate:"gt=0"`
+	FlavorID       string `json:"flavor_id,omitempty"`
+	VCPUS          int    `json:"vcpus" validate:"gte=0"`
+	RAM            int    `json:"ram" validate:"gte=0"`
+	GPU            int    `json:"gpu,omitempty" validate:"gte=0"`
+	Shared         bool   `json:"shared,omitempty"`
+	BaremetalClass string `json:"baremetal_class,omitempty"`
+	// VolumeSize is the size of a boot volume created with every instance.
+	VolumeSize int `json:"volume_size,omitempty" validate:"gte=0"`
+}
+
+// NewInstancePlan returns a plan of count instances of the flavor. Flavors with shared in the ID are shared flavors.
+func NewInstancePlan(flavor flavors.Flavor, count, volumeSize int) InstancePlan {
+	return InstancePlan{
+		Count:      count,
+		FlavorID:   flavor.FlavorID,
+		VCPUS:      flavor.VCPUS,
+		RAM:        flavor.RAM,
+		Shared:     strings.Contains(flavor.FlavorID, "shared"),
+		VolumeSize: volumeSize,
+	}
+}
+
+// VolumePlan represents volumes to create. Quotas are not split by volume type,
+// volumes of all types count against volume_count and volume_size.
+type VolumePlan struct {
+	Count int `json:"count" validate:"gt=0"`
+	Size  int `json:"size" validate:"gt=0"`
+}
+
+// Plan represents resources to create in a region. RegionID defaults to the client region.
+type Plan struct {
+	RegionID      int            `json:"region_id"`
+	Instances     []InstancePlan `json:"instances,omitempty" validate:"dive"`
+	Volumes       []VolumePlan   `json:"volumes,omitempty" validate:"dive"`
+	FloatingIPs   int            `json:"floating_ips,omitempty" validate:"gte=0"`
+	LoadBalancers int            `json:"loadbalancers,omitempty" validate:"gte=0"`
+}
+
+// Validate Plan
+func (p Plan) Validate() error {
+	return gcorecloud.TranslateValidationError(gcorecloud.Validate.Struct(p))
+}
+
+// Requirements returns the quota deltas of the plan by quota name without the _limit suffix.
+func (p Plan) Requirements() map[string]int {
+	required := make(map[string]int)
+	for _, i := range p.Instances {
+		if i.BaremetalClass != "" {
+			required[fmt.Sprintf("baremetal_%s_count", i.BaremetalClass)] += i.Count
+		} else {
+			if i.Shared {
+				required["shared_vm_count"] += i.Count
+			} else {
+				required["vm_count"] += i.Count
+			}
+			required["cpu_count"] += i.Count * i.VCPUS
+			required["ram"] += i.Count * i.RAM
+			required["gpu_count"] += i.Count * i.GPU
+		}
+		if i.VolumeSize > 0 {
+			required["volume_count"] += i.Count
+			required["volume_size"] += i.Count * i.VolumeSize
+		}
+	}
+	for _, v := range p.Volumes {
+		required["volume_count"] += v.Count
+		required["volume_size"] += v.Count * v.Size
+	}
+	required["floating_count"] += p.FloatingIPs
+	required["loadbalancer_count"] += p.LoadBalancers
+	for name, value := range required {
+		if value == 0 {
+			delete(required, name)
+		}
+	}
+	return required
+}
+
+// PreflightCheck represents a quota required by a plan.
+type PreflightCheck struct {
+	Name      string `json:"name"`
+	Limit     int    `json:"limit"`
+	Usage     int    `json:"usage"`
+	Requested int    `json:"requested"`
+	Exceeded  bool   `json:"exceeded"`
+}
+
+// PreflightReport represents the result of a quota check of a plan.
+// Unknown lists the required quotas the region does not report, they are not checked.
+type PreflightReport struct {
+	RegionID int              `json:"region_id"`
+	Checks   []PreflightCheck `json:"checks"`
+	Unknown  []string         `json:"unknown,omitempty"`
+}
+
+// Allowed reports whether the plan fits into the quota.
+func (r PreflightReport) Allowed() bool {
+	return len(r.Exceeded()) == 0
+}
+
+// Exceeded returns the checks of the quotas the plan would exceed.
+func (r PreflightReport) Exceeded() []PreflightCheck {
+	var result []PreflightCheck
+	for _, c := range r.Checks {
+		if c.Exceeded {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// Err returns an error listing the exceeded quotas, nil if the plan fits.
+func (r PreflightReport) Err() error {
+	exceeded := r.Exceeded()
+	if len(exceeded) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(exceeded))
+	for _, c := range exceeded {
+		messages = append(messages, fmt.Sprintf("%s: %d requested, %d of %d used", c.Name, c.Requested, c.Usage, c.Limit))
+	}
+	return fmt.Errorf("region %d quota exceeded: %s", r.RegionID, strings.Join(messages, "; "))
+}
+
+// Regional returns the regional quota of the region.
+func (q CombinedQuota) Regional(regionID int) (Quota, error) {
+	for _, rq := range q.RegionalQuotas {
+		if rq["region_id"] == regionID {
+			return rq, nil
+		}
+	}
+	return nil, fmt.Errorf("no regional quota found for region %d", regionID)
+}
+
+// CheckPlan compares the plan requirements with the regional quota. Negative limits are unlimited.
+func CheckPlan(q Quota, plan Plan) PreflightReport {
+	report := PreflightReport{RegionID: plan.RegionID}
+	required := plan.Requirements()
+	names := make([]string, 0, len(required))
+	for name := range required {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		limit, ok := q[name+"_limit"]
+		if !ok {
+			report.Unknown = append(report.Unknown, name)
+			continue
+		}
+		usage := q[name+"_usage"]
+		report.Checks = append(report.Checks, PreflightCheck{
+			Name:      name,
+			Limit:     limit,
+			Usage:     usage,
+			Requested: required[name],
+			Exceeded:  limit >= 0 && usage+required[name] > limit,
+		})
+	}
+	return report
+}
+
+// Preflight gets the combined quota of the client and checks whether the plan fits into the regional quota
+// before any resources are created.
+func Preflight(ctx context.Context, c *gcorecloud.ServiceClient, plan Plan) (*PreflightReport, error) {
+	if err := plan.Validate(); err != nil {
+		return nil, err
+	}
+	if plan.RegionID == 0 {
+		plan.RegionID = c.RegionID
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	combined, err := ListCombined(c, nil).Extract()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get quota: %w", err)
+	}
+	q, err := combined.Regional(plan.RegionID)
+	if err != nil {
+		return nil, err
+	}
+	report := CheckPlan(q, plan)
+	return &report, nil
+}
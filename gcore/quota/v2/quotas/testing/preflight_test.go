@@ -0,0 +1,90 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/flavor/v1/flavors"
+	"github.com/G-Core/gcorelabscloud-go/gcore/quota/v2/quotas"
+	th "github.com/G-Core/gcorelabscloud-go/testhelper"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanRequirements(t *testing.T) {
+	plan := quotas.Plan{
+		Instances: []quotas.InstancePlan{
+			quotas.NewInstancePlan(flavors.Flavor{FlavorID: "g1-standard-2-4", VCPUS: 2, RAM: 4096}, 3, 10),
+			quotas.NewInstancePlan(flavors.Flavor{FlavorID: "g1s-shared-1-0.5", VCPUS: 1, RAM: 512}, 1, 0),
+			{Count: 1, BaremetalClass: "hf", VolumeSize: 20},
+		},
+		Volumes:     []quotas.VolumePlan{{Count: 2, Size: 50}},
+		FloatingIPs: 2,
+	}
+	require.Equal(t, map[string]int{
+		"vm_count":           3,
+		"shared_vm_count":    1,
+		"baremetal_hf_count": 1,
+		"cpu_count":          7,
+		"ram":                12800,
+		"volume_count":       6,
+		"volume_size":        150,
+		"floating_count":     2,
+	}, plan.Requirements())
+
+	require.Error(t, quotas.Plan{Volumes: []quotas.VolumePlan{{Count: 1}}}.Validate())
+}
+
+func TestPreflight(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc(prepareListCombinedTestURL(), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, CombinedResponse)
+	})
+	client := fake.ServiceTokenClient("quotas", "v2")
+
+	shared := quotas.NewInstancePlan(flavors.Flavor{FlavorID: "g1s-shared-1-0.5", VCPUS: 1, RAM: 512}, 2, 10)
+	report, err := quotas.Preflight(context.Background(), client, quotas.Plan{Instances: []quotas.InstancePlan{shared}})
+	require.NoError(t, err)
+	require.True(t, report.Allowed())
+	require.NoError(t, report.Err())
+	require.Equal(t, 1, report.RegionID)
+	require.Len(t, report.Checks, 5)
+
+	report, err = quotas.Preflight(context.Background(), client, quotas.Plan{
+		Instances:     []quotas.InstancePlan{{Count: 1, VCPUS: 4, RAM: 2048}},
+		Volumes:       []quotas.VolumePlan{{Count: 1, Size: 25}},
+		LoadBalancers: 1,
+	})
+	require.NoError(t, err)
+	require.False(t, report.Allowed())
+	require.Equal(t, []quotas.PreflightCheck{
+		{Name: "cpu_count", Limit: 2, Usage: 0, Requested: 4, Exceeded: true},
+		{Name: "loadbalancer_count", Limit: 0, Usage: 0, Requested: 1, Exceeded: true},
+		{Name: "vm_count", Limit: 0, Usage: 0, Requested: 1, Exceeded: true},
+	}, report.Exceeded())
+	require.Error(t, report.Err())
+
+	_, err = quotas.Preflight(context.Background(), client, quotas.Plan{RegionID: 2, FloatingIPs: 1})
+	require.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = quotas.Preflight(ctx, client, quotas.Plan{FloatingIPs: 1})
+	require.Error(t, err)
+}
+
+func TestCheckPlanUnknown(t *testing.T) {
+	report := quotas.CheckPlan(quotas.Quota{"vm_count_limit": -1, "vm_count_usage": 100}, quotas.Plan{
+		Instances: []quotas.InstancePlan{{Count: 1, VCPUS: 1, RAM: 1024}},
+	})
+	require.True(t, report.Allowed())
+	require.Equal(t, []string{"cpu_count", "ram"}, report.Unknown)
+}
@@ -0,0 +1,63 @@
+package testing
+
+import (
+	"testing"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/quota/v2/quotas"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUtilization(t *testing.T) {
+	q := quotas.Quota{
+		"region_id":         1,
+		"cpu_count_limit":   8,
+		"cpu_count_usage":   7,
+		"ram_limit":         4096,
+		"ram_usage":         1024,
+		"vm_count_limit":    -1,
+		"vm_count_usage":    50,
+		"gpu_count_limit":   0,
+		"gpu_count_usage":   0,
+		"image_count_limit": 3,
+	}
+	require.Equal(t, 1, q.RegionID())
+	require.Equal(t, []quotas.Utilization{
+		{Name: "cpu_count", Limit: 8, Usage: 7, Percent: 87.5},
+		{Name: "gpu_count", Limit: 0, Usage: 0, Percent: 0},
+		{Name: "image_count", Limit: 3, Usage: 0, Percent: 0},
+		{Name: "ram", Limit: 4096, Usage: 1024, Percent: 25},
+		{Name: "vm_count", Limit: -1, Usage: 50, Percent: 0},
+	}, q.Utilization())
+
+	require.Equal(t, 33.33, quotas.NewUtilization("a", 3, 1).Percent)
+	require.Equal(t, float64(100), quotas.NewUtilization("a", 0, 1).Percent)
+	require.Equal(t, 1, quotas.NewUtilization("a", 8, 7).Available())
+	require.Equal(t, 0, quotas.NewUtilization("a", 8, 9).Available())
+	require.Equal(t, -1, quotas.NewUtilization("a", -1, 9).Available())
+}
+
+func TestFindings(t *testing.T) {
+	combined := quotas.CombinedQuota{
+		GlobalQuotas: quotas.Quota{"project_count_limit": 2, "project_count_usage": 2},
+		RegionalQuotas: []quotas.Quota{
+			{"region_id": 8, "cpu_count_limit": 10, "cpu_count_usage": 8, "ram_limit": 100, "ram_usage": 10},
+			{"region_id": 1, "cpu_count_limit": 10, "cpu_count_usage": 9},
+		},
+	}
+	utilization := combined.Utilization()
+	require.Len(t, utilization.Global, 1)
+	require.Equal(t, 1, utilization.Regional[0].RegionID)
+	require.Equal(t, 8, utilization.Regional[1].RegionID)
+
+	thresholds := quotas.Thresholds{Warning: 80, Critical: 95}
+	require.NoError(t, thresholds.Validate())
+	require.Equal(t, []quotas.Finding{
+		{Severity: quotas.SeverityCritical, Utilization: quotas.Utilization{Name: "project_count", Limit: 2, Usage: 2, Percent: 100}},
+		{RegionID: 1, Severity: quotas.SeverityWarning, Utilization: quotas.Utilization{Name: "cpu_count", Limit: 10, Usage: 9, Percent: 90}},
+		{RegionID: 8, Severity: quotas.SeverityWarning, Utilization: quotas.Utilization{Name: "cpu_count", Limit: 10, Usage: 8, Percent: 80}},
+	}, utilization.Findings(thresholds))
+
+	require.Empty(t, quotas.CombinedQuota{}.Utilization().Findings(thresholds))
+	require.Error(t, quotas.Thresholds{Warning: 90, Critical: 80}.Validate())
+	require.Error(t, quotas.Thresholds{Warning: 0, Critical: 80}.Validate())
+}
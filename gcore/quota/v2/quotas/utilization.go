@@ -0,0 +1,163 @@
+package quotas
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+)
+
+const (
+	limitSuffix = "_limit"
+	usageSuffix = "_usage"
+)
+
+// Severity represents how close a quota is to its limit.
+type Severity string
+
+const (
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Utilization represents a quota limit paired with its usage. Percent is the usage in percent of the limit.
+// Negative limits are unlimited and have zero Percent.
+type Utilization struct {
+	Name    string  `json:"name"`
+	Limit   int     `json:"limit"`
+	Usage   int     `json:"usage"`
+	Percent float64 `json:"percent"`
+}
+
+// Unlimited reports whether the quota has no limit.
+func (u Utilization) Unlimited() bool {
+	return u.Limit < 0
+}
+
+// Available returns the remaining quota, -1 if the quota is unlimited.
+func (u Utilization) Available() int {
+	switch {
+	case u.Unlimited():
+		return -1
+	case u.Usage >= u.Limit:
+		return 0
+	}
+	return u.Limit - u.Usage
+}
+
+// NewUtilization pairs a limit and a usage. A used quota with zero limit is fully utilized.
+func NewUtilization(name string, limit, usage int) Utilization {
+	u := Utilization{Name: name, Limit: limit, Usage: usage}
+	switch {
+	case limit < 0:
+	case limit == 0:
+		if usage > 0 {
+			u.Percent = 100
+		}
+	default:
+		u.Percent = math.Round(float64(usage)*10000/float64(limit)) / 100
+	}
+	return u
+}
+
+// RegionID returns the region of a regional quota, zero for a global quota.
+func (q Quota) RegionID() int {
+	return q["region_id"]
+}
+
+// Utilization pairs the _limit and _usage keys of the quota, sorted by name.
+func (q Quota) Utilization() []Utilization {
+	result := make([]Utilization, 0, len(q)/2)
+	for key, limit := range q {
+		if !strings.HasSuffix(key, limitSuffix) {
+			continue
+		}
+		name := strings.TrimSuffix(key, limitSuffix)
+		result = append(result, NewUtilization(name, limit, q[name+usageSuffix]))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// RegionalUtilization represents the utilization of the quotas of a region.
+type RegionalUtilization struct {
+	RegionID int           `json:"region_id"`
+	Quotas   []Utilization `json:"quotas"`
+}
+
+// CombinedUtilization represents the utilization of the global and regional quotas.
+type CombinedUtilization struct {
+	Global   []Utilization         `json:"global"`
+	Regional []RegionalUtilization `json:"regional"`
+}
+
+// Utilization returns the utilization of the global and regional quotas, regions sorted by ID.
+func (q CombinedQuota) Utilization() CombinedUtilization {
+	result := CombinedUtilization{
+		Global:   q.GlobalQuotas.Utilization(),
+		Regional: make([]RegionalUtilization, 0, len(q.RegionalQuotas)),
+	}
+	for _, rq := range q.RegionalQuotas {
+		result.Regional = append(result.Regional, RegionalUtilization{RegionID: rq.RegionID(), Quotas: rq.Utilization()})
+	}
+	sort.Slice(result.Regional, func(i, j int) bool { return result.Regional[i].RegionID < result.Regional[j].RegionID })
+	return result
+}
+
+// Thresholds represents the utilization percents a quota is reported at.
+type Thresholds struct {
+	Warning  float64 `json:"warning" validate:"gt=0,lte=100"`
+	Critical float64 `json:"critical" validate:"gtefield=Warning,lte=100"`
+}
+
+// Validate Thresholds
+func (t Thresholds) Validate() error {
+	return gcorecloud.TranslateValidationError(gcorecloud.Validate.Struct(t))
+}
+
+// Severity returns the severity of the quota utilization, empty if it is below the thresholds.
+func (t Thresholds) Severity(u Utilization) Severity {
+	switch {
+	case u.Unlimited() || u.Percent == 0:
+		return ""
+	case u.Percent >= t.Critical:
+		return SeverityCritical
+	case u.Percent >= t.Warning:
+		return SeverityWarning
+	}
+	return ""
+}
+
+// Finding represents a quota utilized above a threshold. RegionID is zero for global quotas.
+type Finding struct {
+	RegionID int      `json:"region_id,omitempty"`
+	Severity Severity `json:"severity"`
+	Utilization
+}
+
+// Findings returns the quotas utilized above the thresholds, the most utilized first.
+func (u CombinedUtilization) Findings(t Thresholds) []Finding {
+	var result []Finding
+	add := func(regionID int, quotas []Utilization) {
+		for _, q := range quotas {
+			if severity := t.Severity(q); severity != "" {
+				result = append(result, Finding{RegionID: regionID, Severity: severity, Utilization: q})
+			}
+		}
+	}
+	add(0, u.Global)
+	for _, r := range u.Regional {
+		add(r.RegionID, r.Quotas)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Percent != result[j].Percent {
+			return result[i].Percent > result[j].Percent
+		}
+		if result[i].RegionID != result[j].RegionID {
+			return result[i].RegionID < result[j].RegionID
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
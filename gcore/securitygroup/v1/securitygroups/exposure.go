@@ -0,0 +1,201 @@
+package securitygroups
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/types"
+)
+
+// Exposure represents an ingress rule opening an instance port to a source.
+type Exposure struct {
+	PortID            string          `json:"port_id"`
+	PortName          string          `json:"port_name"`
+	SecurityGroupID   string          `json:"security_group_id"`
+	SecurityGroupName string          `json:"security_group_name"`
+	RuleID            string          `json:"rule_id"`
+	EtherType         types.EtherType `json:"ethertype"`
+	Protocol          types.Protocol  `json:"protocol"`
+	Ports             string          `json:"ports"`
+	Source            string          `json:"source"`
+	Public            bool            `json:"public"`
+	RemoteGroupID     string          `json:"remote_group_id,omitempty"`
+	RemoteGroupIPs    []net.IP        `json:"remote_group_ips,omitempty"`
+	Description       string          `json:"description,omitempty"`
+}
+
+// ExposureReport represents the effective ingress exposure of an instance.
+type ExposureReport struct {
+	InstanceID     string                  `json:"instance_id"`
+	SecurityGroups []gcorecloud.ItemIDName `json:"security_groups"`
+	Exposures      []Exposure              `json:"exposures"`
+}
+
+// Public returns exposures open to any address.
+func (r ExposureReport) Public() []Exposure {
+	var result []Exposure
+	for _, e := range r.Exposures {
+		if e.Public {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func portsString(rule CreateSecurityGroupRuleOpts) string {
+	switch {
+	case rule.PortRangeMin == nil:
+		return "all"
+	case rule.PortRangeMax == nil || *rule.PortRangeMin == *rule.PortRangeMax:
+		return fmt.Sprint(*rule.PortRangeMin)
+	default:
+		return fmt.Sprintf("%d-%d", *rule.PortRangeMin, *rule.PortRangeMax)
+	}
+}
+
+// Exposures returns ingress exposures of the instance ports. Groups are looked up by ID,
+// remote group members are looked up in members by the remote group ID.
+func Exposures(ports []instances.InstancePorts, groups map[string]SecurityGroup, members map[string][]net.IP) ([]Exposure, error) {
+	var result []Exposure
+	for _, port := range ports {
+		for _, ref := range port.SecurityGroups {
+			sg, ok := groups[ref.ID]
+			if !ok {
+				return nil, fmt.Errorf("security group %s of port %s is not found", ref.ID, port.ID)
+			}
+			for _, r := range sg.SecurityGroupRules {
+				if r.Direction != types.RuleDirectionIngress {
+					continue
+				}
+				rule, err := NormaliseRule(RuleToOpts(r))
+				if err != nil {
+					return nil, fmt.Errorf("invalid security group %s rule %s: %w", sg.ID, r.ID, err)
+				}
+				e := Exposure{
+					PortID:            port.ID,
+					PortName:          port.Name,
+					SecurityGroupID:   sg.ID,
+					SecurityGroupName: sg.Name,
+					RuleID:            r.ID,
+					EtherType:         rule.EtherType,
+					Protocol:          rule.Protocol,
+					Ports:             portsString(rule),
+				}
+				if rule.Description != nil {
+					e.Description = *rule.Description
+				}
+				switch {
+				case rule.RemoteGroupID != nil:
+					e.RemoteGroupID = *rule.RemoteGroupID
+					e.RemoteGroupIPs = members[e.RemoteGroupID]
+					e.Source = "group:" + e.RemoteGroupID
+					if remote, ok := groups[e.RemoteGroupID]; ok {
+						e.Source = "group:" + remote.Name
+					}
+				case rule.RemoteIPPrefix != nil:
+					e.Source = *rule.RemoteIPPrefix
+				default:
+					e.Public = true
+					e.Source = "0.0.0.0/0"
+					if rule.EtherType == types.EtherTypeIPv6 {
+						e.Source = "::/0"
+					}
+				}
+				result = append(result, e)
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Public != result[j].Public {
+			return result[i].Public
+		}
+		return result[i].PortID < result[j].PortID
+	})
+	return result, nil
+}
+
+func instanceIPs(instance instances.Instance) []net.IP {
+	var result []net.IP
+	names := make([]string, 0, len(instance.Addresses))
+	for name := range instance.Addresses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, addr := range instance.Addresses[name] {
+			result = append(result, addr.Address)
+		}
+	}
+	return result
+}
+
+// AnalyzeExposure collects the instance ports and security groups, resolves remote group references
+// into member addresses and reports the effective ingress exposure of the instance.
+func AnalyzeExposure(instanceClient, securityGroupClient *gcorecloud.ServiceClient, instanceID string) (*ExposureReport, error) {
+	ports, err := instances.ListPortsAll(instanceClient, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list instance %s ports: %w", instanceID, err)
+	}
+	instanceGroups, err := instances.ListSecurityGroupsAll(instanceClient, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list instance %s security groups: %w", instanceID, err)
+	}
+
+	groups := make(map[string]SecurityGroup)
+	getGroup := func(id string) (SecurityGroup, error) {
+		if sg, ok := groups[id]; ok {
+			return sg, nil
+		}
+		sg, err := Get(securityGroupClient, id).Extract()
+		if err != nil {
+			return SecurityGroup{}, fmt.Errorf("cannot get security group %s: %w", id, err)
+		}
+		groups[id] = *sg
+		return *sg, nil
+	}
+	for _, port := range ports {
+		for _, ref := range port.SecurityGroups {
+			if _, err := getGroup(ref.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	members := make(map[string][]net.IP)
+	ids := make([]string, 0, len(groups))
+	for id := range groups {
+		ids = append(ids, id)
+	}
+	for _, id := range ids {
+		for _, r := range groups[id].SecurityGroupRules {
+			if r.Direction != types.RuleDirectionIngress || r.RemoteGroupID == nil {
+				continue
+			}
+			remoteID := *r.RemoteGroupID
+			if _, ok := members[remoteID]; ok {
+				continue
+			}
+			if _, err := getGroup(remoteID); err != nil {
+				return nil, err
+			}
+			remoteInstances, err := ListAllInstances(securityGroupClient, remoteID)
+			if err != nil {
+				return nil, fmt.Errorf("cannot list security group %s instances: %w", remoteID, err)
+			}
+			ips := []net.IP{}
+			for _, instance := range remoteInstances {
+				ips = append(ips, instanceIPs(instance)...)
+			}
+			members[remoteID] = ips
+		}
+	}
+
+	exposures, err := Exposures(ports, groups, members)
+	if err != nil {
+		return nil, err
+	}
+	return &ExposureReport{InstanceID: instanceID, SecurityGroups: instanceGroups, Exposures: exposures}, nil
+}
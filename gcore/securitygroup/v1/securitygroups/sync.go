@@ -0,0 +1,258 @@
+package securitygroups
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/types"
+)
+
+const (
+	minPort = 1
+	maxPort = 65535
+)
+
+// RulesDiff represents the rules to add to and remove from a security group.
+type RulesDiff struct {
+	Add    []CreateSecurityGroupRuleOpts `json:"add"`
+	Remove []SecurityGroupRule           `json:"remove"`
+}
+
+// IsEmpty checks whether the security group rules are already in the desired state.
+func (d RulesDiff) IsEmpty() bool {
+	return len(d.Add) == 0 && len(d.Remove) == 0
+}
+
+// ToUpdateOpts converts the diff to a security group update changing all the rules in one request.
+func (d RulesDiff) ToUpdateOpts() UpdateOpts {
+	var opts UpdateOpts
+	for _, r := range d.Remove {
+		opts.ChangedRules = append(opts.ChangedRules, UpdateSecurityGroupRuleOpts{
+			Action:              types.ActionDelete,
+			SecurityGroupRuleID: r.ID,
+		})
+	}
+	for _, r := range d.Add {
+		opts.ChangedRules = append(opts.ChangedRules, UpdateSecurityGroupRuleOpts{
+			Action:         types.ActionCreate,
+			Direction:      r.Direction,
+			EtherType:      r.EtherType,
+			Protocol:       r.Protocol,
+			RemoteGroupID:  r.RemoteGroupID,
+			PortRangeMax:   r.PortRangeMax,
+			PortRangeMin:   r.PortRangeMin,
+			Description:    r.Description,
+			RemoteIPPrefix: r.RemoteIPPrefix,
+		})
+	}
+	return opts
+}
+
+func hasPorts(p types.Protocol) bool {
+	switch p {
+	case types.ProtocolTCP, types.ProtocolUDP, types.ProtocolSCTP, types.ProtocolUDPLITE, types.ProtocolDCCP:
+		return true
+	}
+	return false
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+// NormaliseRule returns the canonical form of the rule so that equivalent rules compare equal:
+// empty and "0" protocols are "any", a single port bound is expanded to a range, the full port range is dropped,
+// a remote IP prefix is reduced to its network address and any-address prefixes are dropped,
+// ethertype is inferred from the remote IP prefix and defaults to IPv4.
+func NormaliseRule(opts CreateSecurityGroupRuleOpts) (CreateSecurityGroupRuleOpts, error) {
+	if err := opts.Direction.IsValid(); err != nil {
+		return opts, err
+	}
+	rule := CreateSecurityGroupRuleOpts{
+		Direction:     opts.Direction,
+		EtherType:     opts.EtherType,
+		Protocol:      opts.Protocol,
+		RemoteGroupID: opts.RemoteGroupID,
+		Description:   opts.Description,
+	}
+	if rule.Protocol == "" || rule.Protocol == types.Protocol0 {
+		rule.Protocol = types.ProtocolAny
+	}
+	if err := rule.Protocol.IsValid(); err != nil {
+		return opts, err
+	}
+
+	if opts.PortRangeMin != nil || opts.PortRangeMax != nil {
+		if rule.Protocol == types.ProtocolAny {
+			return opts, fmt.Errorf("port range requires a protocol")
+		}
+		rule.PortRangeMin, rule.PortRangeMax = opts.PortRangeMin, opts.PortRangeMax
+		if hasPorts(rule.Protocol) {
+			if rule.PortRangeMin == nil {
+				rule.PortRangeMin = rule.PortRangeMax
+			}
+			if rule.PortRangeMax == nil {
+				rule.PortRangeMax = rule.PortRangeMin
+			}
+			low, high := *rule.PortRangeMin, *rule.PortRangeMax
+			if low < minPort || high > maxPort || low > high {
+				return opts, fmt.Errorf("invalid port range %d-%d", low, high)
+			}
+			if low == minPort && high == maxPort {
+				rule.PortRangeMin, rule.PortRangeMax = nil, nil
+			} else {
+				rule.PortRangeMin, rule.PortRangeMax = intPtr(low), intPtr(high)
+			}
+		}
+	}
+
+	if opts.RemoteIPPrefix != nil && *opts.RemoteIPPrefix != "" {
+		if opts.RemoteGroupID != nil {
+			return opts, fmt.Errorf("remote IP prefix and remote group can not be set together")
+		}
+		prefix := strings.TrimSpace(*opts.RemoteIPPrefix)
+		etherType := types.EtherTypeIPv4
+		if !strings.Contains(prefix, "/") {
+			ip := net.ParseIP(prefix)
+			if ip == nil {
+				return opts, fmt.Errorf("invalid remote IP prefix %s", prefix)
+			}
+			if ip.To4() != nil {
+				prefix += "/32"
+			} else {
+				prefix += "/128"
+			}
+		}
+		ip, network, err := net.ParseCIDR(prefix)
+		if err != nil {
+			return opts, fmt.Errorf("invalid remote IP prefix %s", prefix)
+		}
+		if ip.To4() == nil {
+			etherType = types.EtherTypeIPv6
+		}
+		if rule.EtherType != "" && rule.EtherType != etherType {
+			return opts, fmt.Errorf("remote IP prefix %s does not match ethertype %s", prefix, rule.EtherType)
+		}
+		rule.EtherType = etherType
+		if ones, _ := network.Mask.Size(); ones > 0 {
+			canonical := network.String()
+			rule.RemoteIPPrefix = &canonical
+		}
+	}
+	if rule.EtherType == "" {
+		rule.EtherType = types.EtherTypeIPv4
+	}
+	return rule, rule.EtherType.IsValid()
+}
+
+// RuleToOpts converts an existing security group rule to the options reproducing it.
+func RuleToOpts(r SecurityGroupRule) CreateSecurityGroupRuleOpts {
+	opts := CreateSecurityGroupRuleOpts{
+		Direction:      r.Direction,
+		RemoteGroupID:  r.RemoteGroupID,
+		PortRangeMax:   r.PortRangeMax,
+		PortRangeMin:   r.PortRangeMin,
+		Description:    r.Description,
+		RemoteIPPrefix: r.RemoteIPPrefix,
+	}
+	if r.EtherType != nil {
+		opts.EtherType = *r.EtherType
+	}
+	if r.Protocol != nil {
+		opts.Protocol = *r.Protocol
+	}
+	return opts
+}
+
+// RuleKey identifies a normalised rule. Descriptions are not part of the key.
+func RuleKey(opts CreateSecurityGroupRuleOpts) string {
+	value := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	port := func(p *int) string {
+		if p == nil {
+			return ""
+		}
+		return fmt.Sprint(*p)
+	}
+	return strings.Join([]string{
+		opts.Direction.String(),
+		opts.EtherType.String(),
+		opts.Protocol.String(),
+		port(opts.PortRangeMin) + "-" + port(opts.PortRangeMax),
+		value(opts.RemoteIPPrefix),
+		value(opts.RemoteGroupID),
+	}, "|")
+}
+
+// DiffRules compares normalised current and desired rules. Duplicate desired rules are merged,
+// duplicate current rules are removed.
+func DiffRules(current []SecurityGroupRule, desired []CreateSecurityGroupRuleOpts) (*RulesDiff, error) {
+	wanted := make(map[string]bool, len(desired))
+	var diff RulesDiff
+	var order []CreateSecurityGroupRuleOpts
+	for _, opts := range desired {
+		rule, err := NormaliseRule(opts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule %s: %w", RuleKey(opts), err)
+		}
+		key := RuleKey(rule)
+		if wanted[key] {
+			continue
+		}
+		wanted[key] = true
+		order = append(order, rule)
+	}
+
+	present := make(map[string]bool, len(current))
+	for _, r := range current {
+		rule, err := NormaliseRule(RuleToOpts(r))
+		if err != nil {
+			return nil, fmt.Errorf("invalid security group rule %s: %w", r.ID, err)
+		}
+		key := RuleKey(rule)
+		if !wanted[key] || present[key] {
+			diff.Remove = append(diff.Remove, r)
+			continue
+		}
+		present[key] = true
+	}
+	for _, rule := range order {
+		if !present[RuleKey(rule)] {
+			diff.Add = append(diff.Add, rule)
+		}
+	}
+	return &diff, nil
+}
+
+// SyncRules brings the security group rules to the desired state with a single update request.
+// Rules are compared after normalisation, see NormaliseRule.
+func SyncRules(ctx context.Context, c *gcorecloud.ServiceClient, securityGroupID string, desired []CreateSecurityGroupRuleOpts) (*RulesDiff, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sg, err := Get(c, securityGroupID).Extract()
+	if err != nil {
+		return nil, err
+	}
+	diff, err := DiffRules(sg.SecurityGroupRules, desired)
+	if err != nil {
+		return nil, err
+	}
+	if diff.IsEmpty() {
+		return diff, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return diff, err
+	}
+	if _, err := Update(c, securityGroupID, diff.ToUpdateOpts()).Extract(); err != nil {
+		return diff, fmt.Errorf("cannot update security group %s rules: %w", securityGroupID, err)
+	}
+	return diff, nil
+}
@@ -0,0 +1,62 @@
+package testing
+
+import (
+	"net"
+	"testing"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/securitygroups"
+	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExposures(t *testing.T) {
+	ingress, egress := types.RuleDirectionIngress, types.RuleDirectionEgress
+	tcp := types.ProtocolTCP
+	bastionID := "0b7e5b3c-6a4b-4c2a-9d6f-1f2e3d4c5b6a"
+	web := securitygroups.SecurityGroup{
+		ID:   groupID,
+		Name: "web",
+		SecurityGroupRules: []securitygroups.SecurityGroupRule{
+			{ID: "rule-ssh", Direction: ingress, Protocol: &tcp, PortRangeMin: portPtr(22), PortRangeMax: portPtr(22), RemoteGroupID: &bastionID},
+			{ID: "rule-egress", Direction: egress},
+			{ID: "rule-https", Direction: ingress, Protocol: &tcp, PortRangeMin: portPtr(443), PortRangeMax: portPtr(443), RemoteIPPrefix: strPtr("0.0.0.0/0")},
+			{ID: "rule-internal", Direction: ingress, RemoteIPPrefix: strPtr("10.0.0.0/8"), Description: strPtr("internal")},
+		},
+	}
+	groups := map[string]securitygroups.SecurityGroup{
+		groupID:   web,
+		bastionID: {ID: bastionID, Name: "bastion"},
+	}
+	ports := []instances.InstancePorts{
+		{ID: "port-1", Name: "eth0", SecurityGroups: []gcorecloud.ItemIDName{{ID: groupID, Name: "web"}}},
+	}
+	members := map[string][]net.IP{bastionID: {net.ParseIP("10.0.0.5")}}
+
+	exposures, err := securitygroups.Exposures(ports, groups, members)
+	require.NoError(t, err)
+	require.Len(t, exposures, 3)
+
+	require.Equal(t, "rule-https", exposures[0].RuleID)
+	require.True(t, exposures[0].Public)
+	require.Equal(t, "443", exposures[0].Ports)
+	require.Equal(t, "0.0.0.0/0", exposures[0].Source)
+
+	require.Equal(t, "rule-ssh", exposures[1].RuleID)
+	require.False(t, exposures[1].Public)
+	require.Equal(t, "group:bastion", exposures[1].Source)
+	require.Equal(t, []net.IP{net.ParseIP("10.0.0.5")}, exposures[1].RemoteGroupIPs)
+
+	require.Equal(t, "all", exposures[2].Ports)
+	require.Equal(t, types.ProtocolAny, exposures[2].Protocol)
+	require.Equal(t, "10.0.0.0/8", exposures[2].Source)
+	require.Equal(t, "internal", exposures[2].Description)
+
+	report := securitygroups.ExposureReport{Exposures: exposures}
+	require.Len(t, report.Public(), 1)
+
+	_, err = securitygroups.Exposures(ports, map[string]securitygroups.SecurityGroup{}, nil)
+	require.Error(t, err)
+}
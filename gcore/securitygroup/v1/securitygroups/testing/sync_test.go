@@ -0,0 +1,141 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/securitygroups"
+	"github.com/G-Core/gcorelabscloud-go/gcore/securitygroup/v1/types"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+
+	"github.com/stretchr/testify/require"
+
+	th "github.com/G-Core/gcorelabscloud-go/testhelper"
+)
+
+const SyncRulesRequest = `
+{
+  "changed_rules": [
+    {
+      "action": "delete",
+      "security_group_rule_id": "253c1ad7-8061-44b9-9f33-5616ad8ba5b6"
+    },
+    {
+      "action": "create",
+      "direction": "ingress",
+      "ethertype": "IPv4",
+      "protocol": "tcp",
+      "port_range_min": 22,
+      "port_range_max": 22,
+      "remote_ip_prefix": "10.0.0.0/8"
+    }
+  ]
+}
+`
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func portPtr(p int) *int {
+	return &p
+}
+
+func TestNormaliseRule(t *testing.T) {
+	rule, err := securitygroups.NormaliseRule(securitygroups.CreateSecurityGroupRuleOpts{
+		Direction:      types.RuleDirectionIngress,
+		Protocol:       types.ProtocolTCP,
+		PortRangeMin:   portPtr(443),
+		RemoteIPPrefix: strPtr("192.168.1.17/24"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, types.EtherTypeIPv4, rule.EtherType)
+	require.Equal(t, 443, *rule.PortRangeMax)
+	require.Equal(t, "192.168.1.0/24", *rule.RemoteIPPrefix)
+
+	rule, err = securitygroups.NormaliseRule(securitygroups.CreateSecurityGroupRuleOpts{
+		Direction:      types.RuleDirectionEgress,
+		Protocol:       types.ProtocolUDP,
+		PortRangeMin:   portPtr(1),
+		PortRangeMax:   portPtr(65535),
+		RemoteIPPrefix: strPtr("::/0"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, types.EtherTypeIPv6, rule.EtherType)
+	require.Nil(t, rule.PortRangeMin)
+	require.Nil(t, rule.PortRangeMax)
+	require.Nil(t, rule.RemoteIPPrefix)
+
+	rule, err = securitygroups.NormaliseRule(securitygroups.CreateSecurityGroupRuleOpts{
+		Direction:      types.RuleDirectionIngress,
+		RemoteIPPrefix: strPtr("2001:db8::1"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, types.ProtocolAny, rule.Protocol)
+	require.Equal(t, "2001:db8::1/128", *rule.RemoteIPPrefix)
+
+	invalid := []securitygroups.CreateSecurityGroupRuleOpts{
+		{Direction: "inbound"},
+		{Direction: types.RuleDirectionIngress, PortRangeMin: portPtr(22)},
+		{Direction: types.RuleDirectionIngress, Protocol: types.ProtocolTCP, PortRangeMin: portPtr(80), PortRangeMax: portPtr(22)},
+		{Direction: types.RuleDirectionIngress, EtherType: types.EtherTypeIPv6, RemoteIPPrefix: strPtr("10.0.0.0/8")},
+		{Direction: types.RuleDirectionIngress, RemoteIPPrefix: strPtr("10.0.0.0/33")},
+	}
+	for _, opts := range invalid {
+		_, err := securitygroups.NormaliseRule(opts)
+		require.Error(t, err)
+	}
+}
+
+func TestDiffRules(t *testing.T) {
+	desired := []securitygroups.CreateSecurityGroupRuleOpts{
+		{Direction: types.RuleDirectionEgress, Protocol: types.ProtocolAny},
+		{Direction: types.RuleDirectionEgress, Protocol: types.Protocol50, Description: strPtr("esp")},
+		{Direction: types.RuleDirectionEgress, RemoteIPPrefix: strPtr("0.0.0.0/0")},
+	}
+	diff, err := securitygroups.DiffRules(SecurityGroup1.SecurityGroupRules, desired)
+	require.NoError(t, err)
+	require.True(t, diff.IsEmpty())
+
+	diff, err = securitygroups.DiffRules(SecurityGroup1.SecurityGroupRules, desired[1:2])
+	require.NoError(t, err)
+	require.Len(t, diff.Add, 0)
+	require.Equal(t, SecurityGroup1.SecurityGroupRules[:1], diff.Remove)
+}
+
+func TestSyncRules(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc(prepareGetTestURL(groupID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+		w.Header().Add("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+		case "PATCH":
+			th.TestJSONRequest(t, r, SyncRulesRequest)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, GetResponse)
+	})
+
+	desired := []securitygroups.CreateSecurityGroupRuleOpts{
+		{Direction: types.RuleDirectionEgress, Protocol: types.Protocol50},
+		{Direction: types.RuleDirectionIngress, Protocol: types.ProtocolTCP, PortRangeMin: portPtr(22), RemoteIPPrefix: strPtr("10.1.2.3/8")},
+	}
+	client := fake.ServiceTokenClient("securitygroups", "v1")
+	diff, err := securitygroups.SyncRules(context.Background(), client, groupID, desired)
+	require.NoError(t, err)
+	require.Len(t, diff.Add, 1)
+	require.Len(t, diff.Remove, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = securitygroups.SyncRules(ctx, client, groupID, desired)
+	require.True(t, errors.Is(err, context.Canceled))
+}
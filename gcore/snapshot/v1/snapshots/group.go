@@ -0,0 +1,418 @@
+package snapshots
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/instance/v1/instances"
+	"github.com/G-Core/gcorelabscloud-go/gcore/task/v1/tasks"
+	"github.com/G-Core/gcorelabscloud-go/gcore/volume/v1/volumes"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Metadata keys shared by all snapshots of a group.
+const (
+	GroupIDMetadataKey         = "snapshot_group_id"
+	GroupTimeMetadataKey       = "snapshot_group_time"
+	GroupInstanceIDMetadataKey = "snapshot_group_instance_id"
+)
+
+// GroupWaitSeconds is the default time to wait for every group snapshot task and instance state change.
+const GroupWaitSeconds = 1200
+
+// Quiesce defines how an instance is quiesced while its volumes are snapshotted.
+type Quiesce string
+
+const (
+	// QuiesceNone snapshots volumes of a running instance, the snapshots are crash consistent.
+	QuiesceNone Quiesce = ""
+	// QuiesceStop stops the instance and starts it after the snapshots are taken.
+	QuiesceStop Quiesce = "stop"
+	// QuiesceSuspend suspends the instance and resumes it after the snapshots are taken.
+	QuiesceSuspend Quiesce = "suspend"
+)
+
+// IsValid checks the quiesce mode.
+func (q Quiesce) IsValid() error {
+	switch q {
+	case QuiesceNone, QuiesceStop, QuiesceSuspend:
+		return nil
+	}
+	return fmt.Errorf("invalid Quiesce type: %v", q)
+}
+
+// GroupClients represents the service clients used to snapshot instance volumes.
+type GroupClients struct {
+	Instances *gcorecloud.ServiceClient
+	Volumes   *gcorecloud.ServiceClient
+	Snapshots *gcorecloud.ServiceClient
+}
+
+// CreateGroupOpts represents options used to snapshot all volumes of an instance.
+type CreateGroupOpts struct {
+	// Name prefixes the snapshot names, which are suffixed with the volume name. Defaults to the group ID.
+	Name        string
+	Description string
+	// Metadata is added to every snapshot along with the group metadata.
+	Metadata    map[string]string
+	Quiesce     Quiesce
+	WaitSeconds int
+}
+
+// GroupSnapshot represents a snapshot of a group and the volume it was taken from.
+type GroupSnapshot struct {
+	SnapshotID string             `json:"snapshot_id"`
+	VolumeID   string             `json:"volume_id"`
+	VolumeName string             `json:"volume_name"`
+	VolumeType volumes.VolumeType `json:"volume_type"`
+	Size       int                `json:"size"`
+	Bootable   bool               `json:"bootable"`
+	Device     string             `json:"device,omitempty"`
+}
+
+// GroupManifest describes the snapshots taken together from the volumes of an instance.
+type GroupManifest struct {
+	GroupID    string          `json:"group_id"`
+	InstanceID string          `json:"instance_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Quiesce    Quiesce         `json:"quiesce,omitempty"`
+	Snapshots  []GroupSnapshot `json:"snapshots"`
+}
+
+// InstanceRestoreError is returned when an instance cannot be started or resumed after it was quiesced.
+// CreateGroup returns it along with the manifest when all snapshots were taken.
+type InstanceRestoreError struct {
+	InstanceID string
+	Err        error
+}
+
+func (e *InstanceRestoreError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *InstanceRestoreError) Unwrap() error {
+	return e.Err
+}
+
+func waitForInstanceState(ctx context.Context, c *gcorecloud.ServiceClient, instanceID string, secs int, states ...string) error {
+	return gcorecloud.WaitFor(secs, func() (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		instance, err := instances.Get(c, instanceID).Extract()
+		if err != nil {
+			return false, err
+		}
+		for _, state := range states {
+			if strings.EqualFold(instance.VMState, state) {
+				return true, nil
+			}
+		}
+		if strings.EqualFold(instance.VMState, "error") {
+			return false, fmt.Errorf("instance %s is in error state", instanceID)
+		}
+		return false, nil
+	})
+}
+
+// quiesce stops or suspends the instance and returns a function bringing it back. The function is returned
+// along with the error if the instance was asked to stop or suspend but did not reach the state.
+func quiesce(ctx context.Context, c *gcorecloud.ServiceClient, instanceID string, mode Quiesce, secs int) (func() error, error) {
+	var (
+		err   error
+		state string
+	)
+	switch mode {
+	case QuiesceNone:
+		return func() error { return nil }, nil
+	case QuiesceStop:
+		_, err = instances.Stop(c, instanceID).Extract()
+		state = "stopped"
+	case QuiesceSuspend:
+		_, err = instances.Suspend(c, instanceID).Extract()
+		state = "suspended"
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot %s instance %s: %w", mode, instanceID, err)
+	}
+	restore := func() error {
+		// the instance is brought back even if the context is cancelled
+		ctx := context.Background()
+		var err error
+		if mode == QuiesceStop {
+			_, err = instances.Start(c, instanceID).Extract()
+		} else {
+			_, err = instances.Resume(c, instanceID).Extract()
+		}
+		if err == nil {
+			err = waitForInstanceState(ctx, c, instanceID, secs, "active")
+		}
+		if err != nil {
+			return &InstanceRestoreError{
+				InstanceID: instanceID,
+				Err:        fmt.Errorf("cannot bring instance %s back after %s: %w", instanceID, mode, err),
+			}
+		}
+		return nil
+	}
+	if err := waitForInstanceState(ctx, c, instanceID, secs, state); err != nil {
+		return restore, fmt.Errorf("cannot %s instance %s: %w", mode, instanceID, err)
+	}
+	return restore, nil
+}
+
+func attachmentDevice(volume volumes.Volume, instanceID string) string {
+	for _, a := range volume.Attachments {
+		if a.ServerID == instanceID {
+			return a.Device
+		}
+	}
+	return ""
+}
+
+// CreateGroup snapshots all volumes attached to an instance at once. The instance is optionally stopped or suspended
+// while the snapshots are taken. Every snapshot carries the group ID, the group time and the instance ID in its
+// metadata. Snapshots of a failed group are deleted. If the snapshots are taken but the instance cannot be brought
+// back, the manifest is returned with an *InstanceRestoreError.
+func CreateGroup(ctx context.Context, clients GroupClients, instanceID string, opts CreateGroupOpts) (_ *GroupManifest, err error) {
+	if clients.Instances == nil || clients.Volumes == nil || clients.Snapshots == nil {
+		return nil, fmt.Errorf("instances, volumes and snapshots clients are required")
+	}
+	if err := opts.Quiesce.IsValid(); err != nil {
+		return nil, err
+	}
+	if opts.WaitSeconds == 0 {
+		opts.WaitSeconds = GroupWaitSeconds
+	}
+
+	attached, err := volumes.ListAll(clients.Volumes, volumes.ListOpts{InstanceID: &instanceID})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list instance %s volumes: %w", instanceID, err)
+	}
+	if len(attached) == 0 {
+		return nil, fmt.Errorf("instance %s has no volumes", instanceID)
+	}
+	sort.SliceStable(attached, func(i, j int) bool {
+		return attachmentDevice(attached[i], instanceID) < attachmentDevice(attached[j], instanceID)
+	})
+
+	manifest := &GroupManifest{
+		GroupID:    uuid.NewV4().String(),
+		InstanceID: instanceID,
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+		Quiesce:    opts.Quiesce,
+	}
+	metadata := map[string]string{
+		GroupIDMetadataKey:         manifest.GroupID,
+		GroupTimeMetadataKey:       manifest.CreatedAt.Format(time.RFC3339),
+		GroupInstanceIDMetadataKey: instanceID,
+	}
+	for k, v := range opts.Metadata {
+		if _, ok := metadata[k]; !ok {
+			metadata[k] = v
+		}
+	}
+	name := opts.Name
+	if name == "" {
+		name = manifest.GroupID
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	restore, err := quiesce(ctx, clients.Instances, instanceID, opts.Quiesce, opts.WaitSeconds)
+	// the instance is brought back once, before the snapshots of a failed group are deleted or on any early return
+	restored := false
+	bringBack := func() error {
+		if restore == nil || restored {
+			return nil
+		}
+		restored = true
+		return restore()
+	}
+	defer func() {
+		if restoreErr := bringBack(); restoreErr != nil {
+			err = errors.Join(err, restoreErr)
+		}
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.Snapshots = make([]GroupSnapshot, len(attached))
+	errs := make([]error, len(attached))
+	var wg sync.WaitGroup
+	for idx, volume := range attached {
+		manifest.Snapshots[idx] = GroupSnapshot{
+			VolumeID:   volume.ID,
+			VolumeName: volume.Name,
+			VolumeType: volume.VolumeType,
+			Size:       volume.Size,
+			Bootable:   volume.Bootable,
+			Device:     attachmentDevice(volume, instanceID),
+		}
+		wg.Add(1)
+		go func(idx int, volume volumes.Volume) {
+			defer wg.Done()
+			snapshotID, err := createGroupSnapshot(ctx, clients.Snapshots, CreateOpts{
+				VolumeID:    volume.ID,
+				Name:        fmt.Sprintf("%s-%s", name, volume.Name),
+				Description: opts.Description,
+				Metadata:    metadata,
+			}, opts.WaitSeconds)
+			manifest.Snapshots[idx].SnapshotID = snapshotID
+			if err != nil {
+				errs[idx] = fmt.Errorf("cannot snapshot volume %s: %w", volume.ID, err)
+			}
+		}(idx, volume)
+	}
+	wg.Wait()
+
+	restoreErr := bringBack()
+	// only snapshot errors fail the group, the snapshots are still consistent if the instance cannot be brought back
+	if err := errors.Join(errs...); err != nil {
+		for _, s := range manifest.Snapshots {
+			if s.SnapshotID == "" {
+				continue
+			}
+			if _, delErr := waitResult(clients.Snapshots, Delete(clients.Snapshots, s.SnapshotID), opts.WaitSeconds); delErr != nil {
+				err = errors.Join(err, fmt.Errorf("cannot delete snapshot %s: %w", s.SnapshotID, delErr))
+			}
+		}
+		return nil, errors.Join(err, restoreErr)
+	}
+	return manifest, restoreErr
+}
+
+func waitResult(c *gcorecloud.ServiceClient, r tasks.Result, secs int) (*tasks.Task, error) {
+	results, err := r.Extract()
+	if err != nil {
+		return nil, err
+	}
+	return tasks.WaitForTaskResults(c, results, secs)
+}
+
+func createGroupSnapshot(ctx context.Context, c *gcorecloud.ServiceClient, opts CreateOpts, secs int) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	task, err := waitResult(c, Create(c, opts), secs)
+	if err != nil {
+		return "", err
+	}
+	return ExtractSnapshotIDFromTask(task)
+}
+
+// RestoreMode defines how group snapshots are restored.
+type RestoreMode string
+
+const (
+	// RestoreRevert reverts the volumes to the group snapshots, which must be their latest snapshots.
+	RestoreRevert RestoreMode = "revert"
+	// RestoreNewVolumes creates new volumes from the group snapshots.
+	RestoreNewVolumes RestoreMode = "new"
+)
+
+// RestoreGroupOpts represents options used to restore group snapshots.
+type RestoreGroupOpts struct {
+	Mode RestoreMode
+	// NamePrefix prefixes names of new volumes. Defaults to "restored-".
+	NamePrefix  string
+	WaitSeconds int
+}
+
+// RestoredVolume maps a snapshotted volume to the volume restored from its snapshot.
+// VolumeID equals SourceVolumeID when the volume was reverted.
+type RestoredVolume struct {
+	SourceVolumeID string `json:"source_volume_id"`
+	SnapshotID     string `json:"snapshot_id"`
+	VolumeID       string `json:"volume_id"`
+	Device         string `json:"device,omitempty"`
+}
+
+// checkLatest checks that the snapshot is the latest snapshot of its volume, which is required to revert the volume.
+func checkLatest(c *gcorecloud.ServiceClient, s GroupSnapshot) error {
+	all, err := ListAll(c, ListOpts{VolumeID: s.VolumeID})
+	if err != nil {
+		return fmt.Errorf("cannot list volume %s snapshots: %w", s.VolumeID, err)
+	}
+	var latest *Snapshot
+	for idx := range all {
+		if latest == nil || all[idx].CreatedAt.After(latest.CreatedAt.Time) {
+			latest = &all[idx]
+		}
+	}
+	if latest == nil || latest.ID != s.SnapshotID {
+		return fmt.Errorf("snapshot %s is not the latest snapshot of volume %s", s.SnapshotID, s.VolumeID)
+	}
+	return nil
+}
+
+// RestoreGroup restores the volumes of a group manifest, either reverting the volumes in place or creating
+// new volumes of the same type and size from the snapshots.
+func RestoreGroup(ctx context.Context, clients GroupClients, manifest GroupManifest, opts RestoreGroupOpts) ([]RestoredVolume, error) {
+	if clients.Volumes == nil || clients.Snapshots == nil {
+		return nil, fmt.Errorf("volumes and snapshots clients are required")
+	}
+	if opts.WaitSeconds == 0 {
+		opts.WaitSeconds = GroupWaitSeconds
+	}
+	if opts.NamePrefix == "" {
+		opts.NamePrefix = "restored-"
+	}
+	switch opts.Mode {
+	case RestoreRevert:
+		for _, s := range manifest.Snapshots {
+			if err := checkLatest(clients.Snapshots, s); err != nil {
+				return nil, err
+			}
+		}
+	case RestoreNewVolumes:
+	default:
+		return nil, fmt.Errorf("invalid RestoreMode type: %v", opts.Mode)
+	}
+
+	result := make([]RestoredVolume, len(manifest.Snapshots))
+	errs := make([]error, len(manifest.Snapshots))
+	var wg sync.WaitGroup
+	for idx, s := range manifest.Snapshots {
+		result[idx] = RestoredVolume{SourceVolumeID: s.VolumeID, SnapshotID: s.SnapshotID, Device: s.Device}
+		wg.Add(1)
+		go func(idx int, s GroupSnapshot) {
+			defer wg.Done()
+			if err := ctx.Err(); err != nil {
+				errs[idx] = err
+				return
+			}
+			if opts.Mode == RestoreRevert {
+				if _, err := waitResult(clients.Volumes, volumes.Revert(clients.Volumes, s.VolumeID), opts.WaitSeconds); err != nil {
+					errs[idx] = fmt.Errorf("cannot revert volume %s: %w", s.VolumeID, err)
+					return
+				}
+				result[idx].VolumeID = s.VolumeID
+				return
+			}
+			task, err := waitResult(clients.Volumes, volumes.Create(clients.Volumes, volumes.CreateOpts{
+				Source:     volumes.Snapshot,
+				Name:       opts.NamePrefix + s.VolumeName,
+				Size:       s.Size,
+				TypeName:   s.VolumeType,
+				SnapshotID: s.SnapshotID,
+			}), opts.WaitSeconds)
+			if err == nil {
+				result[idx].VolumeID, err = volumes.ExtractVolumeIDFromTask(task)
+			}
+			if err != nil {
+				errs[idx] = fmt.Errorf("cannot create volume from snapshot %s: %w", s.SnapshotID, err)
+			}
+		}(idx, s)
+	}
+	wg.Wait()
+	return result, errors.Join(errs...)
+}
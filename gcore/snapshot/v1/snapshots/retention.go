@@ -0,0 +1,210 @@
+package snapshots
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+)
+
+// PruneWaitSeconds is the default time to wait for every snapshot deletion.
+const PruneWaitSeconds = 600
+
+// RetentionPolicy represents a GFS-style retention. Last keeps the newest snapshots, Hourly, Daily, Weekly and
+// Monthly keep the newest snapshot of as many distinct hours, days, ISO weeks and months.
+type RetentionPolicy struct {
+	Last    int `json:"last"`
+	Hourly  int `json:"hourly"`
+	Daily   int `json:"daily"`
+	Weekly  int `json:"weekly"`
+	Monthly int `json:"monthly"`
+}
+
+// Validate checks that the policy keeps at least one snapshot of every group.
+func (p RetentionPolicy) Validate() error {
+	if p.Last < 0 || p.Hourly < 0 || p.Daily < 0 || p.Weekly < 0 || p.Monthly < 0 {
+		return fmt.Errorf("retention counts should not be negative")
+	}
+	if p.Last+p.Hourly+p.Daily+p.Weekly+p.Monthly == 0 {
+		return fmt.Errorf("retention policy would delete all snapshots")
+	}
+	return nil
+}
+
+type retentionBucket struct {
+	name  string
+	count int
+	key   func(t time.Time) string
+}
+
+func (p RetentionPolicy) buckets() []retentionBucket {
+	return []retentionBucket{
+		{"last", p.Last, func(t time.Time) string { return t.Format(time.RFC3339Nano) }},
+		{"hourly", p.Hourly, func(t time.Time) string { return t.Format("2006-01-02T15") }},
+		{"daily", p.Daily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{"weekly", p.Weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{"monthly", p.Monthly, func(t time.Time) string { return t.Format("2006-01") }},
+	}
+}
+
+// PruneDecision represents whether a snapshot is kept and which retention rules keep it.
+type PruneDecision struct {
+	SnapshotID string    `json:"snapshot_id"`
+	Name       string    `json:"name"`
+	VolumeID   string    `json:"volume_id"`
+	Group      string    `json:"group"`
+	CreatedAt  time.Time `json:"created_at"`
+	Keep       bool      `json:"keep"`
+	Reasons    []string  `json:"reasons,omitempty"`
+}
+
+// SnapshotGroupKey identifies the retention group of a snapshot: its volume and the values of the metadata keys.
+func SnapshotGroupKey(s Snapshot, groupBy []string) string {
+	parts := []string{s.VolumeID}
+	for _, key := range groupBy {
+		parts = append(parts, key+"="+s.Metadata[key])
+	}
+	return strings.Join(parts, ",")
+}
+
+// ApplyRetention decides which snapshots the policy keeps. Snapshots are grouped by volume and the metadata keys,
+// the policy is applied to every group separately in UTC. Decisions are ordered by group and newest first.
+func ApplyRetention(all []Snapshot, policy RetentionPolicy, groupBy []string) []PruneDecision {
+	groups := make(map[string][]Snapshot)
+	var keys []string
+	for _, s := range all {
+		key := SnapshotGroupKey(s, groupBy)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], s)
+	}
+	sort.Strings(keys)
+
+	var result []PruneDecision
+	for _, key := range keys {
+		group := groups[key]
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].CreatedAt.After(group[j].CreatedAt.Time)
+		})
+		buckets := policy.buckets()
+		kept := make([]int, len(buckets))
+		last := make([]string, len(buckets))
+		for _, s := range group {
+			created := s.CreatedAt.UTC()
+			decision := PruneDecision{
+				SnapshotID: s.ID,
+				Name:       s.Name,
+				VolumeID:   s.VolumeID,
+				Group:      key,
+				CreatedAt:  created,
+			}
+			for idx, b := range buckets {
+				if kept[idx] >= b.count {
+					continue
+				}
+				if bucketKey := b.key(created); bucketKey != last[idx] {
+					last[idx] = bucketKey
+					kept[idx]++
+					decision.Keep = true
+					decision.Reasons = append(decision.Reasons, b.name)
+				}
+			}
+			result = append(result, decision)
+		}
+	}
+	return result
+}
+
+// PruneOpts represents options used to prune snapshots.
+type PruneOpts struct {
+	Policy RetentionPolicy
+	// VolumeID limits pruning to snapshots of the volume.
+	VolumeID string
+	// Selector limits pruning to snapshots with all the metadata.
+	Selector map[string]string
+	// GroupBy splits snapshots of a volume into retention groups by the metadata values.
+	GroupBy     []string
+	DryRun      bool
+	WaitSeconds int
+}
+
+// Validate PruneOpts. Pruning has to be limited to a volume or a selector, so that snapshots taken
+// by lifecycle policies or other tools are not deleted by a policy they were not made for.
+func (opts PruneOpts) Validate() error {
+	if err := opts.Policy.Validate(); err != nil {
+		return err
+	}
+	if opts.VolumeID == "" && len(opts.Selector) == 0 {
+		return fmt.Errorf("volume ID or selector is required to limit the snapshots to prune")
+	}
+	return nil
+}
+
+// PruneReport represents the snapshots kept and deleted by Prune. Deleted is empty for a dry run.
+type PruneReport struct {
+	DryRun    bool            `json:"dry_run"`
+	Decisions []PruneDecision `json:"decisions"`
+	Deleted   []string        `json:"deleted"`
+}
+
+// ToDelete returns the decisions of the snapshots the policy does not keep.
+func (r PruneReport) ToDelete() []PruneDecision {
+	var result []PruneDecision
+	for _, d := range r.Decisions {
+		if !d.Keep {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+func matchesSelector(s Snapshot, selector map[string]string) bool {
+	for k, v := range selector {
+		if value, ok := s.Metadata[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Prune lists available snapshots, applies the retention policy and deletes the snapshots it does not keep.
+// Snapshots in other statuses are neither counted nor deleted.
+func Prune(c *gcorecloud.ServiceClient, opts PruneOpts) (*PruneReport, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.WaitSeconds == 0 {
+		opts.WaitSeconds = PruneWaitSeconds
+	}
+	all, err := ListAll(c, ListOpts{VolumeID: opts.VolumeID})
+	if err != nil {
+		return nil, err
+	}
+	var candidates []Snapshot
+	for _, s := range all {
+		if s.Status == "available" && matchesSelector(s, opts.Selector) {
+			candidates = append(candidates, s)
+		}
+	}
+
+	report := &PruneReport{DryRun: opts.DryRun, Decisions: ApplyRetention(candidates, opts.Policy, opts.GroupBy), Deleted: []string{}}
+	if opts.DryRun {
+		return report, nil
+	}
+	var errs []error
+	for _, d := range report.ToDelete() {
+		if _, err := waitResult(c, Delete(c, d.SnapshotID), opts.WaitSeconds); err != nil {
+			errs = append(errs, fmt.Errorf("cannot delete snapshot %s: %w", d.SnapshotID, err))
+			continue
+		}
+		report.Deleted = append(report.Deleted, d.SnapshotID)
+	}
+	return report, errors.Join(errs...)
+}
@@ -0,0 +1,279 @@
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/G-Core/gcorelabscloud-go/gcore/snapshot/v1/snapshots"
+	th "github.com/G-Core/gcorelabscloud-go/testhelper"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+)
+
+const groupInstanceID = "8dc30d49-bb34-4920-9bbd-03a2587ec0ad"
+
+const GroupVolumesResponse = `
+{
+  "count": 2,
+  "results": [
+    {
+      "id": "b1a2c3d4-0000-4000-8000-000000000002",
+      "name": "data",
+      "volume_type": "ssd_hiiops",
+      "size": 50,
+      "bootable": false,
+      "attachments": [{"server_id": "8dc30d49-bb34-4920-9bbd-03a2587ec0ad", "device": "/dev/vdb"}]
+    },
+    {
+      "id": "b1a2c3d4-0000-4000-8000-000000000001",
+      "name": "root",
+      "volume_type": "standard",
+      "size": 10,
+      "bootable": true,
+      "attachments": [{"server_id": "8dc30d49-bb34-4920-9bbd-03a2587ec0ad", "device": "/dev/vda"}]
+    }
+  ]
+}
+`
+
+func groupTaskResponse(taskID, key, resourceID string) string {
+	return fmt.Sprintf(`
+{
+  "id": "%s",
+  "state": "FINISHED",
+  "task_type": "create_snapshot",
+  "client_id": 1,
+  "user_id": 1,
+  "user_client_id": 1,
+  "created_on": "2020-09-14T14:45:30",
+  "created_resources": {"%s": ["%s"]},
+  "error": null
+}
+`, taskID, key, resourceID)
+}
+
+type groupServer struct {
+	t       *testing.T
+	mu      sync.Mutex
+	calls   map[string]int
+	vmState string
+	// stopState is the state the instance reaches after stop
+	stopState string
+	// startFails makes starting the instance fail
+	startFails bool
+	requests   []map[string]interface{}
+	tasks      map[string]string
+}
+
+func (s *groupServer) respond(w http.ResponseWriter, body string) {
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprint(w, body)
+}
+
+func (s *groupServer) handle(path string, handler func(w http.ResponseWriter, r *http.Request)) {
+	th.Mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		th.TestHeader(s.t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.calls[r.Method+" "+path]++
+		handler(w, r)
+	})
+}
+
+// task registers a finished task creating the resource and responds with its ID.
+func (s *groupServer) task(w http.ResponseWriter, key, resourceID string) {
+	taskID := fmt.Sprintf("50f53a35-42ed-40c4-82b2-%012d", len(s.tasks)+1)
+	s.tasks[taskID] = groupTaskResponse(taskID, key, resourceID)
+	s.respond(w, fmt.Sprintf(`{"tasks": ["%s"]}`, taskID))
+}
+
+func setupGroup(t *testing.T) *groupServer {
+	s := &groupServer{t: t, calls: make(map[string]int), vmState: "active", stopState: "stopped", tasks: make(map[string]string)}
+	instanceURL := fmt.Sprintf("/v1/instances/%d/%d/%s", fake.ProjectID, fake.RegionID, groupInstanceID)
+	s.handle(instanceURL, func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		s.respond(w, fmt.Sprintf(`{"instance_id": "%s", "status": "ACTIVE", "vm_state": "%s"}`, groupInstanceID, s.vmState))
+	})
+	s.handle(instanceURL+"/stop", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		s.vmState = s.stopState
+		s.respond(w, fmt.Sprintf(`{"instance_id": "%s", "vm_state": "active"}`, groupInstanceID))
+	})
+	s.handle(instanceURL+"/start", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		require.Equal(t, s.stopState, s.vmState)
+		if s.startFails {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.vmState = "active"
+		s.respond(w, fmt.Sprintf(`{"instance_id": "%s", "vm_state": "stopped"}`, groupInstanceID))
+	})
+	s.handle(fmt.Sprintf("/v1/volumes/%d/%d", fake.ProjectID, fake.RegionID), func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			require.Equal(t, groupInstanceID, r.URL.Query().Get("instance_id"))
+			s.respond(w, GroupVolumesResponse)
+			return
+		}
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		s.requests = append(s.requests, body)
+		s.task(w, "volumes", fmt.Sprintf("c0ffee00-0000-4000-8000-%012d", len(s.requests)))
+	})
+	s.handle(prepareListTestURL(), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		require.Equal(t, "stopped", s.vmState)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		s.requests = append(s.requests, body)
+		volumeID := body["volume_id"].(string)
+		s.task(w, "snapshots", "5a000000-0000-4000-8000-"+volumeID[len(volumeID)-12:])
+	})
+	th.Mux.HandleFunc("/v1/tasks/", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		taskID := r.URL.Path[len("/v1/tasks/"):]
+		body, ok := s.tasks[taskID]
+		require.True(t, ok, taskID)
+		s.respond(w, body)
+	})
+	return s
+}
+
+func groupClients() snapshots.GroupClients {
+	return snapshots.GroupClients{
+		Instances: fake.ServiceTokenClient("instances", "v1"),
+		Volumes:   fake.ServiceTokenClient("volumes", "v1"),
+		Snapshots: fake.ServiceTokenClient("snapshots", "v1"),
+	}
+}
+
+func TestCreateGroup(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	s := setupGroup(t)
+
+	manifest, err := snapshots.CreateGroup(context.Background(), groupClients(), groupInstanceID, snapshots.CreateGroupOpts{
+		Name:        "nightly",
+		Metadata:    map[string]string{"owner": "dba"},
+		Quiesce:     snapshots.QuiesceStop,
+		WaitSeconds: 10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "active", s.vmState)
+	require.Equal(t, groupInstanceID, manifest.InstanceID)
+	require.Len(t, manifest.Snapshots, 2)
+	require.Equal(t, snapshots.GroupSnapshot{
+		SnapshotID: "5a000000-0000-4000-8000-000000000001",
+		VolumeID:   "b1a2c3d4-0000-4000-8000-000000000001",
+		VolumeName: "root",
+		VolumeType: "standard",
+		Size:       10,
+		Bootable:   true,
+		Device:     "/dev/vda",
+	}, manifest.Snapshots[0])
+	require.Equal(t, "5a000000-0000-4000-8000-000000000002", manifest.Snapshots[1].SnapshotID)
+
+	require.Len(t, s.requests, 2)
+	for _, body := range s.requests {
+		metadata := body["metadata"].(map[string]interface{})
+		require.Equal(t, manifest.GroupID, metadata[snapshots.GroupIDMetadataKey])
+		require.Equal(t, manifest.CreatedAt.Format("2006-01-02T15:04:05Z07:00"), metadata[snapshots.GroupTimeMetadataKey])
+		require.Equal(t, groupInstanceID, metadata[snapshots.GroupInstanceIDMetadataKey])
+		require.Equal(t, "dba", metadata["owner"])
+	}
+	instanceURL := fmt.Sprintf("/v1/instances/%d/%d/%s", fake.ProjectID, fake.RegionID, groupInstanceID)
+	require.Equal(t, 1, s.calls["POST "+instanceURL+"/stop"])
+	require.Equal(t, 1, s.calls["POST "+instanceURL+"/start"])
+
+	_, err = snapshots.CreateGroup(context.Background(), groupClients(), groupInstanceID, snapshots.CreateGroupOpts{Quiesce: "freeze"})
+	require.Error(t, err)
+}
+
+func TestCreateGroupStartsInstanceOnQuiesceFailure(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	s := setupGroup(t)
+	s.stopState = "stopping"
+
+	_, err := snapshots.CreateGroup(context.Background(), groupClients(), groupInstanceID, snapshots.CreateGroupOpts{
+		Quiesce:     snapshots.QuiesceStop,
+		WaitSeconds: 1,
+	})
+	require.Error(t, err)
+	require.Equal(t, "active", s.vmState)
+	instanceURL := fmt.Sprintf("/v1/instances/%d/%d/%s", fake.ProjectID, fake.RegionID, groupInstanceID)
+	require.Equal(t, 1, s.calls["POST "+instanceURL+"/start"])
+	require.Len(t, s.requests, 0)
+}
+
+func TestCreateGroupKeepsSnapshotsOnStartFailure(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	s := setupGroup(t)
+	s.startFails = true
+	th.Mux.HandleFunc(prepareListTestURL()+"/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+	})
+
+	manifest, err := snapshots.CreateGroup(context.Background(), groupClients(), groupInstanceID, snapshots.CreateGroupOpts{
+		Quiesce:     snapshots.QuiesceStop,
+		WaitSeconds: 10,
+	})
+	var restoreErr *snapshots.InstanceRestoreError
+	require.True(t, errors.As(err, &restoreErr))
+	require.Equal(t, groupInstanceID, restoreErr.InstanceID)
+	require.NotNil(t, manifest)
+	require.Len(t, manifest.Snapshots, 2)
+	require.Equal(t, "5a000000-0000-4000-8000-000000000001", manifest.Snapshots[0].SnapshotID)
+	require.Equal(t, "5a000000-0000-4000-8000-000000000002", manifest.Snapshots[1].SnapshotID)
+	instanceURL := fmt.Sprintf("/v1/instances/%d/%d/%s", fake.ProjectID, fake.RegionID, groupInstanceID)
+	require.Equal(t, 1, s.calls["POST "+instanceURL+"/start"])
+}
+
+func TestRestoreGroup(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+	s := setupGroup(t)
+
+	manifest := snapshots.GroupManifest{
+		GroupID:    "9f8e7d6c-0000-4000-8000-000000000000",
+		InstanceID: groupInstanceID,
+		Snapshots: []snapshots.GroupSnapshot{{
+			SnapshotID: "5a000000-0000-4000-8000-000000000001",
+			VolumeID:   "b1a2c3d4-0000-4000-8000-000000000001",
+			VolumeName: "root",
+			VolumeType: "standard",
+			Size:       10,
+			Device:     "/dev/vda",
+		}},
+	}
+	restored, err := snapshots.RestoreGroup(context.Background(), groupClients(), manifest, snapshots.RestoreGroupOpts{
+		Mode:        snapshots.RestoreNewVolumes,
+		WaitSeconds: 10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []snapshots.RestoredVolume{{
+		SourceVolumeID: "b1a2c3d4-0000-4000-8000-000000000001",
+		SnapshotID:     "5a000000-0000-4000-8000-000000000001",
+		VolumeID:       "c0ffee00-0000-4000-8000-000000000001",
+		Device:         "/dev/vda",
+	}}, restored)
+	require.Equal(t, map[string]interface{}{
+		"source":      "snapshot",
+		"name":        "restored-root",
+		"size":        float64(10),
+		"type_name":   "standard",
+		"snapshot_id": "5a000000-0000-4000-8000-000000000001",
+	}, s.requests[0])
+
+	_, err = snapshots.RestoreGroup(context.Background(), groupClients(), manifest, snapshots.RestoreGroupOpts{Mode: "copy"})
+	require.Error(t, err)
+}
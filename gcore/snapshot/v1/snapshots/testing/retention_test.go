@@ -0,0 +1,181 @@
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/snapshot/v1/snapshots"
+	th "github.com/G-Core/gcorelabscloud-go/testhelper"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+)
+
+func retentionSnapshot(id, volumeID, created string, metadata map[string]string) snapshots.Snapshot {
+	t, err := time.Parse(time.RFC3339, created)
+	if err != nil {
+		panic(err)
+	}
+	return snapshots.Snapshot{
+		ID:        id,
+		VolumeID:  volumeID,
+		Status:    "available",
+		CreatedAt: gcorecloud.JSONRFC3339Z{Time: t},
+		Metadata:  metadata,
+	}
+}
+
+func keptIDs(decisions []snapshots.PruneDecision) []string {
+	var result []string
+	for _, d := range decisions {
+		if d.Keep {
+			result = append(result, d.SnapshotID)
+		}
+	}
+	return result
+}
+
+func TestApplyRetention(t *testing.T) {
+	all := []snapshots.Snapshot{
+		retentionSnapshot("d1-a", "vol", "2020-03-02T10:00:00Z", nil),
+		retentionSnapshot("d1-b", "vol", "2020-03-02T22:00:00Z", nil),
+		retentionSnapshot("d2", "vol", "2020-03-01T22:00:00Z", nil),
+		retentionSnapshot("d3", "vol", "2020-02-29T22:00:00Z", nil),
+		retentionSnapshot("d4", "vol", "2020-02-23T22:00:00Z", nil),
+		retentionSnapshot("d5", "vol", "2020-01-10T22:00:00Z", nil),
+	}
+
+	decisions := snapshots.ApplyRetention(all, snapshots.RetentionPolicy{Daily: 2, Weekly: 2, Monthly: 3}, nil)
+	require.Len(t, decisions, len(all))
+	require.Equal(t, "d1-b", decisions[0].SnapshotID)
+	require.Equal(t, []string{"daily", "weekly", "monthly"}, decisions[0].Reasons)
+	require.Equal(t, []string{"d1-b", "d2", "d3", "d5"}, keptIDs(decisions))
+	require.Equal(t, []string{"daily", "weekly"}, decisions[2].Reasons)
+	require.Equal(t, []string{"monthly"}, decisions[3].Reasons)
+
+	decisions = snapshots.ApplyRetention(all, snapshots.RetentionPolicy{Last: 1, Hourly: 2}, nil)
+	require.Equal(t, []string{"d1-b", "d1-a"}, keptIDs(decisions))
+}
+
+func TestApplyRetentionGroups(t *testing.T) {
+	all := []snapshots.Snapshot{
+		retentionSnapshot("a1", "vol-a", "2020-03-02T10:00:00Z", map[string]string{"schedule": "daily"}),
+		retentionSnapshot("a2", "vol-a", "2020-03-02T11:00:00Z", map[string]string{"schedule": "manual"}),
+		retentionSnapshot("a3", "vol-a", "2020-03-01T11:00:00Z", map[string]string{"schedule": "manual"}),
+		retentionSnapshot("b1", "vol-b", "2020-03-01T10:00:00Z", nil),
+	}
+
+	decisions := snapshots.ApplyRetention(all, snapshots.RetentionPolicy{Last: 1}, []string{"schedule"})
+	require.Equal(t, []string{"a1", "a2", "b1"}, keptIDs(decisions))
+	require.Equal(t, "vol-a,schedule=daily", decisions[0].Group)
+	require.Equal(t, "vol-b,schedule=", decisions[3].Group)
+
+	decisions = snapshots.ApplyRetention(all, snapshots.RetentionPolicy{Last: 1}, nil)
+	require.Equal(t, []string{"a2", "b1"}, keptIDs(decisions))
+}
+
+func TestRetentionPolicyValidate(t *testing.T) {
+	require.Error(t, snapshots.RetentionPolicy{}.Validate())
+	require.Error(t, snapshots.RetentionPolicy{Daily: 1, Weekly: -1}.Validate())
+	require.NoError(t, snapshots.RetentionPolicy{Monthly: 1}.Validate())
+}
+
+const PruneListResponse = `
+{
+  "count": 3,
+  "results": [
+    {
+      "id": "726ecfcc-7fd0-4e30-a86e-000000000001",
+      "name": "old",
+      "status": "available",
+      "created_at": "2020-03-01T05:32:41+0000",
+      "volume_id": "67baa7d1-08ea-4fc5-bef2-6b2465b7d227",
+      "metadata": {"policy": "nightly"}
+    },
+    {
+      "id": "726ecfcc-7fd0-4e30-a86e-000000000002",
+      "name": "new",
+      "status": "available",
+      "created_at": "2020-03-02T05:32:41+0000",
+      "volume_id": "67baa7d1-08ea-4fc5-bef2-6b2465b7d227",
+      "metadata": {"policy": "nightly"}
+    },
+    {
+      "id": "726ecfcc-7fd0-4e30-a86e-000000000003",
+      "name": "creating",
+      "status": "creating",
+      "created_at": "2020-02-01T05:32:41+0000",
+      "volume_id": "67baa7d1-08ea-4fc5-bef2-6b2465b7d227",
+      "metadata": {"policy": "nightly"}
+    }
+  ]
+}
+`
+
+func TestPrune(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	const (
+		taskID    = "50f53a35-42ed-40c4-82b2-5a37fb3e00bc"
+		deletedID = "726ecfcc-7fd0-4e30-a86e-000000000001"
+	)
+	var deleted []string
+
+	th.Mux.HandleFunc(prepareListTestURL(), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+		require.Equal(t, "67baa7d1-08ea-4fc5-bef2-6b2465b7d227", r.URL.Query().Get("volume_id"))
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, PruneListResponse)
+	})
+	th.Mux.HandleFunc(prepareGetTestURL(deletedID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "DELETE")
+		deleted = append(deleted, deletedID)
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, `{"tasks": ["%s"]}`, taskID)
+	})
+	th.Mux.HandleFunc("/v1/tasks/"+taskID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, groupTaskResponse(taskID, "snapshots", deletedID))
+	})
+
+	client := fake.ServiceTokenClient("snapshots", "v1")
+	opts := snapshots.PruneOpts{
+		Policy:   snapshots.RetentionPolicy{Last: 1},
+		VolumeID: "67baa7d1-08ea-4fc5-bef2-6b2465b7d227",
+		Selector: map[string]string{"policy": "nightly"},
+		DryRun:   true,
+	}
+
+	report, err := snapshots.Prune(client, opts)
+	require.NoError(t, err)
+	require.Len(t, report.Decisions, 2)
+	require.Len(t, report.ToDelete(), 1)
+	require.Equal(t, deletedID, report.ToDelete()[0].SnapshotID)
+	require.Empty(t, report.Deleted)
+	require.Empty(t, deleted)
+
+	opts.DryRun = false
+	report, err = snapshots.Prune(client, opts)
+	require.NoError(t, err)
+	require.Equal(t, []string{deletedID}, report.Deleted)
+	require.Equal(t, []string{deletedID}, deleted)
+
+	opts.Selector = map[string]string{"policy": "weekly"}
+	report, err = snapshots.Prune(client, opts)
+	require.NoError(t, err)
+	require.Empty(t, report.Decisions)
+
+	_, err = snapshots.Prune(client, snapshots.PruneOpts{})
+	require.Error(t, err)
+
+	_, err = snapshots.Prune(client, snapshots.PruneOpts{Policy: snapshots.RetentionPolicy{Last: 1}, DryRun: true})
+	require.Error(t, err)
+}
@@ -67,3 +67,17 @@ func WaitTaskAndReturnResult(
 
 type RetrieveTaskResult func(task TaskID) (interface{}, error)
 type CheckTaskResult func(task TaskID) error
+
+// WaitForTaskResults waits until every task from results is finished and returns the first of them,
+// which holds the created resources for create operations.
+func WaitForTaskResults(client *gcorecloud.ServiceClient, results *TaskResults, waitSeconds int) (*Task, error) {
+	if results == nil || len(results.Tasks) == 0 {
+		return nil, fmt.Errorf("wrong task response")
+	}
+	for _, taskID := range results.Tasks {
+		if err := WaitForStatus(client, string(taskID), TaskStateFinished, waitSeconds, true); err != nil {
+			return nil, fmt.Errorf("task %s: %w", taskID, err)
+		}
+	}
+	return Get(client, string(results.Tasks[0])).Extract()
+}
@@ -0,0 +1,267 @@
+package transfer
+
+import (
+	"errors"
+	"fmt"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore/image/v1/images"
+	imagetypes "github.com/G-Core/gcorelabscloud-go/gcore/image/v1/images/types"
+	"github.com/G-Core/gcorelabscloud-go/gcore/snapshot/v1/snapshots"
+	"github.com/G-Core/gcorelabscloud-go/gcore/task/v1/tasks"
+	"github.com/G-Core/gcorelabscloud-go/gcore/volume/v1/volumes"
+)
+
+// CopyWaitSeconds is the default time to wait for every copy task.
+const CopyWaitSeconds = 3600
+
+// CopyClients represents the service clients of a region used to copy a volume.
+// Snapshots is only used in the source region, ImageUpload only in the target region.
+type CopyClients struct {
+	Volumes     *gcorecloud.ServiceClient
+	Snapshots   *gcorecloud.ServiceClient
+	Images      *gcorecloud.ServiceClient
+	ImageUpload *gcorecloud.ServiceClient
+}
+
+// CopyOpts represents options used to copy a volume to another region.
+type CopyOpts struct {
+	// Name of the target volume. Defaults to the source volume name.
+	Name string
+	// OSType and SshKey of the intermediate images. Default to linux and allow.
+	OSType imagetypes.OSType
+	SshKey imagetypes.SshKeyType
+	// KeepImages keeps the intermediate images in both regions.
+	KeepImages  bool
+	WaitSeconds int
+	// Progress is called after every finished task.
+	Progress func(step CopyStep)
+}
+
+// CopyStep represents a finished task of a volume copy.
+type CopyStep struct {
+	Name       string `json:"name"`
+	RegionID   int    `json:"region_id"`
+	TaskID     string `json:"task_id"`
+	ResourceID string `json:"resource_id,omitempty"`
+}
+
+// CopyResult represents the volume created in the target region and the tasks executed to copy it.
+type CopyResult struct {
+	VolumeID string     `json:"volume_id"`
+	RegionID int        `json:"region_id"`
+	Steps    []CopyStep `json:"steps"`
+}
+
+// CopyError is returned by CopyToRegion when copying fails.
+// Err is the copy error and CleanupErrs hold errors occurred while deleting intermediate resources.
+type CopyError struct {
+	Err         error
+	CleanupErrs []error
+}
+
+func (e *CopyError) Error() string {
+	if len(e.CleanupErrs) == 0 {
+		return fmt.Sprintf("copy failed, intermediate resources were deleted: %s", e.Err)
+	}
+	return fmt.Sprintf("copy failed: %s. Cleanup failed: %s", e.Err, errors.Join(e.CleanupErrs...))
+}
+
+func (e *CopyError) Unwrap() error {
+	return e.Err
+}
+
+type copyCleanup struct {
+	name string
+	run  func() error
+}
+
+type copier struct {
+	source  CopyClients
+	target  CopyClients
+	opts    CopyOpts
+	steps   []CopyStep
+	cleanup []copyCleanup
+}
+
+func (c *copier) wait(client *gcorecloud.ServiceClient, name string, r tasks.Result, extract func(*tasks.Task) (string, error)) (string, error) {
+	results, err := r.Extract()
+	if err != nil {
+		return "", err
+	}
+	task, err := tasks.WaitForTaskResults(client, results, c.opts.WaitSeconds)
+	if err != nil {
+		return "", err
+	}
+	step := CopyStep{Name: name, RegionID: client.RegionID, TaskID: task.ID}
+	if extract != nil {
+		if step.ResourceID, err = extract(task); err != nil {
+			return "", err
+		}
+	}
+	c.steps = append(c.steps, step)
+	if c.opts.Progress != nil {
+		c.opts.Progress(step)
+	}
+	return step.ResourceID, nil
+}
+
+func (c *copier) addCleanup(name string, client *gcorecloud.ServiceClient, del func() tasks.Result) {
+	c.cleanup = append(c.cleanup, copyCleanup{
+		name: name,
+		run: func() error {
+			_, err := c.wait(client, "delete "+name, del(), nil)
+			var notFound gcorecloud.ErrDefault404
+			if errors.As(err, &notFound) {
+				return nil
+			}
+			return err
+		},
+	})
+}
+
+func (c *copier) doCleanup() []error {
+	var errs []error
+	for i := len(c.cleanup) - 1; i >= 0; i-- {
+		step := c.cleanup[i]
+		if err := step.run(); err != nil {
+			errs = append(errs, fmt.Errorf("cannot delete %s: %w", step.name, err))
+		}
+	}
+	c.cleanup = nil
+	return errs
+}
+
+// CopyMetadata returns the user metadata of the volume. Read-only metadata is set by the platform and is skipped.
+func CopyMetadata(volume volumes.Volume) map[string]string {
+	if len(volume.Metadata) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(volume.Metadata))
+	for _, m := range volume.Metadata {
+		if !m.ReadOnly {
+			result[m.Key] = m.Value
+		}
+	}
+	return result
+}
+
+// CopyToRegion copies a volume to another region. The volume is snapshotted, the snapshot is restored into
+// a temporary volume which is converted to an image. The image is exported and the target region uploads it
+// from the export URL, then a volume with the same type, size and metadata is created from it. Intermediate resources are deleted
+// afterwards, images are kept if opts.KeepImages is set.
+func CopyToRegion(source, target CopyClients, volumeID string, opts CopyOpts) (*CopyResult, error) {
+	if source.Volumes == nil || source.Snapshots == nil || source.Images == nil {
+		return nil, fmt.Errorf("source volumes, snapshots and images clients are required")
+	}
+	if target.Volumes == nil || target.Images == nil || target.ImageUpload == nil {
+		return nil, fmt.Errorf("target volumes, images and image upload clients are required")
+	}
+	if opts.WaitSeconds == 0 {
+		opts.WaitSeconds = CopyWaitSeconds
+	}
+	if opts.OSType == "" {
+		opts.OSType = imagetypes.OsLinux
+	}
+	if opts.SshKey == "" {
+		opts.SshKey = imagetypes.SshKeyAllow
+	}
+
+	c := &copier{source: source, target: target, opts: opts}
+	volumeID, err := c.copy(volumeID)
+	cleanupErrs := c.doCleanup()
+	if err != nil {
+		return nil, &CopyError{Err: err, CleanupErrs: cleanupErrs}
+	}
+	result := &CopyResult{VolumeID: volumeID, RegionID: target.Volumes.RegionID, Steps: c.steps}
+	if len(cleanupErrs) > 0 {
+		return result, fmt.Errorf("volume %s is copied, but cleanup failed: %w", volumeID, errors.Join(cleanupErrs...))
+	}
+	return result, nil
+}
+
+func (c *copier) copy(volumeID string) (string, error) {
+	s, t := c.source, c.target
+	volume, err := volumes.Get(s.Volumes, volumeID).Extract()
+	if err != nil {
+		return "", fmt.Errorf("cannot get volume %s: %w", volumeID, err)
+	}
+	name := c.opts.Name
+	if name == "" {
+		name = volume.Name
+	}
+	copyName := fmt.Sprintf("%s-copy-%d", volume.Name, t.Volumes.RegionID)
+
+	snapshotID, err := c.wait(s.Snapshots, "create snapshot", snapshots.Create(s.Snapshots, snapshots.CreateOpts{
+		VolumeID: volumeID,
+		Name:     copyName,
+	}), snapshots.ExtractSnapshotIDFromTask)
+	if err != nil {
+		return "", fmt.Errorf("cannot create volume %s snapshot: %w", volumeID, err)
+	}
+	c.addCleanup("snapshot "+snapshotID, s.Snapshots, func() tasks.Result {
+		return snapshots.Delete(s.Snapshots, snapshotID)
+	})
+
+	tempVolumeID, err := c.wait(s.Volumes, "create volume from snapshot", volumes.Create(s.Volumes, volumes.CreateOpts{
+		Source:     volumes.Snapshot,
+		Name:       copyName,
+		Size:       volume.Size,
+		TypeName:   volume.VolumeType,
+		SnapshotID: snapshotID,
+	}), volumes.ExtractVolumeIDFromTask)
+	if err != nil {
+		return "", fmt.Errorf("cannot create volume from snapshot %s: %w", snapshotID, err)
+	}
+	c.addCleanup("volume "+tempVolumeID, s.Volumes, func() tasks.Result {
+		return volumes.Delete(s.Volumes, tempVolumeID, nil)
+	})
+
+	imageID, err := c.wait(s.Images, "create image", images.Create(s.Images, images.CreateOpts{
+		Name:     copyName,
+		Source:   imagetypes.ImageSourceVolume,
+		VolumeID: tempVolumeID,
+		OSType:   c.opts.OSType,
+		SshKey:   c.opts.SshKey,
+	}), images.ExtractImageIDFromTask)
+	if err != nil {
+		return "", fmt.Errorf("cannot create image from volume %s: %w", tempVolumeID, err)
+	}
+	if !c.opts.KeepImages {
+		c.addCleanup("image "+imageID, s.Images, func() tasks.Result {
+			return images.Delete(s.Images, imageID)
+		})
+	}
+
+	export, err := images.Export(s.Images, imageID).Extract()
+	if err != nil {
+		return "", fmt.Errorf("cannot export image %s: %w", imageID, err)
+	}
+	targetImageID, err := c.wait(t.ImageUpload, "upload image", images.Upload(t.ImageUpload, images.UploadOpts{
+		Name:   copyName,
+		URL:    export.URL,
+		OSType: c.opts.OSType,
+		SshKey: c.opts.SshKey,
+	}), images.ExtractImageIDFromTask)
+	if err != nil {
+		return "", fmt.Errorf("cannot upload image %s into region %d: %w", imageID, t.ImageUpload.RegionID, err)
+	}
+	if !c.opts.KeepImages {
+		c.addCleanup("image "+targetImageID, t.Images, func() tasks.Result {
+			return images.Delete(t.Images, targetImageID)
+		})
+	}
+
+	targetVolumeID, err := c.wait(t.Volumes, "create volume from image", volumes.Create(t.Volumes, volumes.CreateOpts{
+		Source:   volumes.Image,
+		Name:     name,
+		Size:     volume.Size,
+		TypeName: volume.VolumeType,
+		ImageID:  targetImageID,
+		Metadata: CopyMetadata(*volume),
+	}), volumes.ExtractVolumeIDFromTask)
+	if err != nil {
+		return "", fmt.Errorf("cannot create volume from image %s in region %d: %w", targetImageID, t.Volumes.RegionID, err)
+	}
+	return targetVolumeID, nil
+}
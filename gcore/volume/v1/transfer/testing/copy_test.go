@@ -0,0 +1,184 @@
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	gcorecloud "github.com/G-Core/gcorelabscloud-go"
+	"github.com/G-Core/gcorelabscloud-go/gcore"
+	"github.com/G-Core/gcorelabscloud-go/gcore/volume/v1/transfer"
+	th "github.com/G-Core/gcorelabscloud-go/testhelper"
+	fake "github.com/G-Core/gcorelabscloud-go/testhelper/client"
+)
+
+const targetRegionID = 2
+
+const CopySourceVolumeResponse = `
+{
+  "id": "726ecfcc-7fd0-4e30-a86e-7892524aa483",
+  "name": "db",
+  "volume_type": "ssd_hiiops",
+  "size": 20,
+  "status": "in-use",
+  "metadata_detailed": [
+    {"key": "env", "value": "prod", "read_only": false},
+    {"key": "task_id", "value": "d74c2bb9-cea7-4b23-a009-2f13518ae66d", "read_only": true}
+  ]
+}
+`
+
+const CopyImageExportResponse = `
+{
+  "url": "https://images.example.com/9b1f8a4e-5c2d-4e3f-8a7b-6c5d4e3f2a1b.raw",
+  "expires_at": "2020-09-14T15:45:30+0000"
+}
+`
+
+const TaskResponse = `
+{
+  "tasks": [
+    "50f53a35-42ed-40c4-82b2-5a37fb3e00bc"
+  ]
+}
+`
+
+const CopyTaskResponse = `
+{
+  "id": "50f53a35-42ed-40c4-82b2-5a37fb3e00bc",
+  "state": "FINISHED",
+  "task_type": "create_volume",
+  "client_id": 1,
+  "user_id": 1,
+  "user_client_id": 1,
+  "created_on": "2020-09-14T14:45:30",
+  "created_resources": {
+    "volumes": ["3f1e2d4c-5b6a-4798-8a9b-0c1d2e3f4a5b"],
+    "snapshots": ["c2d3e4f5-a6b7-4c8d-9e0f-1a2b3c4d5e6f"],
+    "images": ["9b1f8a4e-5c2d-4e3f-8a7b-6c5d4e3f2a1b"]
+  },
+  "error": null
+}
+`
+
+func regionClient(name string, regionID int) *gcorecloud.ServiceClient {
+	options := gcorecloud.TokenOptions{
+		APIURL:       th.Endpoint(),
+		AccessToken:  fake.AccessToken,
+		RefreshToken: fake.RefreshToken,
+		AllowReauth:  true,
+	}
+	client, err := gcore.TokenClientService(options, gcorecloud.EndpointOpts{
+		Name:    name,
+		Region:  regionID,
+		Project: fake.ProjectID,
+		Version: "v1",
+	})
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+func TestCopyToRegion(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	volumeID := "726ecfcc-7fd0-4e30-a86e-7892524aa483"
+	createdVolumeID := "3f1e2d4c-5b6a-4798-8a9b-0c1d2e3f4a5b"
+	snapshotID := "c2d3e4f5-a6b7-4c8d-9e0f-1a2b3c4d5e6f"
+	imageID := "9b1f8a4e-5c2d-4e3f-8a7b-6c5d4e3f2a1b"
+	calls := make(map[string]int)
+	respond := func(w http.ResponseWriter, status int, body string) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = fmt.Fprint(w, body)
+	}
+	handle := func(path string, handler func(w http.ResponseWriter, r *http.Request)) {
+		th.Mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			th.TestHeader(t, r, "Authorization", fmt.Sprintf("Bearer %s", fake.AccessToken))
+			calls[r.Method+" "+path]++
+			handler(w, r)
+		})
+	}
+	tasksResponse := func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, TaskResponse)
+	}
+
+	handle(fmt.Sprintf("/v1/volumes/%d/%d/%s", fake.ProjectID, fake.RegionID, volumeID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		respond(w, http.StatusOK, CopySourceVolumeResponse)
+	})
+	handle(fmt.Sprintf("/v1/snapshots/%d/%d", fake.ProjectID, fake.RegionID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestJSONRequest(t, r, `{"volume_id": "726ecfcc-7fd0-4e30-a86e-7892524aa483", "name": "db-copy-2"}`)
+		tasksResponse(w, r)
+	})
+	handle(fmt.Sprintf("/v1/volumes/%d/%d", fake.ProjectID, fake.RegionID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestJSONRequest(t, r, `{"source": "snapshot", "name": "db-copy-2", "size": 20, "type_name": "ssd_hiiops", "snapshot_id": "c2d3e4f5-a6b7-4c8d-9e0f-1a2b3c4d5e6f"}`)
+		tasksResponse(w, r)
+	})
+	handle(fmt.Sprintf("/v1/images/%d/%d", fake.ProjectID, fake.RegionID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestJSONRequest(t, r, `{"name": "db-copy-2", "source": "volume", "volume_id": "3f1e2d4c-5b6a-4798-8a9b-0c1d2e3f4a5b", "os_type": "linux", "ssh_key": "allow"}`)
+		tasksResponse(w, r)
+	})
+	handle(fmt.Sprintf("/v1/images/%d/%d/%s", fake.ProjectID, fake.RegionID, imageID), tasksResponse)
+	handle(fmt.Sprintf("/v1/images/%d/%d/%s/export", fake.ProjectID, fake.RegionID, imageID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		respond(w, http.StatusOK, CopyImageExportResponse)
+	})
+	handle(fmt.Sprintf("/v1/downloadimage/%d/%d", fake.ProjectID, targetRegionID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestJSONRequest(t, r, `{"name": "db-copy-2", "url": "https://images.example.com/9b1f8a4e-5c2d-4e3f-8a7b-6c5d4e3f2a1b.raw", "os_type": "linux", "ssh_key": "allow", "cow_format": false}`)
+		tasksResponse(w, r)
+	})
+	handle(fmt.Sprintf("/v1/volumes/%d/%d", fake.ProjectID, targetRegionID), func(w http.ResponseWriter, r *http.Request) {
+		th.TestJSONRequest(t, r, `{"source": "image", "name": "db", "size": 20, "type_name": "ssd_hiiops", "image_id": "9b1f8a4e-5c2d-4e3f-8a7b-6c5d4e3f2a1b", "metadata": {"env": "prod"}}`)
+		tasksResponse(w, r)
+	})
+	handle(fmt.Sprintf("/v1/images/%d/%d/%s", fake.ProjectID, targetRegionID, imageID), tasksResponse)
+	handle(fmt.Sprintf("/v1/volumes/%d/%d/%s", fake.ProjectID, fake.RegionID, createdVolumeID), tasksResponse)
+	handle(fmt.Sprintf("/v1/snapshots/%d/%d/%s", fake.ProjectID, fake.RegionID, snapshotID), tasksResponse)
+	th.Mux.HandleFunc(fmt.Sprintf("/v1/tasks/%s", "50f53a35-42ed-40c4-82b2-5a37fb3e00bc"), func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, CopyTaskResponse)
+	})
+
+	source := transfer.CopyClients{
+		Volumes:   regionClient("volumes", fake.RegionID),
+		Snapshots: regionClient("snapshots", fake.RegionID),
+		Images:    regionClient("images", fake.RegionID),
+	}
+	target := transfer.CopyClients{
+		Volumes:     regionClient("volumes", targetRegionID),
+		Images:      regionClient("images", targetRegionID),
+		ImageUpload: regionClient("downloadimage", targetRegionID),
+	}
+	var progress []string
+	opts := transfer.CopyOpts{
+		WaitSeconds: 10,
+		Progress: func(step transfer.CopyStep) {
+			progress = append(progress, fmt.Sprintf("%s@%d", step.Name, step.RegionID))
+		},
+	}
+
+	result, err := transfer.CopyToRegion(source, target, volumeID, opts)
+	require.NoError(t, err)
+	require.Equal(t, createdVolumeID, result.VolumeID)
+	require.Equal(t, targetRegionID, result.RegionID)
+	require.Equal(t, []string{
+		"create snapshot@1",
+		"create volume from snapshot@1",
+		"create image@1",
+		"upload image@2",
+		"create volume from image@2",
+		"delete image 9b1f8a4e-5c2d-4e3f-8a7b-6c5d4e3f2a1b@2",
+		"delete image 9b1f8a4e-5c2d-4e3f-8a7b-6c5d4e3f2a1b@1",
+		"delete volume 3f1e2d4c-5b6a-4798-8a9b-0c1d2e3f4a5b@1",
+		"delete snapshot c2d3e4f5-a6b7-4c8d-9e0f-1a2b3c4d5e6f@1",
+	}, progress)
+	require.Len(t, result.Steps, 9)
+	require.Equal(t, 1, calls[fmt.Sprintf("DELETE /v1/snapshots/%d/%d/%s", fake.ProjectID, fake.RegionID, snapshotID)])
+
+	_, err = transfer.CopyToRegion(source, transfer.CopyClients{Volumes: target.Volumes}, volumeID, opts)
+	require.Error(t, err)
+}
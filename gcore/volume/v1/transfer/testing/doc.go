@@ -0,0 +1,2 @@
+// transfer unit tests
+package testing
@@ -29,7 +29,11 @@ require (
 	k8s.io/client-go v0.18.14
 )
 
-require github.com/AlekSi/pointer v1.2.0
+require (
+	github.com/AlekSi/pointer v1.2.0
+	golang.org/x/net v0.21.0
+	golang.org/x/term v0.27.0
+)
 
 require (
 	github.com/cpuguy83/go-md2man/v2 v2.0.2 // indirect
@@ -46,9 +50,7 @@ require (
 	github.com/spf13/pflag v1.0.5 // indirect
 	github.com/xrash/smetrics v0.0.0-20201216005158-039620a65673 // indirect
 	golang.org/x/crypto v0.31.0 // indirect
-	golang.org/x/net v0.21.0 // indirect
 	golang.org/x/sys v0.28.0 // indirect
-	golang.org/x/term v0.27.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
 	gopkg.in/inf.v0 v0.9.1 // indirect
 	k8s.io/apimachinery v0.18.14 // indirect